@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"flag"
 	"fmt"
@@ -27,9 +28,53 @@ func main() {
 		port     = flag.String("port", "9200", "Elasticsearch 端口")
 		interval = flag.Int("interval", 2, "刷新间隔（秒）")
 		username = flag.String("user", "", "用户名（可选）")
-		password = flag.String("pass", "", "密码（可选）")
+		password = flag.String("pass", "", "密码（可选，支持 file:/path 或 env:NAME，指定 -user 时默认读取 $ES_PASSWORD）")
 		version  = flag.Bool("version", false, "显示版本信息")
 		readonly = flag.Bool("readonly", true, "只读模式（生产环境必须开启）")
+		cfgFile  = flag.String("config", "", "JSON 配置文件（端点策略、告警阈值等）")
+
+		cloudID       = flag.String("cloud-id", "", "Elastic Cloud ID（指定后忽略 -host/-port/-scheme）")
+		hosts         = flag.String("hosts", "", "种子节点列表，逗号分隔（如 es1:9200,es2:9200），指定后忽略 -host/-port")
+		sniff         = flag.Bool("sniff", false, "通过 /_nodes/http 自动发现集群节点")
+		sniffInterval = flag.Duration("sniff-interval", 5*time.Minute, "节点发现刷新间隔")
+
+		scheme        = flag.String("scheme", "http", "连接协议：http 或 https")
+		caCert        = flag.String("ca-cert", "", "自定义 CA 证书文件（PEM）")
+		clientCert    = flag.String("cert", "", "客户端证书文件（双向 TLS）")
+		clientKey     = flag.String("key", "", "客户端私钥文件（双向 TLS）")
+		insecure      = flag.Bool("insecure", false, "跳过 TLS 证书校验（仅限测试环境）")
+		caFingerprint = flag.String("ca-fingerprint", "", "CA 证书 SHA-256 指纹（ES 8 首次启动时输出）")
+
+		apiKey       = flag.String("api-key", "", "API Key（base64 或 id:key，支持 file:/path 或 env:NAME，未指定其他认证方式时默认读取 $ES_API_KEY）")
+		bearerToken  = flag.String("bearer-token", "", "Bearer 令牌（支持 file:/path 或 env:NAME，未指定其他认证方式时默认读取 $ES_BEARER_TOKEN）")
+		serviceToken = flag.String("service-token", "", "服务账号令牌（支持 file:/path 或 env:NAME，未指定其他认证方式时默认读取 $ES_SERVICE_TOKEN）")
+
+		proxy         = flag.String("proxy", "", "代理地址（http://host:port 或 socks5://host:port）")
+		sshTunnel     = flag.String("ssh-tunnel", "", "SSH 跳板机（user@host[:port]），经跳板机访问集群")
+		sshKey        = flag.String("ssh-key", "", "SSH 私钥文件")
+		sshKnownHosts = flag.String("ssh-known-hosts", "", "known_hosts 文件（默认 ~/.ssh/known_hosts）")
+
+		record      = flag.String("record", "", "录制目录：保存收到的每个响应，用于事后分析")
+		replay      = flag.String("replay", "", "回放目录：使用录制的响应运行监控界面，无需集群")
+		replaySpeed = flag.String("replay-speed", "1x", "回放速度：1x、10x 等倍速，或 step（按回车逐周期前进）")
+
+		auditLog        = flag.String("audit-log", "", "JSONL 审计日志路径（记录发往 ES 的每个请求）")
+		auditMaxSize    = flag.Int("audit-max-size", 100, "审计日志单文件大小上限（MB），超过后轮转")
+		auditMaxBackups = flag.Int("audit-max-backups", 5, "审计日志保留的历史文件数")
+
+		maxConcurrency = flag.Int("max-concurrency", config.DefaultSafetyConfig.MaxConcurrency, "最大并发请求数")
+		maxRPS         = flag.Float64("max-rps", config.DefaultSafetyConfig.RequestsPerSecond, "每秒最大请求数（0 表示不限制）")
+		maxRetries     = flag.Int("max-retries", config.DefaultSafetyConfig.MaxRetries, "失败请求最大重试次数")
+		maxResponse    = flag.Int64("max-response-size", config.DefaultSafetyConfig.MaxResponseBytes/1024/1024, "单个响应体大小上限（MB，0 表示不限制）")
+
+		shardFilter       = flag.String("shard-filter", "", "分片视图只显示匹配的索引（通配符，逗号分隔，如 logs-*,metrics）")
+		lifecycleInterval = flag.Duration("lifecycle-interval", time.Minute, "索引生命周期（_ilm/explain）刷新间隔，0 表示每个刷新周期都获取")
+
+		hotThreads       = flag.Bool("hot-threads", false, "按需采样热点线程：按刷新间隔采样 -hot-threads-window 次，输出聚合结果后退出")
+		hotThreadsEvery  = flag.Int("hot-threads-every", 0, "监控界面中每隔多少个刷新周期采样一次热点线程（0 表示不采样）")
+		hotThreadsWindow = flag.Int("hot-threads-window", 10, "热点线程聚合最近多少次采样")
+		hotThreadsNodes  = flag.String("hot-threads-nodes", "", "热点线程只采样指定节点（节点 ID 或名称，逗号分隔）")
+		hotThreadsFolded = flag.String("hot-threads-folded", "", "导出折叠调用栈文件（flamegraph.pl、speedscope 等火焰图工具可直接读取）")
 	)
 	flag.Parse()
 
@@ -46,17 +91,37 @@ func main() {
 		*readonly = true
 	}
 
-	// 如果第一个参数是 host:port 格式
-	if flag.NArg() > 0 {
-		addr := flag.Arg(0)
-		if h, p := parseAddress(addr); h != "" {
-			*host = h
-			if p != "" {
-				*port = p
-			}
+	// 解析凭据（支持从文件或环境变量读取，避免出现在进程参数中）
+	// 默认环境变量在确定认证方式后再读取，见 config.ApplyEnvCredentials
+	for _, secret := range []*string{password, apiKey, bearerToken, serviceToken} {
+		resolved, err := config.ResolveSecret(*secret)
+		if err != nil {
+			fmt.Printf("[错误] 解析凭据失败: %v\n", err)
+			os.Exit(1)
 		}
+		*secret = resolved
+	}
+	speed, err := config.ParseReplaySpeed(*replaySpeed)
+	if err != nil {
+		fmt.Printf("[错误] %v\n", err)
+		os.Exit(1)
 	}
 
+	// 请求调度限制（保证对集群的压力上限）
+	if *maxConcurrency < 1 {
+		fmt.Println("[错误] -max-concurrency 必须大于 0")
+		os.Exit(1)
+	}
+	if *hotThreadsEvery < 0 || *hotThreadsWindow < 1 {
+		fmt.Println("[错误] -hot-threads-every 不能为负数，-hot-threads-window 必须大于 0")
+		os.Exit(1)
+	}
+	safety := config.DefaultSafetyConfig
+	safety.MaxConcurrency = *maxConcurrency
+	safety.RequestsPerSecond = *maxRPS
+	safety.MaxRetries = *maxRetries
+	safety.MaxResponseBytes = *maxResponse * 1024 * 1024
+
 	// 创建配置
 	cfg := &config.Config{
 		Host:     *host,
@@ -65,25 +130,139 @@ func main() {
 		Password: *password,
 		Interval: time.Duration(*interval) * time.Second,
 		ReadOnly: *readonly,
+		Safety:   &safety,
+
+		Proxy:         *proxy,
+		SSHTunnel:     *sshTunnel,
+		SSHKey:        *sshKey,
+		SSHKnownHosts: *sshKnownHosts,
+
+		Record:      *record,
+		Replay:      *replay,
+		ReplaySpeed: speed,
+
+		AuditLog:        *auditLog,
+		AuditMaxSizeMB:  *auditMaxSize,
+		AuditMaxBackups: *auditMaxBackups,
+
+		Sniff:         *sniff,
+		SniffInterval: *sniffInterval,
+
+		Scheme:             *scheme,
+		CACert:             *caCert,
+		ClientCert:         *clientCert,
+		ClientKey:          *clientKey,
+		InsecureSkipVerify: *insecure,
+		CAFingerprint:      *caFingerprint,
+
+		APIKey:       *apiKey,
+		BearerToken:  *bearerToken,
+		ServiceToken: *serviceToken,
+
+		ShardFilter:       *shardFilter,
+		LifecycleInterval: *lifecycleInterval,
+
+		HotThreadsEvery:  *hotThreadsEvery,
+		HotThreadsWindow: *hotThreadsWindow,
+		HotThreadsNodes:  *hotThreadsNodes,
+		HotThreadsFolded: *hotThreadsFolded,
+	}
+
+	// 位置参数：host[:port]、[IPv6]:port、完整 URL 或 Cloud ID
+	if flag.NArg() > 0 {
+		if err := config.ApplyAddress(flag.Arg(0), cfg); err != nil {
+			fmt.Printf("[错误] %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *cloudID != "" {
+		if err := config.ApplyCloudID(*cloudID, cfg); err != nil {
+			fmt.Printf("[错误] %v\n", err)
+			os.Exit(1)
+		}
+	}
+	// 命令行和地址中都没有指定的凭据从默认环境变量读取
+	config.ApplyEnvCredentials(cfg)
+
+	if *cfgFile != "" {
+		if err := config.LoadFile(*cfgFile, cfg); err != nil {
+			fmt.Printf("[错误] %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *hosts != "" {
+		cfg.Hosts = strings.Split(*hosts, ",")
+	}
+
+	// 回放模式：不访问集群，按倍速缩短刷新间隔，使每次刷新对应录制中的一个周期
+	if cfg.Replay != "" {
+		cfg.Sniff = false
+		if cfg.ReplaySpeed > 0 {
+			cfg.Interval = time.Duration(float64(cfg.Interval) / cfg.ReplaySpeed)
+		}
+	}
+
+	if cfg.InsecureSkipVerify && cfg.CAFingerprint == "" {
+		fmt.Println("[警告] 已跳过 TLS 证书校验，仅限测试环境使用")
 	}
 
 	// 显示启动信息
 	fmt.Println("===========================================")
 	fmt.Printf("ES Monitor v%s\n", Version)
 	fmt.Println("生产环境安全监控工具")
+	fmt.Printf("请求限制: 并发 %d, 每秒 %.1f 次, 重试 %d 次\n",
+		safety.MaxConcurrency, safety.RequestsPerSecond, safety.MaxRetries)
 	fmt.Println("===========================================")
 
 	// 创建 ES 客户端
-	esClient := client.NewElasticsearchClient(cfg)
+	esClient, err := client.NewElasticsearchClient(cfg)
+	if err != nil {
+		fmt.Printf("[错误] 创建客户端失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// os.Exit 不执行 defer，先关闭客户端（写完审计日志、录制文件并断开 SSH 隧道）再退出
+	err = run(esClient, cfg, *hotThreads)
+	if closeErr := esClient.Close(); closeErr != nil {
+		fmt.Printf("[警告] 关闭客户端失败: %v\n", closeErr)
+	}
+	if err != nil {
+		fmt.Printf("[错误] %v\n", err)
+		os.Exit(1)
+	}
+}
 
+// run 连接集群并运行监控界面（或按需采样热点线程），直到收到退出信号
+func run(esClient *client.ElasticsearchClient, cfg *config.Config, hotThreads bool) error {
 	// 测试连接
-	fmt.Printf("正在连接 Elasticsearch: %s:%s ...\n", cfg.Host, cfg.Port)
+	_, _, total := esClient.ActiveEndpoint()
+	if cfg.Replay != "" {
+		fmt.Printf("正在加载录制 %s ...\n", cfg.Replay)
+	} else {
+		fmt.Printf("正在连接 Elasticsearch (%d 个种子节点) ...\n", total)
+	}
 	ctx := context.Background()
 	if err := esClient.Ping(ctx); err != nil {
-		fmt.Printf("[错误] 连接失败: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	active, _, _ := esClient.ActiveEndpoint()
+	fmt.Printf("[成功] 连接成功! 当前节点: %s (%s)\n", active, esClient.Capabilities())
+
+	// 节点发现
+	if cfg.Sniff {
+		if err := esClient.Sniff(ctx); err != nil {
+			fmt.Printf("[警告] %v，继续使用种子节点\n", err)
+		} else {
+			_, _, total = esClient.ActiveEndpoint()
+			fmt.Printf("[成功] 发现 %d 个 HTTP 节点\n", total)
+		}
+	}
+
+	// 按需采样热点线程后退出
+	if hotThreads {
+		return monitor.SampleHotThreads(ctx, esClient, cfg)
 	}
-	fmt.Println("[成功] 连接成功!")
 	time.Sleep(1 * time.Second)
 
 	// 创建监控器
@@ -97,7 +276,23 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go mon.Start(ctx)
+	// 回放单步模式：不定时刷新，每按一次回车前进一个采集周期
+	if esClient.Replayer() != nil && cfg.ReplaySpeed == 0 {
+		steps := make(chan struct{})
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				select {
+				case steps <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		go mon.StartStepping(ctx, steps)
+	} else {
+		go mon.Start(ctx)
+	}
 
 	// 等待退出信号
 	<-sigChan
@@ -109,13 +304,5 @@ func main() {
 	mon.Stop()
 	
 	fmt.Println("已安全退出")
-}
-
-func parseAddress(addr string) (host, port string) {
-	// 使用 strings 包
-	idx := strings.LastIndex(addr, ":")
-	if idx == -1 {
-		return addr, ""
-	}
-	return addr[:idx], addr[idx+1:]
+	return nil
 }
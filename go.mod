@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/fatih/color v1.16.0
 	github.com/shirou/gopsutil/v3 v3.22.5
+	golang.org/x/crypto v0.33.0
 )
 
 require (
@@ -17,5 +18,5 @@ require (
 	github.com/tklauser/go-sysconf v0.3.12 // indirect
 	github.com/tklauser/numcpus v0.6.1 // indirect
 	github.com/yusufpapurcu/wmi v1.2.3 // indirect
-	golang.org/x/sys v0.14.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 )
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// archiveFile 录制目录中的响应归档文件名（gzip 压缩的 JSONL）
+const archiveFile = "responses.jsonl.gz"
+
+// ArchiveEntry 录制的单个响应
+type ArchiveEntry struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"` // 不含路径前缀的请求 URI（含查询参数）
+	Status int       `json:"status"`
+	Body   string    `json:"body"` // 解压后的响应体
+}
+
+// archiveWriter 以追加方式写入响应归档
+// 每条记录后刷新 gzip 缓冲区，进程异常退出时已写入的记录仍可读取
+type archiveWriter struct {
+	mu   sync.Mutex
+	file *os.File
+	gz   *gzip.Writer
+}
+
+// newArchiveWriter 在目录中打开（或创建）响应归档
+func newArchiveWriter(dir string) (*archiveWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建录制目录失败: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, archiveFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("打开录制文件失败: %w", err)
+	}
+	// 追加时写入新的 gzip 成员，读取时按多成员流连续解压
+	return &archiveWriter{file: file, gz: gzip.NewWriter(file)}, nil
+}
+
+// write 写入一条记录
+func (w *archiveWriter) write(entry ArchiveEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.gz.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return w.gz.Flush()
+}
+
+// Close 关闭归档
+func (w *archiveWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.gz.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// readArchive 读取目录中的全部记录
+// 归档末尾不完整（录制进程被强制终止）时保留已读取的记录
+func readArchive(dir string) ([]ArchiveEntry, error) {
+	file, err := os.Open(filepath.Join(dir, archiveFile))
+	if err != nil {
+		return nil, fmt.Errorf("打开录制文件失败: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取录制文件失败: %w", err)
+	}
+	defer gz.Close()
+
+	var entries []ArchiveEntry
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+	for scanner.Scan() {
+		var entry ArchiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// 截断的最后一行
+			break
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("读取录制文件失败: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("录制文件中没有任何响应: %s", dir)
+	}
+	return entries, nil
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry 请求审计记录（每次实际发出的请求或被拒绝的请求各一条）
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Method    string    `json:"method"`
+	Host      string    `json:"host,omitempty"`
+	Endpoint  string    `json:"endpoint"`
+	Decision  string    `json:"decision"` // allow 或 deny
+	Rule      string    `json:"rule,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Status    int       `json:"status,omitempty"`
+	LatencyMs int64     `json:"latency_ms"`
+	Bytes     int64     `json:"bytes"`      // 解压后的响应大小
+	WireBytes int64     `json:"wire_bytes"` // 实际传输的字节数
+	Error     string    `json:"error,omitempty"`
+}
+
+// auditor 审计记录输出
+type auditor interface {
+	record(entry AuditEntry)
+}
+
+// nopAuditor 未配置审计日志时不记录
+// 被拒绝的请求由 denialLog 保存，在监控界面中显示，不写入标准错误，避免破坏全屏界面
+type nopAuditor struct{}
+
+func (nopAuditor) record(AuditEntry) {}
+
+// maxPendingDenials 两次刷新之间最多保留的被拒绝请求数
+const maxPendingDenials = 20
+
+// denialLog 尚未显示的被拒绝请求
+type denialLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+	dropped int // 超过上限未保留的请求数
+}
+
+func (l *denialLog) add(entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) >= maxPendingDenials {
+		l.dropped++
+		return
+	}
+	l.entries = append(l.entries, entry)
+}
+
+// take 取出尚未显示的被拒绝请求及超过上限未保留的数量
+func (l *denialLog) take() ([]AuditEntry, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, dropped := l.entries, l.dropped
+	l.entries, l.dropped = nil, 0
+	return entries, dropped
+}
+
+// writeFailures 尚未显示的文件写入失败（只保留最近一次错误和累计次数）
+type writeFailures struct {
+	mu    sync.Mutex
+	last  error
+	count int
+}
+
+func (f *writeFailures) add(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.last = err
+	f.count++
+}
+
+// take 取出上次调用以来的失败次数和最近一次错误
+func (f *writeFailures) take() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count, last := f.count, f.last
+	f.count, f.last = 0, nil
+	return count, last
+}
+
+// fileAuditor 将全部请求以 JSON 行写入文件，按大小轮转
+// 写入失败不输出到标准错误，而是记入 failures 在监控界面中显示
+type fileAuditor struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64 // <= 0 表示不轮转
+	maxBackups int
+	file       *os.File
+	size       int64
+	failures   *writeFailures
+}
+
+// newFileAuditor 创建文件审计器
+func newFileAuditor(path string, maxSize int64, maxBackups int, failures *writeFailures) (*fileAuditor, error) {
+	a := &fileAuditor{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		failures:   failures,
+	}
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// open 以追加方式打开日志文件
+func (a *fileAuditor) open() error {
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("读取审计日志状态失败: %w", err)
+	}
+	a.file = file
+	a.size = info.Size()
+	return nil
+}
+
+// rotate 轮转日志：path.N-1 -> path.N ... path -> path.1
+func (a *fileAuditor) rotate() error {
+	if err := a.file.Close(); err != nil {
+		return err
+	}
+
+	if a.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", a.path, a.maxBackups))
+		for i := a.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", a.path, i), fmt.Sprintf("%s.%d", a.path, i+1))
+		}
+		if err := os.Rename(a.path, a.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(a.path); err != nil {
+		return err
+	}
+	return a.open()
+}
+
+func (a *fileAuditor) record(entry AuditEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.failures.add(fmt.Errorf("编码审计记录失败: %w", err))
+		return
+	}
+	data = append(data, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return
+	}
+	if a.maxSize > 0 && a.size > 0 && a.size+int64(len(data)) > a.maxSize {
+		if err := a.rotate(); err != nil {
+			// 轮转失败时停止写入，避免无限增长
+			a.file = nil
+			a.failures.add(fmt.Errorf("轮转审计日志失败，已停止写入: %w", err))
+			return
+		}
+	}
+	n, err := a.file.Write(data)
+	a.size += int64(n)
+	if err != nil {
+		a.failures.add(fmt.Errorf("写入审计日志失败: %w", err))
+	}
+}
+
+// Close 关闭日志文件
+func (a *fileAuditor) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+func TestDenialsKeptForDisplay(t *testing.T) {
+	cl := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("被拒绝的请求不应发出: %s", r.URL)
+	})
+
+	if _, err := cl.request(context.Background(), "/_cluster/health?source=x"); err == nil {
+		t.Fatal("带 source 参数的请求应被拒绝")
+	}
+	denials, dropped := cl.TakeDenials()
+	if len(denials) != 1 || dropped != 0 {
+		t.Fatalf("被拒绝的请求 = %d (未保留 %d), 期望 1", len(denials), dropped)
+	}
+	if d := denials[0]; d.Decision != "deny" || d.Endpoint != "/_cluster/health?source=x" {
+		t.Errorf("审计记录 = %+v", d)
+	}
+	if denials, _ := cl.TakeDenials(); len(denials) != 0 {
+		t.Errorf("已取出的记录不应重复返回: %d", len(denials))
+	}
+
+	for i := 0; i < maxPendingDenials+5; i++ {
+		cl.request(context.Background(), "/_cluster/health?source=x")
+	}
+	if denials, dropped := cl.TakeDenials(); len(denials) != maxPendingDenials || dropped != 5 {
+		t.Errorf("保留 %d 条、丢弃 %d 条, 期望 %d 和 5", len(denials), dropped, maxPendingDenials)
+	}
+}
+
+// auditLines 读取审计日志文件中的记录
+func auditLines(t *testing.T, path string) []AuditEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取 %s 失败: %v", path, err)
+	}
+	var entries []AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("%s 中的记录无法解析: %v", path, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	failures := &writeFailures{}
+	a, err := newFileAuditor(path, 400, 2, failures)
+	if err != nil {
+		t.Fatalf("创建审计器失败: %v", err)
+	}
+	defer a.Close()
+
+	for i := 0; i < 30; i++ {
+		a.record(AuditEntry{Method: "GET", Endpoint: fmt.Sprintf("/_cat/indices?n=%02d", i), Decision: "allow"})
+	}
+
+	// 只保留 2 个历史文件，每个文件不超过大小上限，最新记录在当前文件末尾
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("缺少日志文件 %s: %v", name, err)
+		}
+		if info.Size() > 400 {
+			t.Errorf("%s 大小 = %d, 超过上限 400", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("超过保留数量的历史文件 %s.3 应被删除", path)
+	}
+	current := auditLines(t, path)
+	if last := current[len(current)-1].Endpoint; last != "/_cat/indices?n=29" {
+		t.Errorf("当前文件最后一条 = %s, 期望 /_cat/indices?n=29", last)
+	}
+	previous := auditLines(t, path+".1")
+	if last := previous[len(previous)-1].Endpoint; last != fmt.Sprintf("/_cat/indices?n=%02d", 29-len(current)) {
+		t.Errorf("%s.1 最后一条 = %s, 应紧接在当前文件之前", path, last)
+	}
+	if n, err := failures.take(); n != 0 {
+		t.Errorf("轮转过程中出现 %d 次写入失败: %v", n, err)
+	}
+}
+
+func TestAuditRotationWithoutBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	a, err := newFileAuditor(path, 200, 0, &writeFailures{})
+	if err != nil {
+		t.Fatalf("创建审计器失败: %v", err)
+	}
+	defer a.Close()
+
+	for i := 0; i < 10; i++ {
+		a.record(AuditEntry{Method: "GET", Endpoint: "/_cluster/health", Decision: "allow"})
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("不保留历史时目录中有 %d 个文件, 期望 1", len(files))
+	}
+}
+
+func TestAuditWriteFailuresReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	safety := config.DefaultSafetyConfig
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"green"}`))
+	}))
+	t.Cleanup(srv.Close)
+	cl, err := NewElasticsearchClient(&config.Config{
+		Hosts: []string{srv.URL}, Safety: &safety, ReadOnly: true, AuditLog: path, AuditMaxSizeMB: 1, AuditMaxBackups: 1,
+	})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	if _, err := cl.request(context.Background(), "/_cluster/health"); err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if n, err := cl.TakeAuditErrors(); n != 0 {
+		t.Fatalf("正常写入时报告了 %d 次失败: %v", n, err)
+	}
+
+	// 日志文件被外部关闭后，写入失败应保留到界面显示
+	cl.auditor.(*fileAuditor).file.Close()
+	for i := 0; i < 2; i++ {
+		cl.request(context.Background(), "/_cluster/health")
+	}
+	n, err := cl.TakeAuditErrors()
+	if n != 2 || err == nil || !strings.Contains(err.Error(), "写入审计日志失败") {
+		t.Errorf("写入失败 = %d 次 (%v), 期望 2 次写入审计日志失败", n, err)
+	}
+	if n, _ := cl.TakeAuditErrors(); n != 0 {
+		t.Errorf("已取出的失败不应重复返回: %d", n)
+	}
+}
+
+func TestAuditRotationFailureReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	failures := &writeFailures{}
+	a, err := newFileAuditor(path, 200, 1, failures)
+	if err != nil {
+		t.Fatalf("创建审计器失败: %v", err)
+	}
+	defer a.Close()
+
+	a.record(AuditEntry{Method: "GET", Endpoint: "/_cluster/health", Decision: "allow"})
+	// 日志文件被删除后轮转时重命名失败，停止写入并报告
+	os.Remove(path)
+	for i := 0; i < 5; i++ {
+		a.record(AuditEntry{Method: "GET", Endpoint: "/_cluster/health", Decision: "allow"})
+	}
+	n, err := failures.take()
+	if n != 1 || err == nil || !strings.Contains(err.Error(), "已停止写入") {
+		t.Errorf("轮转失败 = %d 次 (%v), 期望报告 1 次停止写入", n, err)
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// buildAuthHeader 根据配置生成 Authorization 请求头（未配置认证时返回空）
+func buildAuthHeader(cfg *config.Config) (string, error) {
+	methods := 0
+	for _, set := range []bool{
+		cfg.Username != "",
+		cfg.APIKey != "",
+		cfg.BearerToken != "",
+		cfg.ServiceToken != "",
+	} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return "", errors.New("认证方式只能指定一种（用户名密码 / API Key / Bearer 令牌 / 服务账号令牌）")
+	}
+
+	switch {
+	case cfg.APIKey != "":
+		return "ApiKey " + encodeAPIKey(cfg.APIKey), nil
+	case cfg.BearerToken != "":
+		return "Bearer " + cfg.BearerToken, nil
+	case cfg.ServiceToken != "":
+		// ES 服务账号令牌同样通过 Bearer 方案传递
+		return "Bearer " + cfg.ServiceToken, nil
+	case cfg.Username != "":
+		if cfg.Password == "" {
+			return "", errors.New("指定用户名时必须提供密码")
+		}
+		credentials := cfg.Username + ":" + cfg.Password
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)), nil
+	}
+	return "", nil
+}
+
+// encodeAPIKey 将 id:key 形式编码为 base64，已编码的值原样返回
+func encodeAPIKey(key string) string {
+	if strings.Contains(key, ":") {
+		return base64.StdEncoding.EncodeToString([]byte(key))
+	}
+	return key
+}
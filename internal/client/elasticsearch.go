@@ -1,11 +1,17 @@
 package client
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Y-vQv-Y/es-monitor/internal/config"
@@ -14,91 +20,443 @@ import (
 
 // ElasticsearchClient ES 客户端（生产环境安全版本）
 type ElasticsearchClient struct {
-	baseURL string
-	client  *http.Client
-	config  *config.Config
-	safety  *config.SafetyConfig
+	pool       *connectionPool
+	client     *http.Client
+	config     *config.Config
+	safety     *config.SafetyConfig
+	governor   *governor
+	policy     *policyEngine
+	auditor    auditor
+	denials    *denialLog     // 尚未显示的被拒绝请求
+	auditErrs  *writeFailures // 尚未显示的审计日志写入失败
+	recordErrs *writeFailures // 尚未显示的录制文件写入失败
+	authHeader string
+	tunnel     *sshTunnel     // SSH 隧道（未配置时为空）
+	recorder   *archiveWriter // 响应录制（未配置时为空）
+	replayer   *Replayer      // 响应回放（未配置时为空）
+	caps       atomic.Pointer[Capabilities]
+	lastSniff  atomic.Int64 // 上次节点发现时间（UnixNano）
+	sniffing   atomic.Bool
+	clock      func() time.Time // 时钟（为空时使用系统时间）
 }
 
 // NewElasticsearchClient 创建 ES 客户端
-func NewElasticsearchClient(cfg *config.Config) *ElasticsearchClient {
-	baseURL := fmt.Sprintf("http://%s:%s", cfg.Host, cfg.Port)
-	
-	// 使用安全的 HTTP 客户端配置
-	return &ElasticsearchClient{
-		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: config.DefaultSafetyConfig.RequestTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        10,
-				MaxIdleConnsPerHost: 5,
-				IdleConnTimeout:     30 * time.Second,
-				DisableKeepAlives:   false, // 保持连接复用，减少对服务器压力
-			},
-		},
-		config: cfg,
-		safety: &config.DefaultSafetyConfig,
-	}
-}
-
-// isEndpointAllowed 检查端点是否允许访问（生产环境安全检查）
-func (c *ElasticsearchClient) isEndpointAllowed(endpoint string) bool {
-	if !c.config.ReadOnly {
-		return false
+func NewElasticsearchClient(cfg *config.Config) (*ElasticsearchClient, error) {
+	if cfg.Scheme == "" {
+		cfg.Scheme = "http"
 	}
-	
-	for _, allowed := range c.safety.AllowedEndpoints {
-		if endpoint == allowed || len(endpoint) > len(allowed) && endpoint[:len(allowed)] == allowed {
-			return true
+	if cfg.Scheme != "http" && cfg.Scheme != "https" {
+		return nil, fmt.Errorf("不支持的协议: %s", cfg.Scheme)
+	}
+
+	seeds, err := seedURLs(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	authHeader, err := buildAuthHeader(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	safety := cfg.Safety
+	if safety == nil {
+		safety = &config.DefaultSafetyConfig
+	}
+
+	policy, err := newPolicyEngine(safety.Policy, cfg.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	// 审计：配置了审计日志时记录全部请求（被拒绝的请求另外通过 TakeDenials 在界面上显示）
+	var audit auditor = nopAuditor{}
+	auditErrs := &writeFailures{}
+	if cfg.AuditLog != "" {
+		audit, err = newFileAuditor(cfg.AuditLog, int64(cfg.AuditMaxSizeMB)*1024*1024, cfg.AuditMaxBackups, auditErrs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 使用安全的 HTTP 客户端配置（超时由调度器按端点控制）
+	transport := &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DisableKeepAlives:   false, // 保持连接复用，减少对服务器压力
+	}
+	tunnel, err := configureProxy(transport, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 录制与回放：在传输层替换或包装，请求仍经过策略、调度和审计
+	var roundTripper http.RoundTripper = transport
+	var recorder *archiveWriter
+	var replayer *Replayer
+	recordErrs := &writeFailures{}
+	switch {
+	case cfg.Replay != "" && cfg.Record != "":
+		return nil, fmt.Errorf("-record 与 -replay 不能同时使用")
+	case cfg.Replay != "":
+		replayer, err = NewReplayer(cfg.Replay, cfg.ReplaySpeed, cfg.PathPrefix)
+		if err != nil {
+			return nil, err
 		}
+		roundTripper = replayer
+	case cfg.Record != "":
+		recorder, err = newArchiveWriter(cfg.Record)
+		if err != nil {
+			return nil, err
+		}
+		roundTripper = &recordingTransport{next: transport, archive: recorder, prefix: cfg.PathPrefix, failures: recordErrs}
+	}
+
+	return &ElasticsearchClient{
+		pool:       newConnectionPool(seeds),
+		client:     &http.Client{Transport: roundTripper},
+		config:     cfg,
+		safety:     safety,
+		governor:   newGovernor(safety),
+		policy:     policy,
+		auditor:    audit,
+		denials:    &denialLog{},
+		auditErrs:  auditErrs,
+		recordErrs: recordErrs,
+		authHeader: authHeader,
+		tunnel:     tunnel,
+		recorder:   recorder,
+		replayer:   replayer,
+	}, nil
+}
+
+// ActiveEndpoint 返回当前使用的节点地址及存活节点数
+func (c *ElasticsearchClient) ActiveEndpoint() (active string, alive, total int) {
+	return c.pool.status()
+}
+
+// checkPolicy 按只读端点策略检查请求，拒绝时记录审计
+func (c *ElasticsearchClient) checkPolicy(method, endpoint string) (PolicyDecision, error) {
+	decision := c.policy.evaluate(method, endpoint)
+	if decision.Allowed {
+		return decision, nil
+	}
+
+	entry := AuditEntry{
+		Timestamp: time.Now(),
+		Method:    method,
+		Endpoint:  endpoint,
+		Decision:  "deny",
+		Reason:    decision.Reason,
 	}
-	return false
+	c.auditor.record(entry)
+	c.denials.add(entry)
+	return decision, fmt.Errorf("生产环境安全限制: 拒绝 %s %s (%s)", method, endpoint, decision.Reason)
 }
 
-// request 发送 HTTP 请求（只读安全版本）
+// TakeDenials 取出上次调用以来被只读策略拒绝的请求，以及超过保留上限未返回的数量
+func (c *ElasticsearchClient) TakeDenials() ([]AuditEntry, int) {
+	return c.denials.take()
+}
+
+// TakeAuditErrors 取出上次调用以来审计日志写入失败的次数和最近一次错误
+func (c *ElasticsearchClient) TakeAuditErrors() (int, error) {
+	return c.auditErrs.take()
+}
+
+// TakeRecordErrors 取出上次调用以来录制失败的次数和最近一次错误
+func (c *ElasticsearchClient) TakeRecordErrors() (int, error) {
+	return c.recordErrs.take()
+}
+
+// auditAttempt 记录一次实际发出的请求
+func (c *ElasticsearchClient) auditAttempt(ep *endpoint, endpoint string, decision PolicyDecision, start time.Time, size, wireBytes int64, err error) {
+	entry := AuditEntry{
+		Timestamp: start,
+		Method:    "GET",
+		Host:      ep.url.Host,
+		Endpoint:  endpoint,
+		Decision:  "allow",
+		Rule:      decision.Rule,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Bytes:     size,
+		WireBytes: wireBytes,
+	}
+
+	var statusErr *statusError
+	switch {
+	case err == nil:
+		entry.Status = http.StatusOK
+	case errors.As(err, &statusErr):
+		entry.Status = statusErr.code
+		entry.Error = err.Error()
+	default:
+		entry.Error = err.Error()
+	}
+	c.auditor.record(entry)
+}
+
+// Replayer 返回回放器（非回放模式为空）
+func (c *ElasticsearchClient) Replayer() *Replayer {
+	return c.replayer
+}
+
+// Now 返回当前时间（回放模式下为录制时间）
+func (c *ElasticsearchClient) Now() time.Time {
+	if c.replayer != nil {
+		return c.replayer.Now()
+	}
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// SetClock 设置时钟（连接模拟集群时使用集群的模拟时间），回放模式下仍按录制时间计算
+func (c *ElasticsearchClient) SetClock(now func() time.Time) {
+	c.clock = now
+}
+
+// Close 释放客户端资源（关闭审计日志、SSH 隧道和录制文件）
+func (c *ElasticsearchClient) Close() error {
+	var errs []error
+	if c.tunnel != nil {
+		errs = append(errs, c.tunnel.Close())
+	}
+	if c.recorder != nil {
+		errs = append(errs, c.recorder.Close())
+	}
+	if closer, ok := c.auditor.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// request 发送 HTTP 请求并读取完整响应体（适用于小响应）
 func (c *ElasticsearchClient) request(ctx context.Context, endpoint string) ([]byte, error) {
+	var body []byte
+	err := c.stream(ctx, endpoint, func(r io.Reader) error {
+		var err error
+		body, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// stream 发送 HTTP 请求（只读安全版本），响应体交给 consume 边读边处理
+// 节点连接失败时标记为不可用并立即切换节点；限流、服务暂不可用或全部节点失败时按退避策略重试
+// 每次重试都会重新调用 consume，consume 需要自行丢弃上一次的部分结果
+func (c *ElasticsearchClient) stream(ctx context.Context, endpoint string, consume func(io.Reader) error) error {
 	// 生产环境安全检查
-	if !c.isEndpointAllowed(endpoint) {
-		return nil, fmt.Errorf("生产环境安全限制: 不允许访问端点 %s", endpoint)
+	decision, err := c.checkPolicy("GET", endpoint)
+	if err != nil {
+		return err
 	}
-	
-	url := c.baseURL + endpoint
+
+	c.maybeSniff()
+
+	var (
+		lastErr      error
+		retries      int
+		connFailures int
+	)
+	for {
+		ep := c.pool.next()
+		start := time.Now()
+		size, wireBytes, err := c.doRequest(ctx, ep, endpoint, consume)
+		c.pool.record(ep, err)
+		c.auditAttempt(ep, endpoint, decision, start, size, wireBytes, err)
+		if err == nil {
+			c.pool.markAlive(ep)
+			return nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return err
+		}
+
+		// 连接失败（含超时）：隔离该节点，优先换下一个节点立即重试
+		var retryAfter time.Duration
+		var connErr *connectionError
+		var statusErr *statusError
+		switch {
+		case errors.As(err, &connErr):
+			c.pool.markDead(ep)
+			connFailures++
+			if connFailures < c.pool.size() {
+				continue
+			}
+		case errors.As(err, &statusErr) && statusErr.retryable():
+			retryAfter = statusErr.retryAfter
+			// 服务端要求的等待时间过长时放弃，而不是提前重试加重服务端压力
+			if retryAfter > 0 && !retryAfterAllowed(ctx, retryAfter, c.safety.RetryMaxDelay) {
+				return fmt.Errorf("服务端要求 %s 后重试，超过本次请求允许的等待时间: %w", retryAfter, lastErr)
+			}
+		default:
+			return lastErr
+		}
+
+		// 所有节点均失败或状态码可重试：退避后进入下一轮
+		if retries >= c.safety.MaxRetries {
+			return lastErr
+		}
+		delay := backoffDelay(c.safety.RetryBaseDelay, c.safety.RetryMaxDelay, retries, retryAfter)
+		retries++
+		connFailures = 0
+		if err := sleepContext(ctx, delay); err != nil {
+			return lastErr
+		}
+	}
+}
+
+// EndpointStatuses 返回各节点的请求统计和最近错误
+func (c *ElasticsearchClient) EndpointStatuses() []model.EndpointStatus {
+	return c.pool.statuses()
+}
+
+// connectionError 节点连接层错误（触发故障切换）
+type connectionError struct {
+	host string
+	err  error
+}
+
+func (e *connectionError) Error() string {
+	return fmt.Sprintf("请求失败 (%s): %v", e.host, e.err)
+}
+
+func (e *connectionError) Unwrap() error {
+	return e.err
+}
+
+// doRequest 向指定节点发送单次请求（受并发数、请求速率和端点超时约束）
+// 返回解压后的响应体字节数和实际传输的字节数
+func (c *ElasticsearchClient) doRequest(ctx context.Context, ep *endpoint, endpoint string, consume func(io.Reader) error) (int64, int64, error) {
+	release, err := c.governor.acquire(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(ctx, timeoutFor(c.safety, endpoint))
+	defer cancel()
+
+	url := strings.TrimRight(ep.url.String(), "/") + endpoint
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
 	// 添加认证
-	if c.config.Username != "" && c.config.Password != "" {
-		req.SetBasicAuth(c.config.Username, c.config.Password)
+	if c.authHeader != "" {
+		req.Header.Set("Authorization", c.authHeader)
 	}
 	
 	// 添加安全请求头
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "ES-Monitor/1.0 (ReadOnly)")
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
+		return 0, 0, &connectionError{host: ep.url.Host, err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP 状态码: %d", resp.StatusCode)
+		return 0, 0, &statusError{
+			code:       resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+	// 统计实际传输字节数，按需解压
+	wire := &countingReader{r: resp.Body}
+	var reader io.Reader = wire
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(wire)
+		if err != nil {
+			return 0, wire.n, fmt.Errorf("解压响应失败: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
 	}
 
-	return body, nil
+	// 限制解压后的响应体大小，避免异常大的响应耗尽内存
+	body := &limitedReader{r: reader, limit: c.safety.MaxResponseBytes}
+	if err := consume(body); err != nil {
+		return body.n, wire.n, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	return body.n, wire.n, nil
+}
+
+// countingReader 统计读取字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
 }
 
-// Ping 测试连接（安全操作）
+// Ping 测试连接并识别集群版本（安全操作）
 func (c *ElasticsearchClient) Ping(ctx context.Context) error {
-	_, err := c.request(ctx, "/")
-	return err
+	data, err := c.request(ctx, "/")
+	if err != nil {
+		return err
+	}
+
+	var info model.ClusterInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return fmt.Errorf("解析失败: %w", err)
+	}
+	caps, err := detectCapabilities(&info)
+	if err != nil {
+		return err
+	}
+	c.caps.Store(&caps)
+	return nil
+}
+
+// Capabilities 返回集群版本能力（Ping 成功前为空值，即所有可选功能视为不支持）
+func (c *ElasticsearchClient) Capabilities() Capabilities {
+	if caps := c.caps.Load(); caps != nil {
+		return *caps
+	}
+	return Capabilities{}
+}
+
+// GetHealthReport 获取健康报告（只读操作，ES 8.7+）
+func (c *ElasticsearchClient) GetHealthReport(ctx context.Context) (*model.HealthReport, error) {
+	if !c.Capabilities().HealthReport {
+		return nil, &UnsupportedError{Feature: "健康报告", Requirement: "Elasticsearch 8.7+"}
+	}
+
+	data, err := c.request(ctx, "/_health_report?verbose=false")
+	if err != nil {
+		return nil, err
+	}
+
+	var report model.HealthReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return &report, nil
 }
 
 // GetClusterHealth 获取集群健康状态（只读操作）
@@ -116,29 +474,305 @@ func (c *ElasticsearchClient) GetClusterHealth(ctx context.Context) (*model.Clus
 	return &health, nil
 }
 
-// GetNodeStats 获取节点统计（只读操作）
-func (c *ElasticsearchClient) GetNodeStats(ctx context.Context) (*model.NodeStats, error) {
-	data, err := c.request(ctx, "/_nodes/stats")
+// _nodes/stats 和 _stats 的指标选择器，与模型声明的字段保持一致
+const (
+	// 节点级指标
+	nodeBaseMetrics = "jvm,os,process,fs,transport,http,indices,thread_pool,breaker"
+	// 节点 indices 指标下的子指标
+	nodeIndexMetrics = "docs,store,indexing,search,segments,translog,merge,refresh,flush,fielddata,query_cache,request_cache"
+	// 索引统计指标
+	indexStatsMetrics = "docs,store,indexing,search,segments,translog,merge,refresh,flush,fielddata,query_cache,request_cache"
+)
+
+// GetNodeStats 获取节点统计（只读操作），metrics 由 Capabilities.NodeStatsMetrics 按版本选择
+func (c *ElasticsearchClient) GetNodeStats(ctx context.Context, metrics string) (*model.NodeStats, error) {
+	endpoint := fmt.Sprintf("/_nodes/stats/%s?filter_path=%s", metrics, filterPath(model.NodeStats{}))
+
+	// 逐个节点解码，避免整个响应体驻留内存
+	var stats model.NodeStats
+	err := c.stream(ctx, endpoint, func(r io.Reader) error {
+		stats.Nodes = make(map[string]model.NodeStat)
+		return decodeEntries(r, "nodes", func(id string, node model.NodeStat) {
+			stats.Nodes[id] = node
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetIndexStats 获取索引统计（只读操作），metrics 由 Capabilities.IndexStatsMetrics 按版本选择
+func (c *ElasticsearchClient) GetIndexStats(ctx context.Context, metrics string) (*model.IndexStats, error) {
+	endpoint := fmt.Sprintf("/_stats/%s?level=indices&filter_path=%s", metrics, filterPath(model.IndexStats{}))
+
+	// 逐个索引解码，避免整个响应体驻留内存
+	var stats model.IndexStats
+	err := c.stream(ctx, endpoint, func(r io.Reader) error {
+		stats.Indices = make(map[string]model.IndexStat)
+		return decodeEntries(r, "indices", func(name string, index model.IndexStat) {
+			stats.Indices[name] = index
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var stats model.NodeStats
-	if err := json.Unmarshal(data, &stats); err != nil {
+	return &stats, nil
+}
+
+// GetCatIndices 获取索引列表（只读操作）
+func (c *ElasticsearchClient) GetCatIndices(ctx context.Context) ([]model.IndexInfo, error) {
+	data, err := c.request(ctx, "/_cat/indices?format=json&bytes=b&h="+catColumns(model.IndexInfo{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var indices []model.IndexInfo
+	if err := json.Unmarshal(data, &indices); err != nil {
 		return nil, fmt.Errorf("解析失败: %w", err)
 	}
 
-	return &stats, nil
+	return indices, nil
 }
 
-// GetIndexStats 获取索引统计（只读操作）
-func (c *ElasticsearchClient) GetIndexStats(ctx context.Context) (*model.IndexStats, error) {
-	data, err := c.request(ctx, "/_stats")
+// GetCatShards 获取分片列表（只读操作），pattern 为索引通配符（逗号分隔），为空时返回全部索引
+func (c *ElasticsearchClient) GetCatShards(ctx context.Context, pattern string) ([]model.ShardInfo, error) {
+	endpoint := "/_cat/shards"
+	if pattern != "" {
+		endpoint += "/" + url.PathEscape(pattern)
+	}
+	data, err := c.request(ctx, endpoint+"?format=json&bytes=b&h="+catColumns(model.ShardInfo{}))
 	if err != nil {
 		return nil, err
 	}
 
-	var stats model.IndexStats
+	var shards []model.ShardInfo
+	if err := json.Unmarshal(data, &shards); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return shards, nil
+}
+
+// ExplainAllocation 获取分片分配诊断（只读操作）
+// shard 为空时由 ES 解释第一个未分配分片；指定分片需要 ES 8.14+（此前只能通过请求体指定）
+func (c *ElasticsearchClient) ExplainAllocation(ctx context.Context, shard *model.ShardInfo) (*model.AllocationExplain, error) {
+	query := url.Values{}
+	query.Set("filter_path", filterPath(model.AllocationExplain{}))
+	if shard != nil {
+		if !c.Capabilities().AllocationExplainParams {
+			return nil, &UnsupportedError{Feature: "按分片查询分配诊断", Requirement: "Elasticsearch 8.14+"}
+		}
+		query.Set("index", shard.Index)
+		query.Set("shard", shard.Shard)
+		query.Set("primary", strconv.FormatBool(shard.Primary()))
+	}
+
+	data, err := c.request(ctx, "/_cluster/allocation/explain?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var explain model.AllocationExplain
+	if err := json.Unmarshal(data, &explain); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return &explain, nil
+}
+
+// GetPendingTasks 获取主节点待处理的集群任务（只读操作）
+func (c *ElasticsearchClient) GetPendingTasks(ctx context.Context) (*model.PendingTasks, error) {
+	data, err := c.request(ctx, "/_cluster/pending_tasks?filter_path="+filterPath(model.PendingTasks{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks model.PendingTasks
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return &tasks, nil
+}
+
+// GetTasks 获取集群中正在运行的任务，子任务按父任务分组（只读操作）
+func (c *ElasticsearchClient) GetTasks(ctx context.Context) (*model.Tasks, error) {
+	data, err := c.request(ctx, "/_tasks?detailed=true&group_by=parents&filter_path="+filterPath(model.Tasks{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks model.Tasks
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return &tasks, nil
+}
+
+// lifecycleTargets 生命周期查询的索引表达式：* 不匹配隐藏索引，数据流后备索引（.ds-*）需要单独列出
+const lifecycleTargets = "*,.ds-*"
+
+// GetILMExplain 获取索引的 ILM 执行状态（只读操作，ES 6.6+）
+// OpenSearch 使用 ISM，执行状态转换为 ILM 的阶段/动作/步骤
+func (c *ElasticsearchClient) GetILMExplain(ctx context.Context) (*model.ILMExplain, error) {
+	caps := c.Capabilities()
+	if caps.ISM {
+		return c.getISMExplain(ctx)
+	}
+	if !caps.ILM {
+		return nil, &UnsupportedError{Feature: "索引生命周期管理", Requirement: "Elasticsearch 6.6+"}
+	}
+
+	endpoint := "/" + lifecycleTargets + "/_ilm/explain?filter_path=" + filterPath(model.ILMExplain{})
+
+	// 逐个索引解码，避免整个响应体驻留内存
+	var explain model.ILMExplain
+	err := c.stream(ctx, endpoint, func(r io.Reader) error {
+		explain.Indices = make(map[string]model.ILMIndex)
+		return decodeEntries(r, "indices", func(name string, index model.ILMIndex) {
+			explain.Indices[name] = index
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &explain, nil
+}
+
+// getISMExplain 获取 OpenSearch 索引的 ISM 执行状态（只读操作）
+func (c *ElasticsearchClient) getISMExplain(ctx context.Context) (*model.ILMExplain, error) {
+	data, err := c.request(ctx, "/_plugins/_ism/explain/"+lifecycleTargets+"?filter_path="+filterPath(model.ISMExplain{}))
+	if err != nil {
+		return nil, err
+	}
+
+	// 响应顶层除索引外还有 total_managed_indices 计数
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+	explain := &model.ILMExplain{Indices: make(map[string]model.ILMIndex, len(raw))}
+	for name, value := range raw {
+		if len(value) == 0 || value[0] != '{' {
+			continue
+		}
+		var index model.ISMIndex
+		if err := json.Unmarshal(value, &index); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", name, err)
+		}
+		explain.Indices[name] = ismToILM(name, index)
+	}
+
+	return explain, nil
+}
+
+// ismToILM 将 ISM 执行状态转换为 ILM 形式：状态对应阶段，失败的动作对应 ERROR 步骤
+func ismToILM(name string, ism model.ISMIndex) model.ILMIndex {
+	idx := model.ILMIndex{
+		Index:                   name,
+		Managed:                 ism.PolicyID != "",
+		Policy:                  ism.PolicyID,
+		IndexCreationDateMillis: ism.IndexCreationDate,
+	}
+	if ism.State != nil {
+		idx.Phase = ism.State.Name
+		idx.PhaseTimeMillis = ism.State.StartTime
+	}
+	failed := false
+	if ism.Action != nil {
+		idx.Action = ism.Action.Name
+		idx.ActionTimeMillis = ism.Action.StartTime
+		idx.FailedStepRetryCount = ism.Action.ConsumedRetries
+		failed = ism.Action.Failed
+	}
+	if ism.Step != nil {
+		idx.Step = ism.Step.Name
+		idx.StepTimeMillis = ism.Step.StartTime
+		failed = failed || ism.Step.StepStatus == "failed"
+	}
+	if failed {
+		idx.FailedStep = idx.Step
+		idx.Step = "ERROR"
+	}
+	if info := ism.Info; info != nil && (info.Message != "" || info.Cause != "") {
+		idx.StepInfo = &model.ILMStepInfo{Message: info.Message}
+		if info.Cause != "" {
+			idx.StepInfo.Message = strings.TrimPrefix(info.Message+": "+info.Cause, ": ")
+		}
+	}
+	return idx
+}
+
+// GetDataStreamLifecycle 获取数据流后备索引的生命周期状态（只读操作，ES 8.11+）
+func (c *ElasticsearchClient) GetDataStreamLifecycle(ctx context.Context) (*model.DataStreamLifecycleExplain, error) {
+	if !c.Capabilities().DataStreamLifecycle {
+		return nil, &UnsupportedError{Feature: "数据流生命周期", Requirement: "Elasticsearch 8.11+"}
+	}
+
+	endpoint := "/" + lifecycleTargets + "/_lifecycle/explain?filter_path=" + filterPath(model.DataStreamLifecycleExplain{})
+
+	var explain model.DataStreamLifecycleExplain
+	err := c.stream(ctx, endpoint, func(r io.Reader) error {
+		explain.Indices = make(map[string]model.DataStreamLifecycleIndex)
+		return decodeEntries(r, "indices", func(name string, index model.DataStreamLifecycleIndex) {
+			explain.Indices[name] = index
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &explain, nil
+}
+
+// slmSupported 检查集群是否支持快照生命周期管理
+// OpenSearch 的 Snapshot Management（_plugins/_sm）策略和统计格式不同，暂不支持
+func slmSupported(caps Capabilities) error {
+	if caps.Distribution == DistributionOpenSearch {
+		return &UnsupportedError{Feature: "快照生命周期管理", Requirement: "Elasticsearch 7.4+，OpenSearch 的 Snapshot Management 暂不支持"}
+	}
+	if !caps.SLM {
+		return &UnsupportedError{Feature: "快照生命周期管理", Requirement: "Elasticsearch 7.4+"}
+	}
+	return nil
+}
+
+// GetSLMPolicies 获取快照生命周期策略及其最近执行情况（只读操作，ES 7.4+）
+func (c *ElasticsearchClient) GetSLMPolicies(ctx context.Context) (model.SLMPolicies, error) {
+	if err := slmSupported(c.Capabilities()); err != nil {
+		return nil, err
+	}
+
+	data, err := c.request(ctx, "/_slm/policy?filter_path="+filterPath(model.SLMPolicies{}))
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(model.SLMPolicies)
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return policies, nil
+}
+
+// GetSLMStats 获取快照生命周期管理的累计统计（只读操作，ES 7.4+）
+func (c *ElasticsearchClient) GetSLMStats(ctx context.Context) (*model.SLMStats, error) {
+	if err := slmSupported(c.Capabilities()); err != nil {
+		return nil, err
+	}
+
+	data, err := c.request(ctx, "/_slm/stats?filter_path="+filterPath(model.SLMStats{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var stats model.SLMStats
 	if err := json.Unmarshal(data, &stats); err != nil {
 		return nil, fmt.Errorf("解析失败: %w", err)
 	}
@@ -146,17 +780,17 @@ func (c *ElasticsearchClient) GetIndexStats(ctx context.Context) (*model.IndexSt
 	return &stats, nil
 }
 
-// GetCatIndices 获取索引列表（只读操作）
-func (c *ElasticsearchClient) GetCatIndices(ctx context.Context) ([]model.IndexInfo, error) {
-	data, err := c.request(ctx, "/_cat/indices?format=json&bytes=b")
+// GetSnapshotStatus 获取所有仓库中正在执行的快照进度（只读操作，不含各索引和分片明细）
+func (c *ElasticsearchClient) GetSnapshotStatus(ctx context.Context) (*model.SnapshotStatus, error) {
+	data, err := c.request(ctx, "/_snapshot/_status?filter_path="+filterPath(model.SnapshotStatus{}))
 	if err != nil {
 		return nil, err
 	}
 
-	var indices []model.IndexInfo
-	if err := json.Unmarshal(data, &indices); err != nil {
+	var status model.SnapshotStatus
+	if err := json.Unmarshal(data, &status); err != nil {
 		return nil, fmt.Errorf("解析失败: %w", err)
 	}
 
-	return indices, nil
+	return &status, nil
 }
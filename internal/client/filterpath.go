@@ -0,0 +1,125 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// maxFilterPathLength filter_path 参数长度上限
+// ES 默认 http.max_initial_line_length 为 4KB，需要为路径和其他参数留出余量
+const maxFilterPathLength = 2048
+
+// filterPathCache 按类型缓存生成的 filter_path
+var filterPathCache sync.Map
+
+// filterPath 根据模型声明的 json 字段生成 filter_path 参数值
+// map 的键用 * 匹配，切片和指针按元素类型展开，优先精确到叶子字段；
+// 超过长度上限时逐级截断到上层对象，保证请求行不超限
+func filterPath(v interface{}) string {
+	t := reflect.TypeOf(v)
+	if cached, ok := filterPathCache.Load(t); ok {
+		return cached.(string)
+	}
+
+	var paths []string
+	collectPaths(t, "", &paths)
+
+	depth := 0
+	for _, p := range paths {
+		if n := strings.Count(p, ".") + 1; n > depth {
+			depth = n
+		}
+	}
+	result := strings.Join(paths, ",")
+	for len(result) > maxFilterPathLength && depth > 1 {
+		depth--
+		result = strings.Join(truncatePaths(paths, depth), ",")
+	}
+
+	filterPathCache.Store(t, result)
+	return result
+}
+
+// truncatePaths 将路径截断到指定层数并去重（保持原有顺序）
+func truncatePaths(paths []string, depth int) []string {
+	seen := make(map[string]bool, len(paths))
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		parts := strings.Split(p, ".")
+		if len(parts) > depth {
+			p = strings.Join(parts[:depth], ".")
+		}
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// collectPaths 递归收集叶子字段路径
+func collectPaths(t reflect.Type, prefix string, paths *[]string) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		collectPaths(t.Elem(), joinPath(prefix, "*"), paths)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			// 没有 json 标签的嵌入结构体与 encoding/json 一致，字段提升到当前层
+			if f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+				collectPaths(f.Type, prefix, paths)
+				continue
+			}
+			name := jsonName(f)
+			if name == "" {
+				continue
+			}
+			collectPaths(f.Type, joinPath(prefix, name), paths)
+		}
+	default:
+		if prefix != "" {
+			*paths = append(*paths, prefix)
+		}
+	}
+}
+
+// catColumns 根据 _cat 模型的 json 字段生成 h 参数值
+func catColumns(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			columns = append(columns, name)
+		}
+	}
+	return strings.Join(columns, ",")
+}
+
+// jsonName 返回字段的 json 名称，未导出或忽略的字段返回空
+func jsonName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" || tag == "" {
+		return ""
+	}
+	return strings.Split(tag, ",")[0]
+}
+
+// joinPath 拼接 filter_path 段
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type filterPathLeaf struct {
+	Count int64 `json:"count"`
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+type filterPathEmbedded struct {
+	Uptime int64 `json:"uptime"`
+}
+
+type filterPathNode struct {
+	filterPathEmbedded
+	Name     string                    `json:"name"`
+	Roles    []string                  `json:"roles,omitempty"`
+	Docs     filterPathLeaf            `json:"docs"`
+	Pools    map[string]filterPathLeaf `json:"thread_pool"`
+	Shards   []*filterPathLeaf         `json:"shards"`
+	Ignored  string                    `json:"-"`
+	NoTag    string
+	internal string
+}
+
+type filterPathResponse struct {
+	ClusterName string                    `json:"cluster_name"`
+	Nodes       map[string]filterPathNode `json:"nodes"`
+}
+
+func TestFilterPath(t *testing.T) {
+	want := strings.Join([]string{
+		"cluster_name",
+		"nodes.*.uptime",
+		"nodes.*.name",
+		"nodes.*.roles",
+		"nodes.*.docs.count",
+		"nodes.*.docs.bytes",
+		"nodes.*.thread_pool.*.count",
+		"nodes.*.thread_pool.*.bytes",
+		"nodes.*.shards.count",
+		"nodes.*.shards.bytes",
+	}, ",")
+	if got := filterPath(&filterPathResponse{}); got != want {
+		t.Errorf("filterPath = %s\n期望 %s", got, want)
+	}
+	// 指针与值类型各自缓存，结果一致
+	if got := filterPath(filterPathResponse{}); got != want {
+		t.Errorf("值类型 filterPath = %s\n期望 %s", got, want)
+	}
+}
+
+// longFieldType 生成 nodes.*.<n 个长字段名>.current_value_in_bytes 结构
+func longFieldType(n int) reflect.Type {
+	leaf := reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: reflect.TypeOf(int64(0)), Tag: `json:"current_value_in_bytes"`},
+	})
+	fields := make([]reflect.StructField, 0, n)
+	for i := 0; i < n; i++ {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("LongFieldName%02d", i),
+			Type: leaf,
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"long_field_name_%02d"`, i)),
+		})
+	}
+	node := reflect.StructOf(fields)
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Nodes", Type: reflect.MapOf(reflect.TypeOf(""), node), Tag: `json:"nodes"`},
+	})
+}
+
+func TestFilterPathTruncation(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields int
+		depth  int // 截断后的路径层数
+	}{
+		{"未超限保留叶子字段", 10, 4},
+		{"超限截断到上层对象", 60, 3},
+		{"仍超限继续截断", 200, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterPath(reflect.New(longFieldType(tt.fields)).Interface())
+			if len(got) > maxFilterPathLength {
+				t.Errorf("filter_path 长度 = %d, 超过上限 %d", len(got), maxFilterPathLength)
+			}
+			paths := strings.Split(got, ",")
+			for _, p := range paths {
+				if n := strings.Count(p, ".") + 1; n != tt.depth {
+					t.Fatalf("路径 %s 层数 = %d, 期望 %d", p, n, tt.depth)
+				}
+			}
+			wantPaths := tt.fields
+			if tt.depth == 2 {
+				wantPaths = 1
+			}
+			if len(paths) != wantPaths {
+				t.Errorf("路径数 = %d, 期望 %d（截断后去重）", len(paths), wantPaths)
+			}
+		})
+	}
+}
+
+type catRow struct {
+	Index     string `json:"index"`
+	DocsCount string `json:"docs.count"`
+	StoreSize string `json:"store.size,omitempty"`
+	Skipped   string `json:"-"`
+	NoTag     string
+}
+
+func TestCatColumns(t *testing.T) {
+	want := "index,docs.count,store.size"
+	for _, v := range []interface{}{catRow{}, &catRow{}, []catRow{}, &[]catRow{}} {
+		if got := catColumns(v); got != want {
+			t.Errorf("catColumns(%T) = %s, 期望 %s", v, got, want)
+		}
+	}
+}
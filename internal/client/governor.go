@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// governor 请求调度器：限制并发数和全局每秒请求数，保证监控对集群的压力有硬上限
+type governor struct {
+	sem chan struct{}
+
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数，<= 0 表示不限速
+	burst  float64 // 令牌桶容量
+	tokens float64
+	last   time.Time
+}
+
+// newGovernor 根据安全配置创建调度器
+func newGovernor(safety *config.SafetyConfig) *governor {
+	concurrency := safety.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	burst := float64(safety.RequestBurst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &governor{
+		sem:    make(chan struct{}, concurrency),
+		rate:   safety.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// acquire 获取一个并发槽位和一个请求令牌，返回释放函数
+func (g *governor) acquire(ctx context.Context) (func(), error) {
+	select {
+	case g.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-g.sem }
+
+	if err := g.waitToken(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// waitToken 等待令牌桶中有可用令牌
+func (g *governor) waitToken(ctx context.Context) error {
+	if g.rate <= 0 {
+		return nil
+	}
+
+	for {
+		g.mu.Lock()
+		now := time.Now()
+		g.tokens += now.Sub(g.last).Seconds() * g.rate
+		if g.tokens > g.burst {
+			g.tokens = g.burst
+		}
+		g.last = now
+
+		if g.tokens >= 1 {
+			g.tokens--
+			g.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - g.tokens) / g.rate * float64(time.Second))
+		g.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// timeoutFor 返回端点对应的超时时间（按最长前缀匹配，未配置时使用默认超时）
+func timeoutFor(safety *config.SafetyConfig, endpoint string) time.Duration {
+	path := endpoint
+	if idx := strings.IndexByte(path, '?'); idx != -1 {
+		path = path[:idx]
+	}
+
+	timeout := safety.RequestTimeout
+	matched := -1
+	for prefix, d := range safety.EndpointTimeouts {
+		if strings.HasPrefix(path, prefix) && len(prefix) > matched {
+			timeout = d
+			matched = len(prefix)
+		}
+	}
+	return timeout
+}
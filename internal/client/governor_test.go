@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+func TestGovernorConcurrencyLimit(t *testing.T) {
+	g := newGovernor(&config.SafetyConfig{MaxConcurrency: 3})
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := g.acquire(context.Background())
+			if err != nil {
+				t.Errorf("获取槽位失败: %v", err)
+				return
+			}
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			release()
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p > 3 {
+		t.Errorf("最大并发 = %d, 超过上限 3", p)
+	}
+}
+
+func TestGovernorRateLimit(t *testing.T) {
+	g := newGovernor(&config.SafetyConfig{MaxConcurrency: 10, RequestsPerSecond: 20, RequestBurst: 2})
+
+	// 突发容量 2 立即放行，其余 10 个请求按 20 rps 需要约 500ms
+	start := time.Now()
+	for i := 0; i < 12; i++ {
+		release, err := g.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("第 %d 次获取令牌失败: %v", i+1, err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("12 个请求耗时 %s, 期望约 500ms", elapsed)
+	}
+}
+
+func TestGovernorUnlimitedRate(t *testing.T) {
+	g := newGovernor(&config.SafetyConfig{MaxConcurrency: 1})
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		release, err := g.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("获取槽位失败: %v", err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("未限速时 100 个请求耗时 %s", elapsed)
+	}
+}
+
+func TestGovernorContextCancel(t *testing.T) {
+	t.Run("等待并发槽位", func(t *testing.T) {
+		g := newGovernor(&config.SafetyConfig{MaxConcurrency: 1})
+		release, err := g.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("获取槽位失败: %v", err)
+		}
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := g.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("错误 = %v, 期望 context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("等待令牌", func(t *testing.T) {
+		g := newGovernor(&config.SafetyConfig{MaxConcurrency: 1, RequestsPerSecond: 0.1, RequestBurst: 1})
+		release, err := g.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("获取令牌失败: %v", err)
+		}
+		release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := g.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("错误 = %v, 期望 context.DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("取消后仍等待了 %s", elapsed)
+		}
+		// 等待令牌失败时必须归还并发槽位
+		if n := len(g.sem); n != 0 {
+			t.Errorf("占用的并发槽位 = %d, 期望 0", n)
+		}
+	})
+}
+
+func TestTimeoutFor(t *testing.T) {
+	safety := &config.SafetyConfig{
+		RequestTimeout: 10 * time.Second,
+		EndpointTimeouts: map[string]time.Duration{
+			"/_nodes":          5 * time.Second,
+			"/_nodes/stats":    15 * time.Second,
+			"/_cat/":           8 * time.Second,
+			"/_cluster/health": 3 * time.Second,
+		},
+	}
+	tests := []struct {
+		endpoint string
+		want     time.Duration
+	}{
+		{"/_nodes/stats", 15 * time.Second},
+		{"/_nodes/stats/jvm?level=node", 15 * time.Second},
+		{"/_nodes/http", 5 * time.Second},
+		{"/_cat/indices?format=json", 8 * time.Second},
+		{"/_cluster/health?level=indices", 3 * time.Second},
+		{"/_cluster/state", 10 * time.Second},
+		{"/_stats", 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := timeoutFor(safety, tt.endpoint); got != tt.want {
+			t.Errorf("timeoutFor(%q) = %s, 期望 %s", tt.endpoint, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+var (
+	// ::: {node-1}{nodeId}{ephemeralId}{127.0.0.1}{127.0.0.1:9300}{cdfhilmrstw}
+	hotNodeHeader = regexp.MustCompile(`^:::\s*\{([^}]*)\}\{([^}]*)\}`)
+	// 89.5% [cpu=89.5%, other=0.0%] (447.5ms out of 500ms) cpu usage by thread 'elasticsearch[node-1][write][T#3]'
+	// 7.x 之前没有 [cpu=..., other=...] 部分
+	hotThreadLine = regexp.MustCompile(`^([\d.]+)% (?:\[[^\]]*\] )?\(.*\) \w+ usage by thread '(.*)'$`)
+	// 10/10 snapshots sharing following 29 elements
+	hotStackLine = regexp.MustCompile(`^(\d+)/(\d+) snapshots sharing following \d+ elements$`)
+)
+
+// GetHotThreads 采样热点线程（只读操作，ES 在服务端按 interval 统计 CPU 并抓取调用栈）
+// nodes 为逗号分隔的节点 ID 或名称，为空表示全部节点
+func (c *ElasticsearchClient) GetHotThreads(ctx context.Context, nodes string, threads int, interval time.Duration) ([]model.NodeHotThreads, error) {
+	endpoint := "/_nodes/hot_threads"
+	if nodes != "" {
+		endpoint = "/_nodes/" + url.PathEscape(nodes) + "/hot_threads"
+	}
+	query := url.Values{}
+	query.Set("threads", strconv.Itoa(threads))
+	query.Set("interval", fmt.Sprintf("%dms", interval.Milliseconds()))
+	query.Set("ignore_idle_threads", "true")
+
+	var result []model.NodeHotThreads
+	err := c.stream(ctx, endpoint+"?"+query.Encode(), func(r io.Reader) error {
+		var err error
+		result, err = parseHotThreads(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseHotThreads 解析 hot_threads 文本响应
+// 每个节点以 ::: 开头，其后为线程行和按快照分组的调用栈（栈顶在前）
+func parseHotThreads(r io.Reader) ([]model.NodeHotThreads, error) {
+	var (
+		nodes  []model.NodeHotThreads
+		node   *model.NodeHotThreads
+		thread *model.HotThread
+		stack  *model.HotThreadStack
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			stack = nil
+			continue
+		}
+
+		if m := hotNodeHeader.FindStringSubmatch(line); m != nil {
+			nodes = append(nodes, model.NodeHotThreads{NodeName: m[1], NodeID: m[2]})
+			node, thread, stack = &nodes[len(nodes)-1], nil, nil
+			continue
+		}
+		if node == nil {
+			continue
+		}
+
+		if m := hotThreadLine.FindStringSubmatch(line); m != nil {
+			percent, _ := strconv.ParseFloat(m[1], 64)
+			node.Threads = append(node.Threads, model.HotThread{Name: m[2], CPUPercent: percent})
+			thread, stack = &node.Threads[len(node.Threads)-1], nil
+			continue
+		}
+		if thread == nil {
+			continue
+		}
+
+		// 只出现一次的调用栈没有快照计数，总数在线程结束后补齐
+		if m := hotStackLine.FindStringSubmatch(line); m != nil || line == "unique snapshot" {
+			s := model.HotThreadStack{Snapshots: 1}
+			if m != nil {
+				s.Snapshots, _ = strconv.Atoi(m[1])
+				s.Total, _ = strconv.Atoi(m[2])
+			}
+			thread.Stacks = append(thread.Stacks, s)
+			stack = &thread.Stacks[len(thread.Stacks)-1]
+			continue
+		}
+		if stack != nil {
+			stack.Frames = append(stack.Frames, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解析热点线程失败: %w", err)
+	}
+
+	for i := range nodes {
+		for j := range nodes[i].Threads {
+			fillSnapshotTotals(&nodes[i].Threads[j])
+		}
+	}
+	return nodes, nil
+}
+
+// fillSnapshotTotals 为 unique snapshot 补齐快照总数（取同一线程其他分组的总数）
+func fillSnapshotTotals(thread *model.HotThread) {
+	total, sum := 0, 0
+	for _, s := range thread.Stacks {
+		total = max(total, s.Total)
+		sum += s.Snapshots
+	}
+	if total == 0 {
+		total = sum
+	}
+	for i := range thread.Stacks {
+		if thread.Stacks[i].Total == 0 {
+			thread.Stacks[i].Total = total
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHotThreads(t *testing.T) {
+	// 7.x 之前的格式：线程行没有 [cpu=..., other=...]，栈帧带 at 前缀
+	const text = `::: {node-1}{aaaa}{bbbb}{10.0.0.1}{10.0.0.1:9300}{dim}
+   Hot threads at 2019-01-01T00:00:00.000Z, interval=500ms, busiestThreads=3, ignoreIdleThreads=true:
+
+   25.0% (125ms out of 500ms) cpu usage by thread 'elasticsearch[node-1][search][T#2]'
+     7/10 snapshots sharing following 3 elements
+       at org.apache.lucene.search.TermScorer.score(TermScorer.java:65)
+       at org.elasticsearch.search.query.QueryPhase.execute(QueryPhase.java:100)
+       at java.lang.Thread.run(Thread.java:748)
+
+     unique snapshot
+       at org.elasticsearch.search.query.QueryPhase.execute(QueryPhase.java:100)
+       at java.lang.Thread.run(Thread.java:748)
+
+::: {node-2}{cccc}{dddd}{10.0.0.2}{10.0.0.2:9300}{dim}
+   Hot threads at 2019-01-01T00:00:00.000Z, interval=500ms, busiestThreads=3, ignoreIdleThreads=true:
+
+`
+	nodes, err := parseHotThreads(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || nodes[0].NodeName != "node-1" || nodes[0].NodeID != "aaaa" || len(nodes[1].Threads) != 0 {
+		t.Fatalf("节点 = %+v", nodes)
+	}
+	threads := nodes[0].Threads
+	if len(threads) != 1 || threads[0].Name != "elasticsearch[node-1][search][T#2]" || threads[0].CPUPercent != 25 {
+		t.Fatalf("线程 = %+v", threads)
+	}
+	stacks := threads[0].Stacks
+	if len(stacks) != 2 {
+		t.Fatalf("调用栈 = %+v", stacks)
+	}
+	// unique snapshot 的快照总数取同一线程其他分组的总数
+	if s := stacks[0]; s.Snapshots != 7 || s.Total != 10 || len(s.Frames) != 3 {
+		t.Errorf("第 1 组调用栈 = %+v", s)
+	}
+	if s := stacks[1]; s.Snapshots != 1 || s.Total != 10 || len(s.Frames) != 2 || s.Frames[0] != "at org.elasticsearch.search.query.QueryPhase.execute(QueryPhase.java:100)" {
+		t.Errorf("unique snapshot = %+v", s)
+	}
+}
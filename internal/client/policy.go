@@ -0,0 +1,148 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// PolicyDecision 端点策略判定结果
+type PolicyDecision struct {
+	Allowed bool
+	Rule    string // 命中的规则（方法 + 路径模板）
+	Reason  string // 拒绝原因
+}
+
+// compiledRule 预解析的端点规则
+type compiledRule struct {
+	method   string
+	pattern  string
+	segments []string
+}
+
+// policyEngine 只读端点策略引擎
+type policyEngine struct {
+	readOnly     bool
+	rules        []compiledRule
+	deniedParams map[string]bool
+}
+
+// newPolicyEngine 编译端点策略，拒绝加载任何非只读方法的规则
+func newPolicyEngine(policy config.EndpointPolicy, readOnly bool) (*policyEngine, error) {
+	engine := &policyEngine{
+		readOnly:     readOnly,
+		deniedParams: make(map[string]bool, len(policy.DeniedParams)),
+	}
+
+	for _, rule := range policy.Rules {
+		method := strings.ToUpper(rule.Method)
+		if method != "GET" && method != "HEAD" {
+			return nil, fmt.Errorf("端点策略只允许 GET/HEAD 规则: %s %s", rule.Method, rule.Pattern)
+		}
+		if !strings.HasPrefix(rule.Pattern, "/") {
+			return nil, fmt.Errorf("端点路径模板必须以 / 开头: %s", rule.Pattern)
+		}
+		engine.rules = append(engine.rules, compiledRule{
+			method:   method,
+			pattern:  rule.Pattern,
+			segments: splitPath(rule.Pattern),
+		})
+	}
+
+	for _, param := range policy.DeniedParams {
+		engine.deniedParams[param] = true
+	}
+	return engine, nil
+}
+
+// evaluate 判定请求是否允许
+func (e *policyEngine) evaluate(method, endpoint string) PolicyDecision {
+	if !e.readOnly {
+		return deny("未启用只读模式")
+	}
+	if method != "GET" && method != "HEAD" {
+		return deny("只允许 GET/HEAD 请求")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return deny("无法解析请求地址")
+	}
+	if u.Scheme != "" || u.Host != "" || u.Fragment != "" {
+		return deny("请求地址必须是相对路径")
+	}
+
+	// 编码后的斜杠会在服务端解码为路径分隔符，直接拒绝
+	if strings.Contains(strings.ToLower(u.RawPath), "%2f") {
+		return deny("路径中包含编码的斜杠")
+	}
+	// 只接受规范化路径（不含 .、..、重复斜杠）
+	if !isCleanPath(u.Path) {
+		return deny("路径未规范化")
+	}
+
+	for param := range u.Query() {
+		if e.deniedParams[param] {
+			return deny(fmt.Sprintf("禁止使用查询参数 %s", param))
+		}
+	}
+
+	segments := splitPath(u.Path)
+	for _, rule := range e.rules {
+		if rule.method != method && !(rule.method == "GET" && method == "HEAD") {
+			continue
+		}
+		if matchSegments(rule.segments, segments) {
+			return PolicyDecision{Allowed: true, Rule: rule.method + " " + rule.pattern}
+		}
+	}
+	return deny("未命中任何只读端点规则")
+}
+
+// deny 构造拒绝结果
+func deny(reason string) PolicyDecision {
+	return PolicyDecision{Allowed: false, Reason: reason}
+}
+
+// matchSegments 按段匹配路径模板
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		seg := segments[i]
+		switch {
+		case p == "{index}":
+			if seg == "" || strings.HasPrefix(seg, "_") {
+				return false
+			}
+		case strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}"):
+			if seg == "" {
+				return false
+			}
+		case p != seg:
+			return false
+		}
+	}
+	return true
+}
+
+// isCleanPath 判断路径是否已规范化（允许末尾斜杠）
+func isCleanPath(p string) bool {
+	if p == "/" {
+		return true
+	}
+	return p != "" && path.Clean(p) == strings.TrimSuffix(p, "/")
+}
+
+// splitPath 拆分路径段（根路径返回空切片）
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+func newTestPolicy(t *testing.T) *policyEngine {
+	t.Helper()
+	engine, err := newPolicyEngine(config.DefaultEndpointPolicy, true)
+	if err != nil {
+		t.Fatalf("编译默认策略失败: %v", err)
+	}
+	return engine
+}
+
+// samplePath 将路径模板中的占位段替换为具体值
+func samplePath(pattern string) string {
+	segments := splitPath(pattern)
+	for i, s := range segments {
+		switch {
+		case s == "{index}":
+			segments[i] = "logs-2025.01.01"
+		case strings.HasPrefix(s, "{"):
+			segments[i] = "jvm"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func TestPolicyDefaultRules(t *testing.T) {
+	engine := newTestPolicy(t)
+	for _, rule := range config.DefaultEndpointPolicy.Rules {
+		endpoint := samplePath(rule.Pattern)
+		for _, method := range []string{"GET", "HEAD"} {
+			if d := engine.evaluate(method, endpoint); !d.Allowed {
+				t.Errorf("%s %s (规则 %s) 被拒绝: %s", method, endpoint, rule.Pattern, d.Reason)
+			}
+		}
+		for _, method := range []string{"POST", "PUT", "DELETE"} {
+			if d := engine.evaluate(method, endpoint); d.Allowed {
+				t.Errorf("%s %s 不应被允许 (命中 %s)", method, endpoint, d.Rule)
+			}
+		}
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	engine := newTestPolicy(t)
+	tests := []struct {
+		name     string
+		endpoint string
+		allowed  bool
+		reason   string // 拒绝原因片段
+	}{
+		{"索引模板", "/logs-*/_stats", true, ""},
+		{"索引模板带指标", "/users/_stats/docs", true, ""},
+		{"索引模板不匹配下划线开头", "/_all/_ilm/explain", false, "未命中"},
+		{"指标模板", "/_nodes/stats/jvm,os", true, ""},
+		{"末尾斜杠", "/_cluster/health/", true, ""},
+		{"允许的查询参数", "/_cat/indices?format=json&bytes=b", true, ""},
+		{"编码的斜杠小写", "/logs%2fx/_stats", false, "编码的斜杠"},
+		{"编码的斜杠大写", "/logs%2Fx/_stats", false, "编码的斜杠"},
+		{"上级目录", "/_nodes/../_stats", false, "未规范化"},
+		{"当前目录", "/_nodes/./stats", false, "未规范化"},
+		{"重复斜杠", "/_nodes//stats", false, "未规范化"},
+		{"禁用参数", "/_cluster/health?wait_for_active_shards=all", false, "wait_for_active_shards"},
+		{"禁用参数 source", "/_cat/indices?format=json&source=x", false, "source"},
+		{"绝对地址", "http://es:9200/_cluster/health", false, "相对路径"},
+		{"未列出的路径", "/_security/user", false, "未命中"},
+		{"写操作路径", "/logs/_doc/1", false, "未命中"},
+		{"段数不匹配", "/_cat/shards/logs/extra", false, "未命中"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := engine.evaluate("GET", tt.endpoint)
+			if d.Allowed != tt.allowed {
+				t.Fatalf("GET %s: 允许 = %v, 期望 %v (%s)", tt.endpoint, d.Allowed, tt.allowed, d.Reason)
+			}
+			if !tt.allowed && !strings.Contains(d.Reason, tt.reason) {
+				t.Errorf("GET %s: 拒绝原因 = %q, 期望包含 %q", tt.endpoint, d.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPolicyRequiresReadOnly(t *testing.T) {
+	engine, err := newPolicyEngine(config.DefaultEndpointPolicy, false)
+	if err != nil {
+		t.Fatalf("编译策略失败: %v", err)
+	}
+	if d := engine.evaluate("GET", "/_cluster/health"); d.Allowed {
+		t.Error("未启用只读模式时应拒绝所有请求")
+	}
+}
+
+func TestPolicyRejectsWriteRules(t *testing.T) {
+	tests := []config.EndpointRule{
+		{Method: "POST", Pattern: "/_search"},
+		{Method: "delete", Pattern: "/{index}"},
+		{Method: "GET", Pattern: "_cluster/health"},
+	}
+	for _, rule := range tests {
+		policy := config.EndpointPolicy{Rules: []config.EndpointRule{rule}}
+		if _, err := newPolicyEngine(policy, true); err == nil {
+			t.Errorf("规则 %s %s 应被拒绝加载", rule.Method, rule.Pattern)
+		}
+	}
+}
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/", true},
+		{"/{index}/_stats", "/logs/_stats", true},
+		{"/{index}/_stats", "/logs-*,metrics/_stats", true},
+		{"/{index}/_stats", "/_all/_stats", false},
+		{"/{index}/_stats", "/logs/_count", false},
+		{"/_cat/{name}", "/_cat/nodes", true},
+		{"/_cat/{name}", "/_cat/_nodes", true},
+		{"/_cat/{name}", "/_cat", false},
+		{"/_nodes/{node_id}/hot_threads", "/_nodes/node-1/hot_threads", true},
+	}
+	for _, tt := range tests {
+		if got := matchSegments(splitPath(tt.pattern), splitPath(tt.path)); got != tt.want {
+			t.Errorf("matchSegments(%s, %s) = %v, 期望 %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyDenialRecorded(t *testing.T) {
+	cl := newTestClient(t, nil)
+
+	if _, err := cl.checkPolicy("DELETE", "/logs-2025.01.01"); err == nil {
+		t.Fatal("DELETE 请求应被拒绝")
+	}
+	denials, _ := cl.TakeDenials()
+	if len(denials) != 1 {
+		t.Fatalf("被拒绝的请求 = %d, 期望 1", len(denials))
+	}
+	if d := denials[0]; d.Method != "DELETE" || d.Endpoint != "/logs-2025.01.01" || d.Reason != "只允许 GET/HEAD 请求" {
+		t.Errorf("审计记录 = %+v", d)
+	}
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+	"github.com/Y-vQv-Y/es-monitor/pkg/util"
+)
+
+const (
+	// 节点失败后的初始隔离时间
+	resurrectBaseTimeout = 5 * time.Second
+	// 节点隔离时间上限
+	resurrectMaxTimeout = 5 * time.Minute
+)
+
+// endpoint 连接池中的单个节点
+type endpoint struct {
+	url       *url.URL
+	dead      bool
+	deadUntil time.Time
+	failures  int // 连续失败次数，用于计算隔离退避
+
+	// 累计统计（供界面展示节点故障情况）
+	requests      int64
+	errors        int64
+	lastError     string
+	lastErrorAt   time.Time
+	lastSuccessAt time.Time
+}
+
+// connectionPool 多节点连接池（轮询 + 故障隔离 + 退避复活）
+type connectionPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	cursor    int
+	active    *endpoint
+}
+
+// newConnectionPool 创建连接池
+func newConnectionPool(urls []*url.URL) *connectionPool {
+	p := &connectionPool{}
+	p.update(urls)
+	return p
+}
+
+// next 选择下一个可用节点
+// 优先轮询存活节点和隔离期已过的节点；全部不可用时强制复活最早到期的节点
+func (p *connectionPool) next() *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	n := len(p.endpoints)
+	for i := 0; i < n; i++ {
+		ep := p.endpoints[(p.cursor+i)%n]
+		if !ep.dead || now.After(ep.deadUntil) {
+			p.cursor = (p.cursor + i + 1) % n
+			p.active = ep
+			return ep
+		}
+	}
+
+	var candidate *endpoint
+	for _, ep := range p.endpoints {
+		if candidate == nil || ep.deadUntil.Before(candidate.deadUntil) {
+			candidate = ep
+		}
+	}
+	p.active = candidate
+	return candidate
+}
+
+// markDead 标记节点失败，隔离时间按连续失败次数指数增长
+func (p *connectionPool) markDead(ep *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.failures++
+	timeout := resurrectBaseTimeout << uint(util.Min(ep.failures-1, 16))
+	if timeout > resurrectMaxTimeout {
+		timeout = resurrectMaxTimeout
+	}
+	ep.dead = true
+	ep.deadUntil = time.Now().Add(timeout)
+}
+
+// markAlive 标记节点恢复
+func (p *connectionPool) markAlive(ep *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.dead = false
+	ep.failures = 0
+	ep.deadUntil = time.Time{}
+}
+
+// record 记录单次请求结果
+func (p *connectionPool) record(ep *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.requests++
+	if err == nil {
+		ep.lastSuccessAt = time.Now()
+		return
+	}
+	ep.errors++
+	ep.lastError = err.Error()
+	ep.lastErrorAt = time.Now()
+}
+
+// statuses 返回所有节点的连接状态
+func (p *connectionPool) statuses() []model.EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]model.EndpointStatus, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		result = append(result, model.EndpointStatus{
+			URL:           ep.url.String(),
+			Alive:         !ep.dead,
+			Requests:      ep.requests,
+			Errors:        ep.errors,
+			LastError:     ep.lastError,
+			LastErrorAt:   ep.lastErrorAt,
+			LastSuccessAt: ep.lastSuccessAt,
+		})
+	}
+	return result
+}
+
+// update 替换节点列表，保留已有节点的状态
+func (p *connectionPool) update(urls []*url.URL) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	existing := make(map[string]*endpoint, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		existing[ep.url.String()] = ep
+	}
+
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, u := range urls {
+		if ep, ok := existing[u.String()]; ok {
+			endpoints = append(endpoints, ep)
+			continue
+		}
+		endpoints = append(endpoints, &endpoint{url: u})
+	}
+	p.endpoints = endpoints
+	p.cursor = 0
+}
+
+// size 返回节点数量
+func (p *connectionPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.endpoints)
+}
+
+// status 返回当前活跃节点和存活节点数
+func (p *connectionPool) status() (active string, alive, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active != nil {
+		active = p.active.url.String()
+	}
+	for _, ep := range p.endpoints {
+		if !ep.dead {
+			alive++
+		}
+	}
+	return active, alive, len(p.endpoints)
+}
+
+// seedURLs 根据配置生成种子节点地址
+func seedURLs(cfg *config.Config) ([]*url.URL, error) {
+	hosts := cfg.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{net.JoinHostPort(cfg.Host, cfg.Port)}
+	}
+
+	urls := make([]*url.URL, 0, len(hosts))
+	for _, h := range hosts {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if !strings.Contains(h, "://") {
+			h = cfg.Scheme + "://" + h
+		}
+		u, err := url.Parse(h)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("无效的节点地址: %s", h)
+		}
+		// 未单独指定路径的节点使用全局路径前缀
+		if strings.Trim(u.Path, "/") == "" {
+			u.Path = cfg.PathPrefix
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("未配置任何节点地址")
+	}
+	return urls, nil
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// testPool 创建包含给定主机的连接池
+func testPool(hosts ...string) *connectionPool {
+	urls := make([]*url.URL, 0, len(hosts))
+	for _, h := range hosts {
+		urls = append(urls, &url.URL{Scheme: "http", Host: h})
+	}
+	return newConnectionPool(urls)
+}
+
+// pick 连续选择 n 次节点并返回主机序列
+func pick(p *connectionPool, n int) []string {
+	hosts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		hosts = append(hosts, p.next().url.Host)
+	}
+	return hosts
+}
+
+func equalHosts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	p := testPool("a:9200", "b:9200", "c:9200")
+	want := []string{"a:9200", "b:9200", "c:9200", "a:9200", "b:9200"}
+	if got := pick(p, 5); !equalHosts(got, want) {
+		t.Errorf("轮询顺序 = %v, 期望 %v", got, want)
+	}
+}
+
+func TestPoolSkipsDeadNode(t *testing.T) {
+	p := testPool("a:9200", "b:9200", "c:9200")
+	p.markDead(p.endpoints[1])
+
+	want := []string{"a:9200", "c:9200", "a:9200", "c:9200"}
+	if got := pick(p, 4); !equalHosts(got, want) {
+		t.Errorf("轮询顺序 = %v, 期望跳过隔离节点 %v", got, want)
+	}
+	if _, alive, total := p.status(); alive != 2 || total != 3 {
+		t.Errorf("存活节点 = %d/%d, 期望 2/3", alive, total)
+	}
+}
+
+func TestPoolQuarantineBackoff(t *testing.T) {
+	p := testPool("a:9200", "b:9200")
+	ep := p.endpoints[0]
+
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 160 * time.Second, 5 * time.Minute, 5 * time.Minute}
+	for i, w := range want {
+		before := time.Now()
+		p.markDead(ep)
+		got := ep.deadUntil.Sub(before)
+		if got < w || got > w+time.Second {
+			t.Errorf("第 %d 次失败: 隔离时间 = %s, 期望 %s", i+1, got, w)
+		}
+	}
+
+	// 隔离期内被跳过
+	if got := pick(p, 2); !equalHosts(got, []string{"b:9200", "b:9200"}) {
+		t.Errorf("隔离期内轮询 = %v, 期望只选择 b:9200", got)
+	}
+
+	// 隔离期结束后重新参与轮询，成功后重置退避
+	ep.deadUntil = time.Now().Add(-time.Millisecond)
+	if got := pick(p, 2); !equalHosts(got, []string{"a:9200", "b:9200"}) {
+		t.Errorf("隔离期结束后轮询 = %v, 期望重新选择 a:9200", got)
+	}
+	p.markAlive(ep)
+	if ep.dead || ep.failures != 0 {
+		t.Errorf("复活后 dead = %v, failures = %d, 期望 false, 0", ep.dead, ep.failures)
+	}
+	before := time.Now()
+	p.markDead(ep)
+	if got := ep.deadUntil.Sub(before); got > resurrectBaseTimeout+time.Second {
+		t.Errorf("复活后再次失败的隔离时间 = %s, 期望从 %s 重新开始", got, resurrectBaseTimeout)
+	}
+}
+
+func TestPoolAllDead(t *testing.T) {
+	p := testPool("a:9200", "b:9200", "c:9200")
+	p.markDead(p.endpoints[0])
+	p.markDead(p.endpoints[0])
+	p.markDead(p.endpoints[1])
+	p.markDead(p.endpoints[2])
+	p.markDead(p.endpoints[2])
+	p.markDead(p.endpoints[2])
+
+	// 全部隔离时强制选择最早到期的节点
+	if got := pick(p, 2); !equalHosts(got, []string{"b:9200", "b:9200"}) {
+		t.Errorf("全部隔离时选择 = %v, 期望最早到期的 b:9200", got)
+	}
+	if active, alive, _ := p.status(); active != "http://b:9200" || alive != 0 {
+		t.Errorf("活跃节点 = %q, 存活 = %d, 期望 http://b:9200, 0", active, alive)
+	}
+}
+
+func TestClientFailsOverToLiveNode(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"green"}`))
+	}))
+	t.Cleanup(up.Close)
+
+	safety := config.DefaultSafetyConfig
+	safety.MaxRetries = 0
+	cl, err := NewElasticsearchClient(&config.Config{Hosts: []string{down.URL, up.URL}, Safety: &safety, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	for i := 0; i < 3; i++ {
+		if _, err := cl.request(context.Background(), "/_cluster/health"); err != nil {
+			t.Fatalf("第 %d 次请求失败: %v", i+1, err)
+		}
+	}
+
+	statuses := cl.EndpointStatuses()
+	if statuses[0].Alive || statuses[0].Requests != 1 || statuses[0].Errors != 1 {
+		t.Errorf("故障节点状态 = %+v, 期望隔离且只尝试 1 次", statuses[0])
+	}
+	if !statuses[1].Alive || statuses[1].Requests != 3 {
+		t.Errorf("存活节点状态 = %+v, 期望承担全部 3 次请求", statuses[1])
+	}
+}
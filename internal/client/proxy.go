@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// sshDialTimeout 连接跳板机的超时时间
+const sshDialTimeout = 10 * time.Second
+
+// configureProxy 按配置为传输层设置代理
+// HTTP/HTTPS 代理：https 目标通过 CONNECT 建立隧道，http 目标以绝对 URI 形式转发给代理；
+// SOCKS5 代理由标准库直接支持；
+// SSH 隧道模式下所有连接经跳板机转发（等价于 ssh -L，但无需额外进程）
+func configureProxy(transport *http.Transport, cfg *config.Config) (*sshTunnel, error) {
+	if cfg.Proxy != "" && cfg.SSHTunnel != "" {
+		return nil, fmt.Errorf("-proxy 与 -ssh-tunnel 不能同时使用")
+	}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("无效的代理地址: %s", cfg.Proxy)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("不支持的代理协议: %s（支持 http、https、socks5）", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+		return nil, nil
+	}
+
+	if cfg.SSHTunnel != "" {
+		tunnel, err := newSSHTunnel(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = nil
+		transport.DialContext = tunnel.DialContext
+		return tunnel, nil
+	}
+
+	return nil, nil
+}
+
+// sshTunnel 经 SSH 跳板机转发 TCP 连接
+// SSH 连接按需建立，断开后在下一次拨号时自动重连
+type sshTunnel struct {
+	addr   string
+	config *ssh.ClientConfig
+
+	// dial 建立到跳板机的 TCP 连接，可替换为进程内的 SSH 服务端
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mu     sync.Mutex
+	client *ssh.Client
+	closed bool
+}
+
+// errTunnelClosed 隧道已关闭后不再建立新连接
+var errTunnelClosed = errors.New("SSH 隧道已关闭")
+
+// newSSHTunnel 根据配置创建 SSH 隧道（读取私钥和 known_hosts，不立即连接）
+func newSSHTunnel(cfg *config.Config) (*sshTunnel, error) {
+	user, addr, err := parseSSHTarget(cfg.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.SSHKey == "" {
+		return nil, fmt.Errorf("SSH 隧道需要指定私钥文件 (-ssh-key)")
+	}
+	keyData, err := os.ReadFile(cfg.SSHKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取 SSH 私钥失败: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("解析 SSH 私钥失败: %w", err)
+	}
+
+	knownHostsFile := cfg.SSHKnownHosts
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("无法确定 known_hosts 路径: %w", err)
+		}
+		knownHostsFile = home + "/.ssh/known_hosts"
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取 known_hosts 失败: %w", err)
+	}
+
+	return &sshTunnel{
+		addr: addr,
+		config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         sshDialTimeout,
+		},
+		dial: (&net.Dialer{Timeout: sshDialTimeout}).DialContext,
+	}, nil
+}
+
+// parseSSHTarget 解析 user@host[:port] 形式的跳板机地址
+func parseSSHTarget(target string) (user, addr string, err error) {
+	at := strings.LastIndex(target, "@")
+	if at <= 0 || at == len(target)-1 {
+		return "", "", fmt.Errorf("无效的 SSH 隧道地址: %s（格式 user@host[:port]）", target)
+	}
+	user, addr = target[:at], target[at+1:]
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(strings.Trim(addr, "[]"), "22")
+	}
+	return user, addr, nil
+}
+
+// DialContext 经跳板机连接目标地址
+func (t *sshTunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialSSH(ctx, client, network, addr)
+	var openErr *ssh.OpenChannelError
+	if err == nil || ctx.Err() != nil || errors.As(err, &openErr) {
+		// 跳板机拒绝转发（如目标节点不可达）时 SSH 连接本身仍然可用
+		return conn, err
+	}
+
+	// SSH 连接可能已失效：丢弃后重连一次
+	t.reset(client)
+	client, err = t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dialSSH(ctx, client, network, addr)
+}
+
+// dialSSH 通过 SSH 连接打开转发通道（ssh.Client.Dial 不支持 context，超时由调用方的 context 控制）
+func dialSSH(ctx context.Context, client *ssh.Client, network, addr string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		conn, err := client.Dial(network, addr)
+		ch <- result{conn, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return nil, fmt.Errorf("SSH 隧道转发到 %s 失败: %w", addr, r.err)
+		}
+		return r.conn, nil
+	case <-ctx.Done():
+		go func() {
+			if r := <-ch; r.conn != nil {
+				r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// connect 返回已建立的 SSH 连接，未连接时建立新连接
+// 拨号和握手在锁外进行，避免跳板机无响应时阻塞 Close 和其他拨号；并发建立的多余连接会被关闭
+func (t *sshTunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	client, closed := t.client, t.closed
+	t.mu.Unlock()
+	if closed {
+		return nil, errTunnelClosed
+	}
+	if client != nil {
+		return client, nil
+	}
+
+	conn, err := t.dial(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, fmt.Errorf("连接跳板机 %s 失败: %w", t.addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, t.addr, t.config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH 握手失败 (%s): %w", t.addr, err)
+	}
+	conn.SetDeadline(time.Time{})
+	client = ssh.NewClient(sshConn, chans, reqs)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.closed:
+		client.Close()
+		return nil, errTunnelClosed
+	case t.client != nil:
+		client.Close()
+		return t.client, nil
+	}
+	t.client = client
+	return client, nil
+}
+
+// reset 丢弃失效的 SSH 连接
+func (t *sshTunnel) reset(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == client {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+// Close 关闭 SSH 连接
+func (t *sshTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	if t.client == nil {
+		return nil
+	}
+	err := t.client.Close()
+	t.client = nil
+	return err
+}
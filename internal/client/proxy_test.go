@@ -0,0 +1,533 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// testSSHServer 进程内的 SSH 跳板机，只接受指定公钥并支持 direct-tcpip 转发
+type testSSHServer struct {
+	addr    string
+	hostKey ssh.Signer
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newTestSSHServer(t *testing.T, authorized ssh.PublicKey) *testSSHServer {
+	t.Helper()
+	hostKey := newTestSigner(t)
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(authorized.Marshal()) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, nil
+		},
+	}
+	cfg.AddHostKey(hostKey)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &testSSHServer{addr: ln.Addr().String(), hostKey: hostKey}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn, cfg)
+		}
+	}()
+	return s
+}
+
+func (s *testSSHServer) serve(conn net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for ch := range chans {
+		if ch.ChannelType() != "direct-tcpip" {
+			ch.Reject(ssh.UnknownChannelType, "只支持 direct-tcpip")
+			continue
+		}
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(ch.ExtraData(), &target); err != nil {
+			ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, chReqs, err := ch.Accept()
+		if err != nil {
+			upstream.Close()
+			continue
+		}
+		go ssh.DiscardRequests(chReqs)
+		go func() {
+			io.Copy(channel, upstream)
+			channel.Close()
+		}()
+		go func() {
+			io.Copy(upstream, channel)
+			upstream.Close()
+		}()
+	}
+}
+
+// drop 断开所有已建立的 SSH 连接（模拟跳板机重启）
+func (s *testSSHServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// newEchoServer 回显收到的数据，作为隧道转发的目标
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// newTestTunnel 按命令行配置的方式创建隧道：私钥和 known_hosts 写入临时文件
+func newTestTunnel(t *testing.T, server *testSSHServer, clientKey ed25519.PrivateKey, knownKey ssh.PublicKey) *sshTunnel {
+	t.Helper()
+	dir := t.TempDir()
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	knownHosts := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, knownKey)
+	if err := os.WriteFile(knownHosts, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tunnel, err := newSSHTunnel(&config.Config{SSHTunnel: "monitor@" + server.addr, SSHKey: keyFile, SSHKnownHosts: knownHosts})
+	if err != nil {
+		t.Fatalf("创建 SSH 隧道失败: %v", err)
+	}
+	t.Cleanup(func() { tunnel.Close() })
+	return tunnel
+}
+
+func newClientKey(t *testing.T) (ed25519.PrivateKey, ssh.PublicKey) {
+	t.Helper()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, sshPub
+}
+
+// echo 经隧道发送数据并校验回显
+func echo(t *testing.T, tunnel *sshTunnel, target, msg string) {
+	t.Helper()
+	conn, err := tunnel.DialContext(context.Background(), "tcp", target)
+	if err != nil {
+		t.Fatalf("经隧道连接失败: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, len(msg))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != msg {
+		t.Errorf("回显 = %q, 期望 %q", buf, msg)
+	}
+}
+
+func TestSSHTunnelReconnect(t *testing.T) {
+	key, pub := newClientKey(t)
+	server := newTestSSHServer(t, pub)
+	target := newEchoServer(t)
+	tunnel := newTestTunnel(t, server, key, server.hostKey.PublicKey())
+
+	var dials atomic.Int32
+	dial := tunnel.dial
+	tunnel.dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials.Add(1)
+		return dial(ctx, network, addr)
+	}
+
+	echo(t, tunnel, target, "first")
+	echo(t, tunnel, target, "reuse")
+	if n := dials.Load(); n != 1 {
+		t.Fatalf("连接跳板机 %d 次, 期望复用同一个 SSH 连接", n)
+	}
+
+	server.drop()
+	echo(t, tunnel, target, "after drop")
+	if n := dials.Load(); n != 2 {
+		t.Errorf("跳板机断开后连接 %d 次, 期望重连一次", n)
+	}
+}
+
+func TestSSHTunnelHostKeyMismatch(t *testing.T) {
+	key, pub := newClientKey(t)
+	server := newTestSSHServer(t, pub)
+	tunnel := newTestTunnel(t, server, key, newTestSigner(t).PublicKey())
+
+	_, err := tunnel.DialContext(context.Background(), "tcp", newEchoServer(t))
+	var keyErr *knownhosts.KeyError
+	if err == nil || !strings.Contains(err.Error(), "SSH 握手失败") || !errors.As(err, &keyErr) {
+		t.Fatalf("主机密钥不匹配时的错误 = %v", err)
+	}
+}
+
+func TestSSHTunnelAuthFailure(t *testing.T) {
+	_, pub := newClientKey(t)
+	server := newTestSSHServer(t, pub)
+	otherKey, _ := newClientKey(t)
+	tunnel := newTestTunnel(t, server, otherKey, server.hostKey.PublicKey())
+
+	_, err := tunnel.DialContext(context.Background(), "tcp", newEchoServer(t))
+	if err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+		t.Fatalf("认证失败时的错误 = %v", err)
+	}
+}
+
+func TestSSHTunnelClosed(t *testing.T) {
+	key, pub := newClientKey(t)
+	server := newTestSSHServer(t, pub)
+	tunnel := newTestTunnel(t, server, key, server.hostKey.PublicKey())
+
+	tunnel.Close()
+	if _, err := tunnel.DialContext(context.Background(), "tcp", newEchoServer(t)); err != errTunnelClosed {
+		t.Errorf("关闭后拨号的错误 = %v, 期望 %v", err, errTunnelClosed)
+	}
+}
+
+// esHandler 模拟 ES 集群健康接口
+func esHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"cluster_name":"proxied","status":"green"}`))
+}
+
+// newProxyClient 创建经代理访问 target 的客户端（不重试）
+func newProxyClient(t *testing.T, target, proxy string, configure func(*config.Config)) *ElasticsearchClient {
+	t.Helper()
+	safety := config.DefaultSafetyConfig
+	safety.MaxRetries = 0
+	cfg := &config.Config{Hosts: []string{target}, Proxy: proxy, Safety: &safety, ReadOnly: true}
+	if configure != nil {
+		configure(cfg)
+	}
+	cl, err := NewElasticsearchClient(cfg)
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+	return cl
+}
+
+// pipe 双向转发两个连接直到任一端关闭
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(a, b); done <- struct{}{} }()
+	go func() { io.Copy(b, a); done <- struct{}{} }()
+	<-done
+	a.Close()
+	b.Close()
+}
+
+// newHTTPProxy 启动 HTTP 正向代理，resolve 将请求中的目标地址映射为实际地址
+// 返回代理地址和代理收到的请求记录（"GET <绝对 URI>" 或 "CONNECT <主机>"）
+func newHTTPProxy(t *testing.T, resolve map[string]string) (string, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var seen []string
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, s)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			record("CONNECT " + r.Host)
+			upstream, err := net.Dial("tcp", resolve[r.Host])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				upstream.Close()
+				return
+			}
+			conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+			pipe(conn, upstream)
+			return
+		}
+
+		record(r.Method + " " + r.RequestURI)
+		req := r.Clone(context.Background())
+		req.RequestURI = ""
+		req.URL.Host = resolve[r.URL.Host]
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func TestHTTPProxyForwardsPlainHTTP(t *testing.T) {
+	es := httptest.NewServer(http.HandlerFunc(esHandler))
+	t.Cleanup(es.Close)
+	proxy, seen := newHTTPProxy(t, map[string]string{"es.internal:9200": es.Listener.Addr().String()})
+
+	cl := newProxyClient(t, "http://es.internal:9200", proxy, nil)
+	if _, err := cl.request(context.Background(), "/_cluster/health"); err != nil {
+		t.Fatalf("经代理请求失败: %v", err)
+	}
+
+	// http 目标以绝对 URI 转发，不使用 CONNECT
+	want := []string{"GET http://es.internal:9200/_cluster/health"}
+	if got := seen(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("代理收到 %v, 期望 %v", got, want)
+	}
+}
+
+func TestHTTPProxyConnectsForHTTPS(t *testing.T) {
+	es := httptest.NewTLSServer(http.HandlerFunc(esHandler))
+	t.Cleanup(es.Close)
+	proxy, seen := newHTTPProxy(t, map[string]string{"es.internal:9200": es.Listener.Addr().String()})
+
+	sum := sha256.Sum256(es.Certificate().Raw)
+	cl := newProxyClient(t, "https://es.internal:9200", proxy, func(cfg *config.Config) {
+		cfg.Scheme = "https"
+		cfg.CAFingerprint = hex.EncodeToString(sum[:])
+	})
+	if _, err := cl.request(context.Background(), "/_cluster/health"); err != nil {
+		t.Fatalf("经代理请求失败: %v", err)
+	}
+
+	// https 目标通过 CONNECT 建立隧道，代理看不到请求路径
+	want := []string{"CONNECT es.internal:9200"}
+	if got := seen(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("代理收到 %v, 期望 %v", got, want)
+	}
+}
+
+// newSOCKS5Proxy 启动无认证的 SOCKS5 代理，返回代理地址和收到的 CONNECT 目标
+func newSOCKS5Proxy(t *testing.T, resolve map[string]string) (string, func() []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	var seen []string
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				target, err := socks5Handshake(conn)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				mu.Lock()
+				seen = append(seen, target)
+				mu.Unlock()
+
+				upstream, err := net.Dial("tcp", resolve[target])
+				if err != nil {
+					conn.Write([]byte{5, 4, 0, 1, 0, 0, 0, 0, 0, 0}) // 主机不可达
+					conn.Close()
+					return
+				}
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				pipe(conn, upstream)
+			}()
+		}
+	}()
+
+	return "socks5h://" + ln.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+// socks5Handshake 完成无认证协商并读取 CONNECT 请求，返回目标 host:port
+func socks5Handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(conn, req); err != nil {
+		return "", err
+	}
+	if req[1] != 1 {
+		return "", errors.New("只支持 CONNECT")
+	}
+	var host string
+	switch req[3] {
+	case 1:
+		ip := make([]byte, 4)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 3:
+		n := make([]byte, 1)
+		if _, err := io.ReadFull(conn, n); err != nil {
+			return "", err
+		}
+		name := make([]byte, n[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		return "", errors.New("不支持的地址类型")
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port[0])<<8|int(port[1]))), nil
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	es := httptest.NewServer(http.HandlerFunc(esHandler))
+	t.Cleanup(es.Close)
+	proxy, seen := newSOCKS5Proxy(t, map[string]string{"es.internal:9200": es.Listener.Addr().String()})
+
+	cl := newProxyClient(t, "http://es.internal:9200", proxy, nil)
+	health, err := cl.GetClusterHealth(context.Background())
+	if err != nil {
+		t.Fatalf("经 SOCKS5 代理请求失败: %v", err)
+	}
+	if health.ClusterName != "proxied" {
+		t.Errorf("集群名称 = %s, 期望 proxied", health.ClusterName)
+	}
+	// socks5h 由代理解析主机名
+	if got := seen(); len(got) != 1 || got[0] != "es.internal:9200" {
+		t.Errorf("代理收到的目标 = %v, 期望 [es.internal:9200]", got)
+	}
+}
+
+func TestProxyConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"不支持的协议", config.Config{Proxy: "ftp://proxy:21"}, "不支持的代理协议"},
+		{"缺少主机", config.Config{Proxy: "http://"}, "无效的代理地址"},
+		{"与 SSH 隧道同时使用", config.Config{Proxy: "http://proxy:3128", SSHTunnel: "user@bastion"}, "不能同时使用"},
+	}
+	for _, tt := range tests {
+		_, err := configureProxy(&http.Transport{}, &tt.cfg)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: 错误 = %v, 期望包含 %q", tt.name, err, tt.want)
+		}
+	}
+}
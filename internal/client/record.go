@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// recordingTransport 将收到的每个响应写入归档（录制模式）
+// 响应体在读取的同时复制一份，关闭时写入归档，调用方仍按原方式流式读取
+type recordingTransport struct {
+	next     http.RoundTripper
+	archive  *archiveWriter
+	prefix   string         // 路径前缀，录制时去除，回放时无需相同的代理路径
+	failures *writeFailures // 录制失败不影响监控本身，记入此处在界面中显示
+}
+
+// RoundTrip 发送请求并录制响应
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = &recordingBody{
+		body:      resp.Body,
+		transport: t,
+		entry: ArchiveEntry{
+			Time:   start,
+			Method: req.Method,
+			Path:   strings.TrimPrefix(req.URL.RequestURI(), t.prefix),
+			Status: resp.StatusCode,
+		},
+		gzipped: resp.Header.Get("Content-Encoding") == "gzip",
+	}
+	return resp, nil
+}
+
+// recordingBody 复制读取的响应体，关闭时写入归档
+type recordingBody struct {
+	body      io.ReadCloser
+	transport *recordingTransport
+	entry     ArchiveEntry
+	gzipped   bool
+	buf       bytes.Buffer
+	closed    bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+// Close 读取剩余内容后写入归档（调用方可能在 JSON 结束后不再读取）
+func (b *recordingBody) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	_, copyErr := io.Copy(&b.buf, b.body)
+	err := b.body.Close()
+	if copyErr != nil {
+		return err
+	}
+
+	data := b.buf.Bytes()
+	if b.gzipped {
+		gz, gzErr := gzip.NewReader(bytes.NewReader(data))
+		if gzErr == nil {
+			data, gzErr = io.ReadAll(gz)
+		}
+		if gzErr != nil {
+			b.transport.failures.add(fmt.Errorf("解压录制的响应失败 %s: %w", b.entry.Path, gzErr))
+			return err
+		}
+	}
+	b.entry.Body = string(data)
+	if werr := b.transport.archive.write(b.entry); werr != nil {
+		b.transport.failures.add(fmt.Errorf("写入录制文件失败: %w", werr))
+	}
+	return err
+}
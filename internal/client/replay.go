@@ -0,0 +1,159 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Replayer 回放录制的响应（替代真实集群的传输层）
+// 使用虚拟时钟：连续模式下按倍速推进，单步模式下每次 Step 前进一个采集周期；
+// 每个请求返回虚拟时间之前最近一次录制的同路径响应
+type Replayer struct {
+	byURI  map[string][]ArchiveEntry // 按完整请求 URI 索引（时间升序）
+	byPath map[string][]ArchiveEntry // 按路径索引（忽略查询参数，兼容参数变化）
+	prefix string
+
+	first time.Time
+	last  time.Time
+	ticks []time.Time // 每个采集周期最后一条记录的时间（单步模式使用）
+	speed float64     // 回放倍速，0 表示单步模式
+
+	mu        sync.Mutex
+	wallStart time.Time
+	tick      int
+}
+
+// NewReplayer 加载录制目录，speed 为回放倍速（0 表示单步模式）
+func NewReplayer(dir string, speed float64, prefix string) (*Replayer, error) {
+	if speed < 0 {
+		return nil, fmt.Errorf("无效的回放速度: %v", speed)
+	}
+	entries, err := readArchive(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	r := &Replayer{
+		byURI:     make(map[string][]ArchiveEntry),
+		byPath:    make(map[string][]ArchiveEntry),
+		prefix:    prefix,
+		first:     entries[0].Time,
+		last:      entries[len(entries)-1].Time,
+		ticks:     splitTicks(entries),
+		speed:     speed,
+		wallStart: time.Now(),
+	}
+	for _, e := range entries {
+		r.byURI[e.Path] = append(r.byURI[e.Path], e)
+		path := pathOf(e.Path)
+		r.byPath[path] = append(r.byPath[path], e)
+	}
+	return r, nil
+}
+
+// splitTicks 将记录划分为采集周期：同一路径再次出现即视为进入下一个周期
+func splitTicks(entries []ArchiveEntry) []time.Time {
+	var ticks []time.Time
+	seen := make(map[string]bool)
+	for i, e := range entries {
+		if seen[e.Path] {
+			ticks = append(ticks, entries[i-1].Time)
+			seen = make(map[string]bool)
+		}
+		seen[e.Path] = true
+	}
+	return append(ticks, entries[len(entries)-1].Time)
+}
+
+// pathOf 去除查询参数
+func pathOf(uri string) string {
+	if idx := strings.IndexByte(uri, '?'); idx != -1 {
+		return uri[:idx]
+	}
+	return uri
+}
+
+// Now 返回回放的虚拟时间（不超过录制结束时间）
+func (r *Replayer) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.speed == 0 {
+		return r.ticks[r.tick]
+	}
+	elapsed := time.Duration(float64(time.Since(r.wallStart)) * r.speed)
+	now := r.first.Add(elapsed)
+	if now.After(r.last) {
+		return r.last
+	}
+	return now
+}
+
+// Step 单步模式下前进到下一个采集周期，已到末尾时返回 false
+func (r *Replayer) Step() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tick >= len(r.ticks)-1 {
+		return false
+	}
+	r.tick++
+	return true
+}
+
+// Position 返回当前周期序号和总周期数（单步模式）
+func (r *Replayer) Position() (tick, total int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tick + 1, len(r.ticks)
+}
+
+// Done 是否已回放到录制末尾
+func (r *Replayer) Done() bool {
+	return !r.Now().Before(r.last)
+}
+
+// RoundTrip 返回虚拟时间点对应的录制响应，未录制的请求返回 404
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	uri := strings.TrimPrefix(req.URL.RequestURI(), r.prefix)
+	entries, ok := r.byURI[uri]
+	if !ok {
+		entries, ok = r.byPath[pathOf(uri)]
+	}
+
+	resp := &http.Response{
+		Status:     http.StatusText(http.StatusNotFound),
+		StatusCode: http.StatusNotFound,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":"not recorded"}`)),
+		Request:    req,
+	}
+	if !ok {
+		return resp, nil
+	}
+
+	entry := latestBefore(entries, r.Now())
+	resp.Status = http.StatusText(entry.Status)
+	resp.StatusCode = entry.Status
+	resp.Body = io.NopCloser(strings.NewReader(entry.Body))
+	resp.ContentLength = int64(len(entry.Body))
+	return resp, nil
+}
+
+// latestBefore 返回不晚于 now 的最后一条记录，全部晚于 now 时返回第一条
+func latestBefore(entries []ArchiveEntry, now time.Time) ArchiveEntry {
+	idx := sort.Search(len(entries), func(i int) bool { return entries[i].Time.After(now) })
+	if idx == 0 {
+		return entries[0]
+	}
+	return entries[idx-1]
+}
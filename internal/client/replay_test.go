@@ -0,0 +1,317 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// replayEndpoints 每个采集周期依次请求的端点
+var replayEndpoints = []string{
+	"/_cluster/health",
+	"/_nodes/stats/jvm,os?level=node",
+	"/_cat/indices?format=json&bytes=b",
+}
+
+// recordTicks 对 httptest 服务端录制 ticks 个采集周期，返回每个周期各端点的响应体
+// 服务端按客户端声明的 Accept-Encoding 返回 gzip 压缩的响应
+func recordTicks(t *testing.T, dir string, ticks int) [][]string {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := fmt.Sprintf(`{"call":%d,"uri":%q,"data":"%s"}`, calls.Add(1), r.URL.RequestURI(), strings.Repeat("x", 512))
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(body))
+			gz.Close()
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	safety := config.DefaultSafetyConfig
+	safety.RequestsPerSecond = 0
+	cl, err := NewElasticsearchClient(&config.Config{
+		Hosts: []string{srv.URL + "/es"}, PathPrefix: "/es", Safety: &safety, ReadOnly: true, Record: dir,
+	})
+	if err != nil {
+		t.Fatalf("创建录制客户端失败: %v", err)
+	}
+
+	bodies := make([][]string, ticks)
+	for i := range bodies {
+		for _, endpoint := range replayEndpoints {
+			data, err := cl.request(context.Background(), endpoint)
+			if err != nil {
+				t.Fatalf("周期 %d: 请求 %s 失败: %v", i+1, endpoint, err)
+			}
+			bodies[i] = append(bodies[i], string(data))
+		}
+	}
+	if n, err := cl.TakeRecordErrors(); n != 0 {
+		t.Fatalf("录制失败 %d 次: %v", n, err)
+	}
+	if err := cl.Close(); err != nil {
+		t.Fatalf("关闭录制客户端失败: %v", err)
+	}
+	return bodies
+}
+
+func TestRecordReplayStep(t *testing.T) {
+	dir := t.TempDir()
+	recorded := recordTicks(t, dir, 3)
+
+	safety := config.DefaultSafetyConfig
+	safety.RequestsPerSecond = 0
+	cl, err := NewElasticsearchClient(&config.Config{Replay: dir, ReplaySpeed: 0, Safety: &safety, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("创建回放客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+	replayer := cl.Replayer()
+
+	for i, bodies := range recorded {
+		if tick, total := replayer.Position(); tick != i+1 || total != len(recorded) {
+			t.Fatalf("周期 %d: 位置 = %d/%d, 期望 %d/%d", i+1, tick, total, i+1, len(recorded))
+		}
+		for j, endpoint := range replayEndpoints {
+			data, err := cl.request(context.Background(), endpoint)
+			if err != nil {
+				t.Fatalf("周期 %d: 回放 %s 失败: %v", i+1, endpoint, err)
+			}
+			if string(data) != bodies[j] {
+				t.Errorf("周期 %d: %s 回放内容与录制不一致\n回放 %s\n录制 %s", i+1, endpoint, data, bodies[j])
+			}
+		}
+		if done := replayer.Done(); done != (i == len(recorded)-1) {
+			t.Errorf("周期 %d: Done = %v", i+1, done)
+		}
+		if stepped := replayer.Step(); stepped != (i < len(recorded)-1) {
+			t.Errorf("周期 %d: Step = %v", i+1, stepped)
+		}
+	}
+
+	// 未录制的端点返回 404
+	if _, err := cl.request(context.Background(), "/_cluster/pending_tasks"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("未录制端点的错误 = %v, 期望 404", err)
+	}
+	// 查询参数不同时按路径回退到同路径的录制
+	data, err := cl.request(context.Background(), "/_cluster/health?level=indices")
+	if err != nil || string(data) != recorded[len(recorded)-1][0] {
+		t.Errorf("按路径回退的回放 = %s (%v), 期望最后一个周期的 /_cluster/health", data, err)
+	}
+}
+
+func TestRecordWriteFailureReported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"green"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	safety := config.DefaultSafetyConfig
+	cl, err := NewElasticsearchClient(&config.Config{Hosts: []string{srv.URL}, Safety: &safety, ReadOnly: true, Record: t.TempDir()})
+	if err != nil {
+		t.Fatalf("创建录制客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	// 录制文件不可写时请求本身仍成功，失败保留到界面显示
+	cl.recorder.file.Close()
+	if _, err := cl.request(context.Background(), "/_cluster/health"); err != nil {
+		t.Fatalf("录制失败不应影响请求: %v", err)
+	}
+	n, err := cl.TakeRecordErrors()
+	if n != 1 || err == nil || !strings.Contains(err.Error(), "写入录制文件失败") {
+		t.Errorf("录制失败 = %d 次 (%v), 期望 1 次写入录制文件失败", n, err)
+	}
+	if n, _ := cl.TakeRecordErrors(); n != 0 {
+		t.Errorf("已取出的失败不应重复返回: %d", n)
+	}
+}
+
+func TestReplayContinuousSpeed(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	w, err := newArchiveWriter(dir)
+	if err != nil {
+		t.Fatalf("创建归档失败: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		w.write(ArchiveEntry{Time: base.Add(time.Duration(i) * 10 * time.Second), Method: "GET", Path: "/_cluster/health", Status: 200, Body: fmt.Sprint(i)})
+	}
+	w.Close()
+
+	r, err := NewReplayer(dir, 10, "")
+	if err != nil {
+		t.Fatalf("加载录制失败: %v", err)
+	}
+	// 10 倍速下墙上时间 1 秒对应录制时间 10 秒，不超过录制结束时间
+	r.wallStart = time.Now().Add(-time.Second)
+	if now := r.Now(); now.Before(base.Add(10*time.Second)) || now.After(base.Add(12*time.Second)) {
+		t.Errorf("回放时间 = %s, 期望约 %s", now, base.Add(10*time.Second))
+	}
+	r.wallStart = time.Now().Add(-time.Hour)
+	if now := r.Now(); !now.Equal(base.Add(20*time.Second)) || !r.Done() {
+		t.Errorf("回放时间 = %s, Done = %v, 期望停在录制结束时间 %s", now, r.Done(), base.Add(20*time.Second))
+	}
+
+	if _, err := NewReplayer(dir, -1, ""); err == nil {
+		t.Error("负的回放速度应返回错误")
+	}
+}
+
+func TestLatestBefore(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []ArchiveEntry{
+		{Time: base, Body: "0"},
+		{Time: base.Add(10 * time.Second), Body: "1"},
+		{Time: base.Add(20 * time.Second), Body: "2"},
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"早于第一条", base.Add(-time.Second), "0"},
+		{"等于第一条", base, "0"},
+		{"两条之间", base.Add(9 * time.Second), "0"},
+		{"等于中间一条", base.Add(10 * time.Second), "1"},
+		{"刚过中间一条", base.Add(10*time.Second + time.Nanosecond), "1"},
+		{"等于最后一条", base.Add(20 * time.Second), "2"},
+		{"晚于最后一条", base.Add(time.Hour), "2"},
+	}
+	for _, tt := range tests {
+		if got := latestBefore(entries, tt.now); got.Body != tt.want {
+			t.Errorf("%s: latestBefore = %s, 期望 %s", tt.name, got.Body, tt.want)
+		}
+	}
+}
+
+func TestSplitTicks(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(paths ...string) []ArchiveEntry {
+		entries := make([]ArchiveEntry, len(paths))
+		for i, p := range paths {
+			entries[i] = ArchiveEntry{Time: base.Add(time.Duration(i) * time.Second), Path: p}
+		}
+		return entries
+	}
+	tests := []struct {
+		name    string
+		entries []ArchiveEntry
+		want    []int // 每个周期最后一条记录的序号
+	}{
+		{"单个周期", at("/a", "/b", "/c"), []int{2}},
+		{"路径重复出现开始新周期", at("/a", "/b", "/a", "/b", "/a"), []int{1, 3, 4}},
+		{"同一路径连续出现", at("/a", "/a", "/a"), []int{0, 1, 2}},
+		{"周期内请求数不同", at("/a", "/b", "/c", "/a", "/c", "/a"), []int{2, 4, 5}},
+		{"查询参数不同视为不同请求", at("/a?x=1", "/a?x=2", "/a?x=1"), []int{1, 2}},
+	}
+	for _, tt := range tests {
+		got := splitTicks(tt.entries)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: 周期数 = %d, 期望 %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i, idx := range tt.want {
+			if !got[i].Equal(tt.entries[idx].Time) {
+				t.Errorf("%s: 周期 %d 结束于 %s, 期望第 %d 条记录 %s", tt.name, i+1, got[i], idx, tt.entries[idx].Time)
+			}
+		}
+	}
+}
+
+func TestReadArchiveDamaged(t *testing.T) {
+	writeEntries := func(t *testing.T, dir string, n int, close bool) {
+		w, err := newArchiveWriter(dir)
+		if err != nil {
+			t.Fatalf("创建归档失败: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			w.write(ArchiveEntry{Time: time.Unix(int64(i), 0), Method: "GET", Path: "/_cluster/health", Status: 200, Body: strings.Repeat("y", 200)})
+		}
+		if close {
+			w.Close()
+		} else {
+			w.file.Close()
+		}
+	}
+	truncate := func(t *testing.T, dir string, cut int64) {
+		path := filepath.Join(dir, archiveFile)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Truncate(path, info.Size()-cut); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("未正常关闭", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEntries(t, dir, 3, false)
+		if entries, err := readArchive(dir); err != nil || len(entries) != 3 {
+			t.Errorf("读取到 %d 条 (%v), 期望保留已刷新的 3 条", len(entries), err)
+		}
+	})
+
+	t.Run("末尾截断", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEntries(t, dir, 3, false)
+		truncate(t, dir, 20)
+		entries, err := readArchive(dir)
+		if err != nil || len(entries) != 2 {
+			t.Errorf("读取到 %d 条 (%v), 期望保留完整的前 2 条", len(entries), err)
+		}
+	})
+
+	t.Run("追加录制的多个 gzip 成员", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEntries(t, dir, 2, true)
+		writeEntries(t, dir, 2, true)
+		if entries, err := readArchive(dir); err != nil || len(entries) != 4 {
+			t.Errorf("读取到 %d 条 (%v), 期望 4 条", len(entries), err)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, dir string)
+		want  string
+	}{
+		{"文件不存在", func(t *testing.T, dir string) {}, "打开录制文件失败"},
+		{"不是 gzip", func(t *testing.T, dir string) {
+			os.WriteFile(filepath.Join(dir, archiveFile), []byte(`{"path":"/"}`), 0o600)
+		}, "读取录制文件失败"},
+		{"没有任何记录", func(t *testing.T, dir string) { writeEntries(t, dir, 0, true) }, "没有任何响应"},
+		{"只有截断的记录", func(t *testing.T, dir string) {
+			writeEntries(t, dir, 1, false)
+			truncate(t, dir, 20)
+		}, "没有任何响应"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(t, dir)
+			_, err := readArchive(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("错误 = %v, 期望包含 %q", err, tt.want)
+			}
+			if _, err := NewReplayer(dir, 1, ""); err == nil {
+				t.Error("NewReplayer 应返回错误")
+			}
+		})
+	}
+}
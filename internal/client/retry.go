@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusError 非 200 响应错误
+type statusError struct {
+	code       int
+	retryAfter time.Duration // 服务端通过 Retry-After 要求的等待时间
+}
+
+func (e *statusError) Error() string {
+	if e.retryAfter > 0 {
+		return fmt.Sprintf("HTTP 状态码: %d (Retry-After %s)", e.code, e.retryAfter)
+	}
+	return fmt.Sprintf("HTTP 状态码: %d", e.code)
+}
+
+// retryable 判断状态码是否可重试（限流或网关/服务暂不可用）
+func (e *statusError) retryable() bool {
+	switch e.code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoffDelay 计算第 attempt 次重试前的等待时间（指数退避 + 随机抖动）
+// 服务端给出 Retry-After 时按其等待，不会提前重试（能否等待由 retryAfterAllowed 判断）
+func backoffDelay(base, max time.Duration, attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := base << uint(attempt)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	// 在 [delay/2, delay) 区间内随机，避免多个实例同时重试
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfterAllowed 判断是否可以按 Retry-After 等待后重试：
+// 请求设置了截止时间时等待结束不能晚于截止时间，否则等待不能超过退避上限
+func retryAfterAllowed(ctx context.Context, retryAfter, max time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline) >= retryAfter
+	}
+	return retryAfter <= max
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或 HTTP 日期）
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleepContext 可被取消的等待
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
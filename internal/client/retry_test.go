@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// newTestClient 创建连接到 handler 的客户端
+func newTestClient(t *testing.T, handler http.HandlerFunc) *ElasticsearchClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	safety := config.DefaultSafetyConfig
+	safety.RetryBaseDelay = 10 * time.Millisecond
+	cl, err := NewElasticsearchClient(&config.Config{Hosts: []string{srv.URL}, Safety: &safety, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+	return cl
+}
+
+func TestRetryAfterHonored(t *testing.T) {
+	var calls atomic.Int32
+	cl := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"status":"green"}`))
+	})
+
+	start := time.Now()
+	if _, err := cl.request(context.Background(), "/_cluster/health"); err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Retry-After 为 1s，但 %s 后就重试了", elapsed)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("请求次数 = %d, 期望 2", n)
+	}
+}
+
+func TestRetryAfterBeyondLimitGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	cl := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	start := time.Now()
+	_, err := cl.request(context.Background(), "/_cluster/health")
+	if err == nil || !strings.Contains(err.Error(), "30s") {
+		t.Fatalf("错误 = %v, 期望包含服务端要求的等待时间 30s", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("放弃重试前等待了 %s", elapsed)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("请求次数 = %d, 期望 1（不能早于 Retry-After 重试）", n)
+	}
+}
+
+func TestRetryAfterWithinDeadline(t *testing.T) {
+	if !retryAfterAllowed(context.Background(), 5*time.Second, 5*time.Second) {
+		t.Error("没有截止时间时应允许等待不超过上限的 Retry-After")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if !retryAfterAllowed(ctx, 30*time.Second, 5*time.Second) {
+		t.Error("截止时间足够时应允许等待超过上限的 Retry-After")
+	}
+	if retryAfterAllowed(ctx, 2*time.Minute, 5*time.Second) {
+		t.Error("等待结束晚于截止时间时不应重试")
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// nodesHTTPInfo /_nodes/http 响应
+type nodesHTTPInfo struct {
+	Nodes map[string]struct {
+		HTTP struct {
+			PublishAddress string `json:"publish_address"`
+		} `json:"http"`
+	} `json:"nodes"`
+}
+
+// Sniff 从 /_nodes/http 发现集群 HTTP 发布地址并更新连接池
+func (c *ElasticsearchClient) Sniff(ctx context.Context) error {
+	c.lastSniff.Store(time.Now().UnixNano())
+
+	data, err := c.request(ctx, "/_nodes/http")
+	if err != nil {
+		return fmt.Errorf("节点发现失败: %w", err)
+	}
+
+	var info nodesHTTPInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return fmt.Errorf("解析失败: %w", err)
+	}
+
+	urls := make([]*url.URL, 0, len(info.Nodes))
+	for _, node := range info.Nodes {
+		addr := parsePublishAddress(node.HTTP.PublishAddress)
+		if addr == "" {
+			continue
+		}
+		// 发布地址是节点自身的 HTTP 地址，不经过反向代理，因此不使用种子地址的路径前缀
+		urls = append(urls, &url.URL{Scheme: c.config.Scheme, Host: addr})
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("节点发现未返回任何 HTTP 地址")
+	}
+
+	// 排序保证轮询顺序稳定
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Host < urls[j].Host })
+	c.pool.update(urls)
+	return nil
+}
+
+// maybeSniff 按间隔在后台刷新节点列表
+func (c *ElasticsearchClient) maybeSniff() {
+	if !c.config.Sniff || c.config.SniffInterval <= 0 {
+		return
+	}
+	if time.Since(time.Unix(0, c.lastSniff.Load())) < c.config.SniffInterval {
+		return
+	}
+	if !c.sniffing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer c.sniffing.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), c.safety.RequestTimeout)
+		defer cancel()
+		// 发现失败时保留原有节点列表
+		_ = c.Sniff(ctx)
+	}()
+}
+
+// parsePublishAddress 解析发布地址，格式为 "ip:port" 或 "hostname/ip:port"
+func parsePublishAddress(addr string) string {
+	if addr == "" {
+		return ""
+	}
+	if idx := strings.LastIndex(addr, "/"); idx != -1 {
+		addr = addr[idx+1:]
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return ""
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+func TestSniffIgnoresPathPrefix(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_cluster/health" {
+			t.Errorf("发现的节点收到 %s, 不应带代理路径前缀", r.URL.Path)
+		}
+		w.Write([]byte(`{"status":"green"}`))
+	}))
+	t.Cleanup(node.Close)
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/es/_nodes/http" {
+			t.Errorf("代理收到 %s, 期望 /es/_nodes/http", r.URL.Path)
+		}
+		addr := strings.TrimPrefix(node.URL, "http://")
+		w.Write([]byte(`{"nodes":{"n1":{"http":{"publish_address":"es-1/` + addr + `"}}}}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	safety := config.DefaultSafetyConfig
+	cl, err := NewElasticsearchClient(&config.Config{
+		Hosts: []string{proxy.URL}, PathPrefix: "/es", Scheme: "http", Sniff: true, Safety: &safety, ReadOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	ctx := context.Background()
+	if err := cl.Sniff(ctx); err != nil {
+		t.Fatalf("节点发现失败: %v", err)
+	}
+	if _, err := cl.GetClusterHealth(ctx); err != nil {
+		t.Fatalf("请求发现的节点失败: %v", err)
+	}
+}
+
+func TestSniffReplacesNodeList(t *testing.T) {
+	seed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"nodes":{
+			"n2":{"http":{"publish_address":"es-2/10.0.0.2:9200"}},
+			"n1":{"http":{"publish_address":"10.0.0.1:9200"}},
+			"n3":{"http":{"publish_address":"[::1]:9201"}},
+			"n4":{"http":{}}
+		}}`))
+	}))
+	t.Cleanup(seed.Close)
+
+	safety := config.DefaultSafetyConfig
+	cl, err := NewElasticsearchClient(&config.Config{
+		Hosts: []string{seed.URL}, Scheme: "http", Sniff: true, Safety: &safety, ReadOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	if err := cl.Sniff(context.Background()); err != nil {
+		t.Fatalf("节点发现失败: %v", err)
+	}
+
+	// 种子节点被发现的节点替换，缺少发布地址的节点被忽略，按地址排序
+	want := []string{"http://10.0.0.1:9200", "http://10.0.0.2:9200", "http://[::1]:9201"}
+	statuses := cl.EndpointStatuses()
+	if len(statuses) != len(want) {
+		t.Fatalf("节点数 = %d, 期望 %d: %+v", len(statuses), len(want), statuses)
+	}
+	for i, s := range statuses {
+		if s.URL != want[i] {
+			t.Errorf("节点 %d = %s, 期望 %s", i, s.URL, want[i])
+		}
+	}
+}
+
+func TestPoolUpdateKeepsEndpointState(t *testing.T) {
+	p := testPool("a:9200", "b:9200")
+	p.markDead(p.endpoints[1])
+
+	p.update([]*url.URL{{Scheme: "http", Host: "b:9200"}, {Scheme: "http", Host: "c:9200"}})
+	if p.size() != 2 {
+		t.Fatalf("节点数 = %d, 期望 2", p.size())
+	}
+	if !p.endpoints[0].dead || p.endpoints[0].failures != 1 {
+		t.Errorf("保留的节点 b:9200 丢失了隔离状态: %+v", p.endpoints[0])
+	}
+	if p.endpoints[1].dead {
+		t.Errorf("新节点 c:9200 不应处于隔离状态")
+	}
+}
+
+func TestParsePublishAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"10.0.0.1:9200", "10.0.0.1:9200"},
+		{"es-1/10.0.0.1:9200", "10.0.0.1:9200"},
+		{"[::1]:9200", "[::1]:9200"},
+		{"es-1/[::1]:9200", "[::1]:9200"},
+		{"10.0.0.1", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := parsePublishAddress(tt.addr); got != tt.want {
+			t.Errorf("parsePublishAddress(%q) = %q, 期望 %q", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// limitedReader 限制响应体大小，超过上限时返回错误而不是截断
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.limit > 0 && r.n > r.limit {
+		return 0, fmt.Errorf("响应体超过上限 %d 字节", r.limit)
+	}
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.limit > 0 && r.n > r.limit {
+		return n, fmt.Errorf("响应体超过上限 %d 字节", r.limit)
+	}
+	return n, err
+}
+
+// decodeEntries 流式解析 {"<field>": {"<key>": {...}, ...}, ...} 结构
+// field 下的条目逐个解码后交给 add，其他顶层字段直接跳过，避免整个响应驻留内存
+func decodeEntries[T any](r io.Reader, field string, add func(key string, v T)) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return err
+		}
+		if key != field {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == nil {
+			continue
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+			return fmt.Errorf("字段 %s 不是对象", field)
+		}
+		for dec.More() {
+			name, err := objectKey(dec)
+			if err != nil {
+				return err
+			}
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				return fmt.Errorf("解码 %s.%s 失败: %w", field, name, err)
+			}
+			add(name, v)
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// expectDelim 读取下一个分隔符并校验
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("期望 %q，实际为 %v", want, tok)
+	}
+	return nil
+}
+
+// objectKey 读取对象中的下一个键
+func objectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("期望对象键，实际为 %v", tok)
+	}
+	return key, nil
+}
+
+// skipValue 逐个 token 跳过一个完整的值（不缓存内容）
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
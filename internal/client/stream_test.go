@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// indexStatsBody 生成 n 个索引的 _stats 响应体（含 _shards 和 _all）
+func indexStatsBody(tb testing.TB, n int) []byte {
+	tb.Helper()
+	var stat model.IndexStat
+	stat.UUID = "xKv9aW2cQ4u1tV8nR3mYpA"
+	stat.Health = "green"
+	stat.Status = "open"
+	stat.Primaries.Docs.Count = 123456789
+	stat.Total.Docs.Count = 246913578
+	entry, err := json.Marshal(stat)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"_shards":{"total":10000,"successful":10000,"failed":0},"_all":`)
+	buf.WriteString(`{"primaries":` + string(entry) + `,"total":` + string(entry) + `},"indices":{`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `"logs-%05d":`, i)
+		buf.Write(entry)
+	}
+	buf.WriteString(`}}`)
+	return buf.Bytes()
+}
+
+func TestDecodeEntriesMatchesUnmarshal(t *testing.T) {
+	body := indexStatsBody(t, 100)
+
+	streamed := make(map[string]model.IndexStat)
+	err := decodeEntries(bytes.NewReader(body), "indices", func(name string, index model.IndexStat) {
+		streamed[name] = index
+	})
+	if err != nil {
+		t.Fatalf("流式解析失败: %v", err)
+	}
+	var whole model.IndexStats
+	if err := json.Unmarshal(body, &whole); err != nil {
+		t.Fatal(err)
+	}
+	if len(streamed) != len(whole.Indices) {
+		t.Fatalf("流式解析 %d 个索引, 整体解析 %d 个", len(streamed), len(whole.Indices))
+	}
+	for name, index := range whole.Indices {
+		if streamed[name] != index {
+			t.Errorf("%s: 流式解析 %+v, 整体解析 %+v", name, streamed[name], index)
+		}
+	}
+}
+
+// 5000 个索引的 _stats：流式解码（GetIndexStats 的做法）与先读完整个响应体再整体解析对比
+func BenchmarkDecodeEntries(b *testing.B) {
+	body := indexStatsBody(b, 5000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		stats := make(map[string]model.IndexStat)
+		err := decodeEntries(bytes.NewReader(body), "indices", func(name string, index model.IndexStat) {
+			stats[name] = index
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadAllUnmarshal(b *testing.B) {
+	body := indexStatsBody(b, 5000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := io.ReadAll(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var stats model.IndexStats
+		if err := json.Unmarshal(data, &stats); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
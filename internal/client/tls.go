@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// buildTLSConfig 根据配置构建 TLS 配置（http 协议时返回 nil）
+func buildTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.Scheme != "https" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// 自定义 CA 证书
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 证书文件中没有有效的 PEM 证书: %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// 双向 TLS 客户端证书
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, errors.New("客户端证书和私钥必须同时指定")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// CA 指纹固定：由自定义校验替代系统校验
+	if cfg.CAFingerprint != "" {
+		fingerprint, err := parseFingerprint(cfg.CAFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			return verifyFingerprint(cs.PeerCertificates, fingerprint)
+		}
+		return tlsConfig, nil
+	}
+
+	tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+	return tlsConfig, nil
+}
+
+// parseFingerprint 解析 SHA-256 指纹，兼容冒号分隔和大小写
+func parseFingerprint(s string) ([]byte, error) {
+	cleaned := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), ":", ""))
+	fingerprint, err := hex.DecodeString(cleaned)
+	if err != nil || len(fingerprint) != sha256.Size {
+		return nil, fmt.Errorf("无效的 CA 指纹（需要 SHA-256 十六进制格式）: %s", s)
+	}
+	return fingerprint, nil
+}
+
+// verifyFingerprint 校验证书链中存在指纹匹配的 CA，且服务端证书由该 CA 签发
+func verifyFingerprint(certs []*x509.Certificate, fingerprint []byte) error {
+	if len(certs) == 0 {
+		return errors.New("服务端未提供证书")
+	}
+
+	for _, cert := range certs {
+		sum := sha256.Sum256(cert.Raw)
+		if !bytes.Equal(sum[:], fingerprint) {
+			continue
+		}
+
+		// 以指纹匹配的证书作为唯一信任根，不校验主机名（ES 自签证书常缺少 SAN）
+		roots := x509.NewCertPool()
+		roots.AddCert(cert)
+		intermediates := x509.NewCertPool()
+		for _, c := range certs[1:] {
+			intermediates.AddCert(c)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		if err != nil {
+			return fmt.Errorf("服务端证书未由指纹匹配的 CA 签发: %w", err)
+		}
+		return nil
+	}
+
+	return errors.New("证书链中没有与指纹匹配的 CA 证书")
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+)
+
+// newTLSTestClient 创建连接到 https 测试服务器的客户端（不重试，失败立即返回）
+func newTLSTestClient(t *testing.T, srv *httptest.Server, configure func(*config.Config)) (*ElasticsearchClient, error) {
+	t.Helper()
+	safety := config.DefaultSafetyConfig
+	safety.MaxRetries = 0
+	cfg := &config.Config{Hosts: []string{srv.URL}, Scheme: "https", Safety: &safety, ReadOnly: true}
+	configure(cfg)
+	cl, err := NewElasticsearchClient(cfg)
+	if err == nil {
+		t.Cleanup(func() { cl.Close() })
+	}
+	return cl, err
+}
+
+// testCert 生成证书；parent 为 nil 时生成自签 CA
+func testCert(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("签发证书失败: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("解析证书失败: %v", err)
+	}
+	return cert, key
+}
+
+// writePEM 将 PEM 块写入临时文件并返回路径
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("写入 %s 失败: %v", name, err)
+	}
+	return path
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"status":"green"}`))
+}
+
+func TestTLSCACert(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer srv.Close()
+
+	unknownCA, _ := testCert(t, "unknown-ca", nil, nil)
+
+	tests := []struct {
+		name    string
+		caCert  string
+		wantErr bool
+	}{
+		{"服务端 CA", writePEM(t, "ca.pem", "CERTIFICATE", srv.Certificate().Raw), false},
+		{"未知 CA", writePEM(t, "unknown.pem", "CERTIFICATE", unknownCA.Raw), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := newTLSTestClient(t, srv, func(cfg *config.Config) { cfg.CACert = tt.caCert })
+			if err != nil {
+				t.Fatalf("创建客户端失败: %v", err)
+			}
+			_, err = cl.request(context.Background(), "/_cluster/health")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("请求错误 = %v, 期望出错 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSCACertInvalidPEM(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(path, []byte("not a certificate"), 0o600)
+	_, err := newTLSTestClient(t, srv, func(cfg *config.Config) { cfg.CACert = path })
+	if err == nil || !strings.Contains(err.Error(), "没有有效的 PEM 证书") {
+		t.Errorf("错误 = %v, 期望提示没有有效的 PEM 证书", err)
+	}
+}
+
+func TestTLSCAFingerprint(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer srv.Close()
+
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	plain := hex.EncodeToString(sum[:])
+	pairs := make([]string, 0, len(sum))
+	for _, b := range sum {
+		pairs = append(pairs, hex.EncodeToString([]byte{b}))
+	}
+	colons := strings.Join(pairs, ":")
+	other := sha256.Sum256([]byte("other"))
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		wantErr     string // 为空表示请求应成功
+	}{
+		{"小写无冒号", plain, ""},
+		{"大写无冒号", strings.ToUpper(plain), ""},
+		{"小写带冒号", colons, ""},
+		{"大写带冒号", strings.ToUpper(colons), ""},
+		{"指纹不匹配", hex.EncodeToString(other[:]), "没有与指纹匹配的 CA 证书"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := newTLSTestClient(t, srv, func(cfg *config.Config) { cfg.CAFingerprint = tt.fingerprint })
+			if err != nil {
+				t.Fatalf("创建客户端失败: %v", err)
+			}
+			_, err = cl.request(context.Background(), "/_cluster/health")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("请求失败: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("错误 = %v, 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSCAFingerprintInvalid(t *testing.T) {
+	for _, fp := range []string{"zz", "abcd", strings.Repeat("ab", 20)} {
+		if _, err := parseFingerprint(fp); err == nil {
+			t.Errorf("parseFingerprint(%q) 应返回错误", fp)
+		}
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	clientCA, clientCAKey := testCert(t, "client-ca", nil, nil)
+	clientCert, clientKey := testCert(t, "es-monitor", clientCA, clientCAKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(okHandler))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caPath := writePEM(t, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	certPath := writePEM(t, "client.pem", "CERTIFICATE", clientCert.Raw)
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatalf("编码私钥失败: %v", err)
+	}
+	keyPath := writePEM(t, "client.key", "EC PRIVATE KEY", keyDER)
+
+	tests := []struct {
+		name      string
+		cert, key string
+		wantErr   bool
+	}{
+		{"携带客户端证书", certPath, keyPath, false},
+		{"未携带客户端证书", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := newTLSTestClient(t, srv, func(cfg *config.Config) {
+				cfg.CACert = caPath
+				cfg.ClientCert = tt.cert
+				cfg.ClientKey = tt.key
+			})
+			if err != nil {
+				t.Fatalf("创建客户端失败: %v", err)
+			}
+			_, err = cl.request(context.Background(), "/_cluster/health")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("请求错误 = %v, 期望出错 %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err = newTLSTestClient(t, srv, func(cfg *config.Config) { cfg.ClientCert = certPath })
+	if err == nil || !strings.Contains(err.Error(), "必须同时指定") {
+		t.Errorf("只指定客户端证书时错误 = %v, 期望提示证书和私钥必须同时指定", err)
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// 发行版
+const (
+	DistributionElasticsearch = "elasticsearch"
+	DistributionOpenSearch    = "opensearch"
+)
+
+// Version 语义化版本号
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// parseVersion 解析版本号（如 "8.11.0"、"7.10.2"、"8.0.0-SNAPSHOT"）
+func parseVersion(s string) (Version, error) {
+	if idx := strings.IndexByte(s, '-'); idx != -1 {
+		s = s[:idx]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return Version{}, fmt.Errorf("无法解析版本号: %s", s)
+	}
+
+	var nums [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("无法解析版本号: %s", s)
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+// AtLeast 判断版本是否不低于 major.minor
+func (v Version) AtLeast(major, minor int) bool {
+	return v.Major > major || v.Major == major && v.Minor >= minor
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capabilities 集群版本能力，采集器据此选择端点和字段
+type Capabilities struct {
+	Distribution string
+	Version      Version
+
+	IndexingPressure    bool // _nodes/stats 中的 indexing_pressure（ES 7.9+ / OpenSearch）
+	HealthReport        bool // /_health_report（ES 8.7+）
+	ILM                 bool // 索引生命周期管理（ES 6.6+，OpenSearch 使用 ISM）
+	SLM                 bool // 快照生命周期管理（ES 7.4+，OpenSearch 的 Snapshot Management 不支持）
+	ISM                 bool // OpenSearch 索引状态管理（_plugins/_ism，代替 ILM）
+	DataStreamLifecycle bool // 数据流生命周期（ES 8.11+）
+
+	AllocationExplainParams bool // _cluster/allocation/explain 通过查询参数指定分片（ES 8.14+，此前只能通过请求体指定）
+}
+
+// detectCapabilities 根据根端点信息推断集群能力
+func detectCapabilities(info *model.ClusterInfo) (Capabilities, error) {
+	version, err := parseVersion(info.Version.Number)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	caps := Capabilities{
+		Distribution: DistributionElasticsearch,
+		Version:      version,
+	}
+	if info.Version.Distribution == DistributionOpenSearch {
+		// OpenSearch 从 ES 7.10.2 分叉，保留了 indexing_pressure
+		caps.Distribution = DistributionOpenSearch
+		caps.IndexingPressure = true
+		caps.ISM = true
+		return caps, nil
+	}
+
+	caps.IndexingPressure = version.AtLeast(7, 9)
+	caps.HealthReport = version.AtLeast(8, 7)
+	caps.ILM = version.AtLeast(6, 6)
+	caps.SLM = version.AtLeast(7, 4)
+	caps.DataStreamLifecycle = version.AtLeast(8, 11)
+	caps.AllocationExplainParams = version.AtLeast(8, 14)
+	return caps, nil
+}
+
+// NodeStatsMetrics 节点统计的指标选择器（_nodes/stats/<metric>/<index_metric>）
+func (c Capabilities) NodeStatsMetrics() string {
+	metrics := nodeBaseMetrics
+	if c.IndexingPressure {
+		metrics += ",indexing_pressure"
+	}
+	return metrics + "/" + nodeIndexMetrics
+}
+
+// IndexStatsMetrics 索引统计的指标选择器（_stats/<metric>），各版本相同
+func (c Capabilities) IndexStatsMetrics() string {
+	return indexStatsMetrics
+}
+
+// String 返回发行版和版本，如 "Elasticsearch 8.11.0"
+func (c Capabilities) String() string {
+	name := "Elasticsearch"
+	if c.Distribution == DistributionOpenSearch {
+		name = "OpenSearch"
+	}
+	return name + " " + c.Version.String()
+}
+
+// UnsupportedError 当前集群版本不支持的功能
+type UnsupportedError struct {
+	Feature     string
+	Requirement string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("当前版本不支持%s（需要 %s）", e.Feature, e.Requirement)
+}
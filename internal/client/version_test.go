@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	tests := []struct {
+		distribution, number string
+		ilm, ism, slm        bool
+		nodeMetrics          string
+	}{
+		{"", "7.8.1", true, false, true, nodeBaseMetrics + "/" + nodeIndexMetrics},
+		{"", "8.11.0", true, false, true, nodeBaseMetrics + ",indexing_pressure/" + nodeIndexMetrics},
+		{DistributionOpenSearch, "2.11.0", false, true, false, nodeBaseMetrics + ",indexing_pressure/" + nodeIndexMetrics},
+	}
+	for _, tt := range tests {
+		info := &model.ClusterInfo{}
+		info.Version.Number = tt.number
+		info.Version.Distribution = tt.distribution
+		caps, err := detectCapabilities(info)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.number, err)
+		}
+		if caps.ILM != tt.ilm || caps.ISM != tt.ism || caps.SLM != tt.slm {
+			t.Errorf("%s: ILM=%v ISM=%v SLM=%v, 期望 %v %v %v", caps, caps.ILM, caps.ISM, caps.SLM, tt.ilm, tt.ism, tt.slm)
+		}
+		if got := caps.NodeStatsMetrics(); got != tt.nodeMetrics {
+			t.Errorf("%s: 节点指标 = %s, 期望 %s", caps, got, tt.nodeMetrics)
+		}
+	}
+}
+
+func TestOpenSearchLifecycle(t *testing.T) {
+	cl := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`{"cluster_name":"os","version":{"distribution":"opensearch","number":"2.11.0"}}`))
+		case "/_plugins/_ism/explain/*,.ds-*":
+			w.Write([]byte(`{
+				"logs-000001": {"index": "logs-000001", "policy_id": "hot-delete", "enabled": true,
+					"state": {"name": "hot", "start_time": 1000},
+					"action": {"name": "rollover", "start_time": 2000, "failed": true, "consumed_retries": 3},
+					"step": {"name": "attempt_rollover", "start_time": 3000, "step_status": "failed"},
+					"info": {"message": "Failed to rollover index", "cause": "alias missing"}},
+				"logs-000002": {"index": "logs-000002", "policy_id": "hot-delete", "enabled": true,
+					"state": {"name": "hot", "start_time": 1000},
+					"action": {"name": "rollover", "start_time": 2000},
+					"step": {"name": "attempt_rollover", "start_time": 3000, "step_status": "condition_not_met"}},
+				"users": {"index.plugins.index_state_management.policy_id": null, "enabled": null},
+				"total_managed_indices": 2
+			}`))
+		default:
+			t.Errorf("意外的请求: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+	if err := cl.Ping(ctx); err != nil {
+		t.Fatalf("Ping 失败: %v", err)
+	}
+
+	explain, err := cl.GetILMExplain(ctx)
+	if err != nil {
+		t.Fatalf("获取 ISM 状态失败: %v", err)
+	}
+	if len(explain.Indices) != 3 {
+		t.Fatalf("索引数 = %d, 期望 3", len(explain.Indices))
+	}
+	failed := explain.Indices["logs-000001"]
+	if !failed.Managed || failed.Step != "ERROR" || failed.FailedStep != "attempt_rollover" || failed.FailedStepRetryCount != 3 {
+		t.Errorf("失败的索引 = %+v", failed)
+	}
+	if failed.StepInfo == nil || failed.StepInfo.Message != "Failed to rollover index: alias missing" {
+		t.Errorf("失败原因 = %+v", failed.StepInfo)
+	}
+	waiting := explain.Indices["logs-000002"]
+	if waiting.Phase != "hot" || waiting.Action != "rollover" || waiting.Step != "attempt_rollover" || waiting.StepTimeMillis != 3000 {
+		t.Errorf("等待滚动的索引 = %+v", waiting)
+	}
+	if explain.Indices["users"].Managed {
+		t.Error("没有 ISM 策略的索引不应视为受管理")
+	}
+
+	var unsupported *UnsupportedError
+	if _, err := cl.GetSLMPolicies(ctx); !errors.As(err, &unsupported) {
+		t.Errorf("OpenSearch 上获取 SLM 策略应返回不支持: %v", err)
+	}
+}
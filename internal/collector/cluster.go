@@ -23,3 +23,8 @@ func NewClusterCollector(client *client.ElasticsearchClient) *ClusterCollector {
 func (c *ClusterCollector) Collect(ctx context.Context) (*model.ClusterHealth, error) {
 	return c.client.GetClusterHealth(ctx)
 }
+
+// CollectHealthReport 采集健康报告（只读操作，ES 8.7+）
+func (c *ClusterCollector) CollectHealthReport(ctx context.Context) (*model.HealthReport, error) {
+	return c.client.GetHealthReport(ctx)
+}
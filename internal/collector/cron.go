@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears 查找下一次执行时间的最大范围
+const cronSearchYears = 5
+
+// cronSchedule ES 快照生命周期使用的 cron 表达式（UTC）：
+// 秒 分 时 日 月 星期 [年]，星期 1-7 对应 SUN-SAT
+// 支持 *、?、列表、范围、步长和月份 / 星期名称，不支持 L、W、#
+type cronSchedule struct {
+	seconds, minutes, hours []bool
+	days, months, weekdays  []bool
+	years                   map[int]bool // 为空表示每年
+}
+
+// cronField cron 字段的取值范围和名称
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // 从 min 开始的名称（如 JAN、SUN）
+}
+
+var cronFields = []cronField{
+	{name: "秒", min: 0, max: 59},
+	{name: "分", min: 0, max: 59},
+	{name: "时", min: 0, max: 23},
+	{name: "日", min: 1, max: 31},
+	{name: "月", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "星期", min: 1, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+	{name: "年", min: 1970, max: 2199},
+}
+
+// parseCron 解析 cron 表达式
+func parseCron(expr string) (*cronSchedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 6 && len(parts) != 7 {
+		return nil, fmt.Errorf("cron 表达式应为 6 或 7 个字段: %s", expr)
+	}
+
+	sets := make([][]bool, len(parts))
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("无效的 cron 表达式 %s: %w", expr, err)
+		}
+		sets[i] = set
+	}
+
+	s := &cronSchedule{
+		seconds:  sets[0],
+		minutes:  sets[1],
+		hours:    sets[2],
+		days:     sets[3],
+		months:   sets[4],
+		weekdays: sets[5],
+	}
+	if len(parts) == 7 && parts[6] != "*" && parts[6] != "?" {
+		s.years = make(map[int]bool)
+		for y, ok := range sets[6] {
+			if ok {
+				s.years[y] = true
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseCronField 解析单个字段，返回按值索引的集合
+func parseCronField(s string, f cronField) ([]bool, error) {
+	set := make([]bool, f.max+1)
+	for _, item := range strings.Split(strings.ToUpper(s), ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			v, err := strconv.Atoi(stepPart)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("%s字段的步长无效: %s", f.name, item)
+			}
+			step = v
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		default:
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(from, f); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = cronValue(to, f); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("%s字段的范围无效: %s", f.name, item)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// cronValue 解析字段中的数值或名称
+func cronValue(s string, f cronField) (int, error) {
+	for i, name := range f.names {
+		if s == name {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s字段的值无效: %s", f.name, s)
+	}
+	return v, nil
+}
+
+// next 返回晚于 t 的下一次执行时间（UTC），在 cronSearchYears 年内没有执行时间时返回零值
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Second).Add(time.Second)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(cronSearchYears, 0, 0)
+
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !s.matchDay(day) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if !s.hours[h] {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				if !s.minutes[m] {
+					continue
+				}
+				for sec := 0; sec < 60; sec++ {
+					if !s.seconds[sec] {
+						continue
+					}
+					if at := day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second); !at.Before(t) {
+						return at
+					}
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay 判断日期是否满足日、月、星期和年字段
+func (s *cronSchedule) matchDay(day time.Time) bool {
+	if s.years != nil && !s.years[day.Year()] {
+		return false
+	}
+	return s.months[int(day.Month())] && s.days[day.Day()] && s.weekdays[int(day.Weekday())+1]
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"0 30 1 * *",          // 字段过少
+		"0 30 1 * * ? 2025 1", // 字段过多
+		"60 30 1 * * ?",       // 秒超出范围
+		"0 30 24 * * ?",       // 时超出范围
+		"0 30 1 0 * ?",        // 日从 1 开始
+		"0 30 1 * 13 ?",       // 月超出范围
+		"0 30 1 ? * 0",        // 星期为 1-7（SUN-SAT）
+		"0 30 5-1 * * ?",      // 范围颠倒
+		"0 */0 * * * ?",       // 步长为 0
+		"0 0/x * * * ?",       // 步长不是数字
+		"0 30 1 L * ?",        // 不支持 L
+		"0 30 1 ? * 6#3",      // 不支持 #
+		"0 30 1 15W * ?",      // 不支持 W
+		"0 30 1 * FOO ?",      // 未知名称
+	} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) 应返回错误", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04:05", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for _, c := range []struct {
+		expr, from, want string // want 为空表示搜索范围内没有执行时间
+	}{
+		{"0 30 1 * * ?", "2025-01-01 00:00:00", "2025-01-01 01:30:00"},
+		{"0 30 1 * * ?", "2025-01-01 01:30:00", "2025-01-02 01:30:00"}, // 严格晚于起点
+		{"0 30 1 * * ?", "2025-12-31 02:00:00", "2026-01-01 01:30:00"}, // 跨年
+		{"0 0/15 * * * ?", "2025-01-01 10:07:30", "2025-01-01 10:15:00"},
+		{"0 0 */6 * * ?", "2025-01-01 18:00:01", "2025-01-02 00:00:00"},
+		{"0 0 9 ? * MON-FRI", "2025-01-03 10:00:00", "2025-01-06 09:00:00"}, // 周五之后是周一
+		{"0 0 0 ? * 1", "2025-01-01 00:00:00", "2025-01-05 00:00:00"},       // 1 为周日
+		{"0 0 0 ? * sun", "2025-01-01 00:00:00", "2025-01-05 00:00:00"},     // 名称不区分大小写
+		{"0 0 0 31 * ?", "2025-04-01 00:00:00", "2025-05-31 00:00:00"},      // 跳过没有 31 日的月份
+		{"0 0 0 29 FEB ?", "2025-01-01 00:00:00", "2028-02-29 00:00:00"},    // 闰年
+		{"0 0 0 1 1 ? 2027", "2025-01-01 00:00:00", "2027-01-01 00:00:00"},
+		{"0 0 0 1,15 * ?", "2025-01-02 00:00:00", "2025-01-15 00:00:00"},
+		{"0 0 0 30 2 ?", "2025-01-01 00:00:00", ""},     // 2 月没有 30 日
+		{"0 0 0 1 1 ? 2020", "2025-01-01 00:00:00", ""}, // 年份已过
+	} {
+		s, err := parseCron(c.expr)
+		if err != nil {
+			t.Errorf("parseCron(%q) 失败: %v", c.expr, err)
+			continue
+		}
+		got := s.next(at(c.from))
+		switch {
+		case c.want == "" && !got.IsZero():
+			t.Errorf("%q 从 %s 开始的下一次执行 = %v, 期望没有", c.expr, c.from, got)
+		case c.want != "" && !got.Equal(at(c.want)):
+			t.Errorf("%q 从 %s 开始的下一次执行 = %v, 期望 %s", c.expr, c.from, got, c.want)
+		}
+	}
+}
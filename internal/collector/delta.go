@@ -0,0 +1,48 @@
+package collector
+
+// counterSample 累计计数器的一次采样
+type counterSample struct {
+	value     int64
+	timestamp int64 // 节点统计中的时间戳（毫秒）
+}
+
+// counterDeltas 跟踪累计计数器在相邻两次采集之间的增量
+// 时间间隔使用 ES 返回的节点时间戳，不受采集延迟和回放倍速影响；
+// 每次采集后调用 commit，已消失的节点和线程池随之清除
+type counterDeltas struct {
+	prev map[string]counterSample
+	next map[string]counterSample
+}
+
+// newCounterDeltas 创建计数器增量跟踪
+func newCounterDeltas() *counterDeltas {
+	return &counterDeltas{
+		prev: make(map[string]counterSample),
+		next: make(map[string]counterSample),
+	}
+}
+
+// observe 记录计数器当前值，返回本周期增量和每秒速率
+// 首次出现时 ok 为 false；计数器变小（节点重启）时以当前值作为增量
+func (d *counterDeltas) observe(key string, value, timestamp int64) (delta int64, perSec float64, ok bool) {
+	d.next[key] = counterSample{value: value, timestamp: timestamp}
+
+	prev, found := d.prev[key]
+	if !found {
+		return 0, 0, false
+	}
+	delta = value - prev.value
+	if delta < 0 {
+		delta = value
+	}
+	if elapsed := timestamp - prev.timestamp; elapsed > 0 {
+		perSec = float64(delta) * 1000 / float64(elapsed)
+	}
+	return delta, perSec, true
+}
+
+// commit 结束本次采集，本次采样成为下次的基准
+func (d *counterDeltas) commit() {
+	d.prev = d.next
+	d.next = make(map[string]counterSample, len(d.prev))
+}
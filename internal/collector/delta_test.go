@@ -0,0 +1,77 @@
+package collector
+
+import "testing"
+
+func TestCounterDeltas(t *testing.T) {
+	type sample struct {
+		key              string
+		value, timestamp int64
+	}
+	type result struct {
+		delta  int64
+		perSec float64
+		ok     bool
+	}
+	// 每个周期依次观测的计数器及期望结果，周期结束后调用 commit
+	cycles := []struct {
+		samples []sample
+		want    []result
+	}{
+		{
+			// 首次观测没有基准
+			[]sample{{"node-1/write", 100, 10000}, {"node-2/write", 50, 10000}},
+			[]result{{0, 0, false}, {0, 0, false}},
+		},
+		{
+			[]sample{{"node-1/write", 120, 12000}, {"node-2/write", 50, 12000}},
+			[]result{{20, 10, true}, {0, 0, true}},
+		},
+		{
+			// node-1 重启：计数器变小，以当前值作为增量；node-2 时间戳未前进，不计算速率
+			[]sample{{"node-1/write", 6, 14000}, {"node-2/write", 60, 12000}},
+			[]result{{6, 3, true}, {10, 0, true}},
+		},
+		{
+			// node-2 本周期消失，commit 后被清除
+			[]sample{{"node-1/write", 10, 15000}},
+			[]result{{4, 4, true}},
+		},
+		{
+			// node-2 重新出现时重新建立基准
+			[]sample{{"node-1/write", 10, 17000}, {"node-2/write", 80, 17000}},
+			[]result{{0, 0, true}, {0, 0, false}},
+		},
+		{
+			// 时间戳倒退（节点时钟回拨）时只返回增量
+			[]sample{{"node-1/write", 30, 16000}},
+			[]result{{20, 0, true}},
+		},
+	}
+
+	d := newCounterDeltas()
+	for i, c := range cycles {
+		for j, s := range c.samples {
+			delta, perSec, ok := d.observe(s.key, s.value, s.timestamp)
+			w := c.want[j]
+			if delta != w.delta || perSec != w.perSec || ok != w.ok {
+				t.Errorf("周期 %d: %s = (%d, %.2f/s, %v), 期望 (%d, %.2f/s, %v)", i+1, s.key, delta, perSec, ok, w.delta, w.perSec, w.ok)
+			}
+		}
+		d.commit()
+		if len(d.prev) != len(c.samples) || len(d.next) != 0 {
+			t.Errorf("周期 %d: commit 后基准数 = %d, 待提交 = %d, 期望 %d, 0", i+1, len(d.prev), len(d.next), len(c.samples))
+		}
+	}
+}
+
+func TestCounterDeltasWithoutCommit(t *testing.T) {
+	// 同一周期内重复观测使用同一基准，未提交的采样不影响增量
+	d := newCounterDeltas()
+	d.observe("k", 10, 1000)
+	d.commit()
+	for i := 0; i < 2; i++ {
+		if delta, _, ok := d.observe("k", 15, 2000); delta != 5 || !ok {
+			t.Errorf("第 %d 次观测: 增量 = %d (%v), 期望 5", i+1, delta, ok)
+		}
+	}
+}
@@ -3,156 +3,664 @@ package collector
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
 	"github.com/Y-vQv-Y/es-monitor/internal/model"
 )
 
 // EnhancedCollector 增强监控采集器
 type EnhancedCollector struct {
-	client *client.ElasticsearchClient
+	client     *client.ElasticsearchClient
+	thresholds *config.Thresholds
+	rejected   *counterDeltas // 线程池拒绝数（按节点 + 线程池）
+	tripped    *counterDeltas // 断路器触发次数（按节点 + 断路器）
+	oldGC      *counterDeltas // 老年代 GC 次数（按节点）
+	issues     *IssueTracker  // 跨周期跟踪的健康问题
 }
 
-// NewEnhancedCollector 创建增强采集器
-func NewEnhancedCollector(client *client.ElasticsearchClient) *EnhancedCollector {
+// NewEnhancedCollector 创建增强采集器，thresholds 为空时使用 DefaultThresholds
+func NewEnhancedCollector(client *client.ElasticsearchClient, thresholds *config.Thresholds) *EnhancedCollector {
+	if thresholds == nil {
+		thresholds = &config.DefaultThresholds
+	}
 	return &EnhancedCollector{
-		client: client,
+		client:     client,
+		thresholds: thresholds,
+		rejected:   newCounterDeltas(),
+		tripped:    newCounterDeltas(),
+		oldGC:      newCounterDeltas(),
+		issues:     NewIssueTracker(),
 	}
 }
 
-// Collect 采集增强指标
-func (c *EnhancedCollector) Collect(ctx context.Context, nodeStats *model.NodeStats) (*model.EnhancedMetrics, error) {
+// EnhancedInput 增强采集的输入（同一周期已采集的数据，采集失败的项为 nil）
+type EnhancedInput struct {
+	NodeStats  *model.NodeStats
+	Health     *model.ClusterHealth
+	System     *model.SystemMetrics // 本机系统指标（回放模式下为 nil）
+	Indices    []model.IndexInfo    // 索引列表（_cat/indices，字节数为数值）
+	IndexStats *model.IndexStats    // 索引统计（段数等）
+	Pending    *model.PendingTaskOverview
+	Tasks      *model.TaskOverview
+	Lifecycle  *model.LifecycleOverview
+	Snapshots  *model.SnapshotOverview
+}
+
+// Collect 采集增强指标，健康问题跨周期合并（保留首次发现时间）
+func (c *EnhancedCollector) Collect(ctx context.Context, in EnhancedInput) (*model.EnhancedMetrics, error) {
 	metrics := &model.EnhancedMetrics{
 		HealthIssues: make([]model.HealthIssue, 0),
 	}
+	now := c.client.Now()
+
+	// 本周期检查过的问题来源（未检查的来源保持原有问题状态）
+	var checked []string
+
+	if in.NodeStats != nil {
+		// 1. 分析线程池状态
+		metrics.NodeThreadPools = c.analyzeThreadPools(in.NodeStats)
+
+		// 2. 分析断路器状态
+		metrics.NodeCircuitBreakers = c.analyzeCircuitBreakers(in.NodeStats)
+
+		// 3. 检查节点健康问题
+		c.checkHealthIssues(in.NodeStats, metrics, now.Unix())
+		checked = append(checked, "node")
+
+		// 4. 汇总集群段、缓存和事务日志统计
+		metrics.ClusterStats = summarizeClusterStats(in.NodeStats)
+	}
+
+	// 5. 检查集群状态
+	if in.Health != nil {
+		c.checkClusterIssues(in.Health, metrics, now.Unix())
+		checked = append(checked, "cluster")
+	}
+
+	// 6. 检查本机系统资源
+	if in.System != nil {
+		c.checkSystemIssues(in.System, metrics, now.Unix())
+		checked = append(checked, "system")
+	}
+
+	// 7. 检查索引分片大小
+	if in.Indices != nil {
+		c.checkIndexIssues(in.Indices, metrics, now.Unix())
+		checked = append(checked, "index")
+	}
 
-	// 1. 分析线程池状态
-	metrics.NodeThreadPools = c.analyzeThreadPools(nodeStats)
-	
-	// 2. 分析断路器状态
-	metrics.NodeCircuitBreakers = c.analyzeCircuitBreakers(nodeStats)
-	
-	// 3. 检查集群健康问题
-	c.checkHealthIssues(nodeStats, metrics)
-	
+	// 8. 检查主分片段数（需要索引列表中的主分片数）
+	if in.Indices != nil && in.IndexStats != nil {
+		c.checkSegmentIssues(in.Indices, in.IndexStats, metrics, now.Unix())
+		checked = append(checked, "segments")
+	}
+
+	// 9. 检查集群任务排队
+	if in.Pending != nil {
+		c.checkPendingTaskIssues(in.Pending, metrics, now.Unix())
+		checked = append(checked, "pending")
+	}
+
+	// 10. 检查长时间运行的任务
+	if in.Tasks != nil {
+		c.checkTaskIssues(in.Tasks, metrics, now.Unix())
+		checked = append(checked, "task")
+	}
+
+	// 11. 检查索引生命周期（出错、滚动逾期、停留过久）
+	if in.Lifecycle != nil {
+		c.checkLifecycleIssues(in.Lifecycle, metrics, now.Unix())
+		checked = append(checked, "ilm")
+	}
+
+	// 12. 检查快照策略是否在成功窗口内成功
+	if in.Snapshots != nil {
+		c.checkSnapshotIssues(in.Snapshots, metrics, now.Unix())
+		checked = append(checked, "snapshot")
+	}
+
+	metrics.HealthIssues = c.issues.Update(metrics.HealthIssues, checked, now)
 	return metrics, nil
 }
 
-// analyzeThreadPools 分析线程池
-func (c *EnhancedCollector) analyzeThreadPools(nodeStats *model.NodeStats) map[string]model.ThreadPoolStats {
-	// 这里需要从 ES API 获取线程池数据
-	// 简化实现，实际需要调用 /_nodes/stats/thread_pool
-	pools := make(map[string]model.ThreadPoolStats)
-	
-	// 示例数据结构（需要添加到 NodeStat 中）
-	// 关键线程池：search, write, get, bulk, management
-	
-	return pools
-}
-
-// analyzeCircuitBreakers 分析断路器
-func (c *EnhancedCollector) analyzeCircuitBreakers(nodeStats *model.NodeStats) map[string]model.CircuitBreakerStats {
-	breakers := make(map[string]model.CircuitBreakerStats)
-	
-	// 需要从 /_nodes/stats 中的 breakers 字段获取
-	// 关键断路器：parent, fielddata, request, in_flight_requests
-	
-	return breakers
-}
-
-// checkHealthIssues 检查健康问题
-func (c *EnhancedCollector) checkHealthIssues(nodeStats *model.NodeStats, metrics *model.EnhancedMetrics) {
-	now := time.Now().Unix()
-	
-	for _, node := range nodeStats.Nodes {
+// analyzeThreadPools 分析线程池（按节点 ID、线程池统计，拒绝数换算为本周期增量和每秒速率）
+func (c *EnhancedCollector) analyzeThreadPools(nodeStats *model.NodeStats) map[string]map[string]model.ThreadPoolStats {
+	result := make(map[string]map[string]model.ThreadPoolStats, len(nodeStats.Nodes))
+	for nodeID, node := range nodeStats.Nodes {
+		if len(node.ThreadPool) == 0 {
+			continue
+		}
+		pools := make(map[string]model.ThreadPoolStats, len(node.ThreadPool))
+		for name, pool := range node.ThreadPool {
+			stats := model.ThreadPoolStats{
+				NodeName:  node.Name,
+				PoolName:  name,
+				Active:    pool.Active,
+				Queue:     pool.Queue,
+				Rejected:  pool.Rejected,
+				Completed: pool.Completed,
+				Threads:   pool.Threads,
+				Largest:   pool.Largest,
+			}
+			stats.RejectedDelta, stats.RejectedPerSec, stats.RateValid =
+				c.rejected.observe(nodeID+"/"+name, pool.Rejected, node.Timestamp)
+			pools[name] = stats
+		}
+		result[nodeID] = pools
+	}
+	c.rejected.commit()
+	return result
+}
+
+// analyzeCircuitBreakers 分析断路器（按节点 ID、断路器统计使用率和本周期触发次数）
+func (c *EnhancedCollector) analyzeCircuitBreakers(nodeStats *model.NodeStats) map[string]map[string]model.CircuitBreakerStats {
+	result := make(map[string]map[string]model.CircuitBreakerStats, len(nodeStats.Nodes))
+	for nodeID, node := range nodeStats.Nodes {
+		if len(node.Breakers) == 0 {
+			continue
+		}
+		breakers := make(map[string]model.CircuitBreakerStats, len(node.Breakers))
+		for name, b := range node.Breakers {
+			stats := model.CircuitBreakerStats{
+				NodeName:    node.Name,
+				NodeRoles:   node.Roles,
+				Name:        name,
+				LimitSizeMB: float64(b.LimitSizeInBytes) / 1024 / 1024,
+				EstimatedMB: float64(b.EstimatedSizeInBytes) / 1024 / 1024,
+				Overhead:    b.Overhead,
+				Tripped:     b.Tripped,
+			}
+			if b.LimitSizeInBytes > 0 {
+				stats.UsedPercent = float64(b.EstimatedSizeInBytes) / float64(b.LimitSizeInBytes) * 100
+			}
+			stats.TrippedDelta, _, stats.RateValid = c.tripped.observe(nodeID+"/"+name, b.Tripped, node.Timestamp)
+			breakers[name] = stats
+		}
+		result[nodeID] = breakers
+	}
+	c.tripped.commit()
+	return result
+}
+
+// checkHealthIssues 检查节点健康问题
+func (c *EnhancedCollector) checkHealthIssues(nodeStats *model.NodeStats, metrics *model.EnhancedMetrics, now int64) {
+	defer c.oldGC.commit()
+	metrics.NodeOldGC = make(map[string]int64, len(nodeStats.Nodes))
+
+	for nodeID, node := range nodeStats.Nodes {
+		keyPrefix := "node/" + node.Name + "/"
+
+		thresholds := c.thresholds.ForNode(node.Roles)
+
 		// 1. JVM 堆内存问题
-		if node.JVM.Mem.HeapUsedPercent >= 85 {
-			metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
-				Level:      "critical",
+		heapPercent := node.JVM.Mem.HeapUsedPercent
+		if level, threshold := config.Level(float64(heapPercent), thresholds.JVMHeapWarning, thresholds.JVMHeapCritical); level != "" {
+			issue := model.HealthIssue{
+				Level:      level,
 				Component:  "jvm",
 				NodeName:   node.Name,
-				Message:    fmt.Sprintf("JVM 堆内存使用率过高: %d%%", node.JVM.Mem.HeapUsedPercent),
-				Value:      node.JVM.Mem.HeapUsedPercent,
-				Threshold:  85,
+				Key:        keyPrefix + "jvm.heap",
+				Message:    fmt.Sprintf("JVM 堆内存使用率过高: %d%%", heapPercent),
+				Value:      heapPercent,
+				Threshold:  threshold,
 				Timestamp:  now,
 				Suggestion: "增加堆内存或优化查询，检查是否有内存泄漏",
-			})
-		} else if node.JVM.Mem.HeapUsedPercent >= 75 {
+			}
+			if level == "warning" {
+				issue.Message = fmt.Sprintf("JVM 堆内存使用率偏高: %d%%", heapPercent)
+				issue.Suggestion = "关注内存使用趋势，考虑优化查询或增加堆内存"
+			}
+			metrics.HealthIssues = append(metrics.HealthIssues, issue)
+		}
+
+		// 2. 本周期发生 Full GC（累计次数随运行时间增长，只看增量）
+		oldGC, _, ok := c.oldGC.observe(nodeID, int64(node.JVM.GC.Collectors.Old.CollectionCount), node.Timestamp)
+		if ok {
+			metrics.NodeOldGC[nodeID] = oldGC
+		}
+		if level, threshold := config.Level(float64(oldGC), thresholds.OldGCWarning, thresholds.OldGCCritical); level != "" {
 			metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
-				Level:      "warning",
+				Level:      level,
 				Component:  "jvm",
 				NodeName:   node.Name,
-				Message:    fmt.Sprintf("JVM 堆内存使用率偏高: %d%%", node.JVM.Mem.HeapUsedPercent),
-				Value:      node.JVM.Mem.HeapUsedPercent,
-				Threshold:  75,
+				Key:        keyPrefix + "jvm.old_gc",
+				Message:    fmt.Sprintf("本周期发生 Full GC: %d 次", oldGC),
+				Value:      oldGC,
+				Threshold:  threshold,
 				Timestamp:  now,
-				Suggestion: "关注内存使用趋势，考虑优化查询或增加堆内存",
+				Suggestion: "检查堆内存配置，优化查询和聚合，减少 fielddata 使用",
 			})
 		}
 
-		// 2. Full GC 频繁
-		if node.JVM.GC.Collectors.Old.CollectionCount > 10 {
+		// 3. 节点 CPU 使用率
+		cpuPercent := node.OS.CPU.Percent
+		if level, threshold := config.Level(float64(cpuPercent), thresholds.CPUWarning, thresholds.CPUCritical); level != "" {
 			metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
-				Level:      "warning",
-				Component:  "jvm",
+				Level:      level,
+				Component:  "cpu",
 				NodeName:   node.Name,
-				Message:    fmt.Sprintf("Full GC 次数过多: %d", node.JVM.GC.Collectors.Old.CollectionCount),
-				Value:      node.JVM.GC.Collectors.Old.CollectionCount,
-				Threshold:  10,
+				Key:        keyPrefix + "cpu",
+				Message:    fmt.Sprintf("节点 CPU 使用率过高: %d%%", cpuPercent),
+				Value:      cpuPercent,
+				Threshold:  threshold,
 				Timestamp:  now,
-				Suggestion: "检查堆内存配置，优化查询和聚合，减少 fielddata 使用",
+				Suggestion: "使用 _nodes/hot_threads 查看热点线程，检查慢查询和段合并",
 			})
 		}
 
-		// 3. 磁盘空间不足
-		diskTotal := float64(node.FS.Total.TotalInBytes) / 1024 / 1024 / 1024
-		diskAvail := float64(node.FS.Total.AvailableInBytes) / 1024 / 1024 / 1024
-		diskUsedPercent := (diskTotal - diskAvail) / diskTotal * 100
+		// 4. 磁盘空间不足
+		if node.FS.Total.TotalInBytes > 0 {
+			diskUsedPercent := float64(node.FS.Total.TotalInBytes-node.FS.Total.AvailableInBytes) / float64(node.FS.Total.TotalInBytes) * 100
+			if level, threshold := config.Level(diskUsedPercent, thresholds.DiskWarning, thresholds.DiskCritical); level != "" {
+				issue := model.HealthIssue{
+					Level:      level,
+					Component:  "disk",
+					NodeName:   node.Name,
+					Key:        keyPrefix + "disk",
+					Message:    fmt.Sprintf("磁盘空间严重不足: %.1f%%", diskUsedPercent),
+					Value:      diskUsedPercent,
+					Threshold:  threshold,
+					Timestamp:  now,
+					Suggestion: "立即清理旧索引或扩展磁盘容量，启用 ILM 策略",
+				}
+				if level == "warning" {
+					issue.Message = fmt.Sprintf("磁盘空间不足: %.1f%%", diskUsedPercent)
+					issue.Suggestion = "计划清理旧索引或扩展磁盘，检查索引增长速度"
+				}
+				metrics.HealthIssues = append(metrics.HealthIssues, issue)
+			}
+		}
+
+		// 5. 文件描述符使用率
+		if node.Process.MaxFileDescriptors > 0 {
+			fdPercent := float64(node.Process.OpenFileDescriptors) / float64(node.Process.MaxFileDescriptors) * 100
+			if level, threshold := config.Level(fdPercent, thresholds.FileDescriptorWarning, thresholds.FileDescriptorCritical); level != "" {
+				metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+					Level:      level,
+					Component:  "system",
+					NodeName:   node.Name,
+					Key:        keyPrefix + "fd",
+					Message:    fmt.Sprintf("文件描述符使用率过高: %.1f%%", fdPercent),
+					Value:      fdPercent,
+					Threshold:  threshold,
+					Timestamp:  now,
+					Suggestion: "增加系统文件描述符限制: ulimit -n",
+				})
+			}
+		}
 
-		if diskUsedPercent >= 90 {
+		// 6. 本周期出现的写入 / 查询拒绝（累计值无意义，只看增量）
+		for _, poolName := range []string{"write", "search"} {
+			pool, ok := metrics.NodeThreadPools[nodeID][poolName]
+			if !ok || pool.RejectedDelta == 0 {
+				continue
+			}
 			metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
 				Level:      "critical",
-				Component:  "disk",
+				Component:  "thread_pool",
 				NodeName:   node.Name,
-				Message:    fmt.Sprintf("磁盘空间严重不足: %.1f%%", diskUsedPercent),
-				Value:      diskUsedPercent,
-				Threshold:  90.0,
+				Key:        keyPrefix + "thread_pool." + poolName,
+				Message:    fmt.Sprintf("%s 线程池拒绝请求: 本周期 %d 次 (%.1f 次/秒)", poolName, pool.RejectedDelta, pool.RejectedPerSec),
+				Value:      pool.RejectedDelta,
+				Threshold:  0,
 				Timestamp:  now,
-				Suggestion: "立即清理旧索引或扩展磁盘容量，启用 ILM 策略",
+				Suggestion: "降低写入/查询并发，检查热点分片和慢查询，必要时扩容节点",
 			})
-		} else if diskUsedPercent >= 85 {
+		}
+
+		// 7. 本周期触发的断路器（历史累计触发不告警）和接近限制的断路器
+		breakerNames := make([]string, 0, len(metrics.NodeCircuitBreakers[nodeID]))
+		for name := range metrics.NodeCircuitBreakers[nodeID] {
+			breakerNames = append(breakerNames, name)
+		}
+		sort.Strings(breakerNames)
+		for _, name := range breakerNames {
+			breaker := metrics.NodeCircuitBreakers[nodeID][name]
+			if breaker.TrippedDelta > 0 {
+				metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+					Level:      "critical",
+					Component:  "breaker",
+					NodeName:   node.Name,
+					Key:        keyPrefix + "breaker." + name,
+					Message:    fmt.Sprintf("%s 断路器本周期触发 %d 次 (使用率 %.1f%%)", name, breaker.TrippedDelta, breaker.UsedPercent),
+					Value:      breaker.TrippedDelta,
+					Threshold:  0,
+					Timestamp:  now,
+					Suggestion: "检查大聚合、fielddata 和批量请求大小，必要时增加堆内存",
+				})
+			}
+			if level, threshold := config.Level(breaker.UsedPercent, thresholds.BreakerWarning, thresholds.BreakerCritical); level != "" {
+				metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+					Level:      level,
+					Component:  "breaker",
+					NodeName:   node.Name,
+					Key:        keyPrefix + "breaker." + name + ".usage",
+					Message:    fmt.Sprintf("%s 断路器使用率接近限制: %.1f%% (%.0fMB / %.0fMB)", name, breaker.UsedPercent, breaker.EstimatedMB, breaker.LimitSizeMB),
+					Value:      breaker.UsedPercent,
+					Threshold:  threshold,
+					Timestamp:  now,
+					Suggestion: "减少大聚合和 fielddata 使用，控制批量请求大小，避免断路器触发",
+				})
+			}
+		}
+	}
+}
+
+// summarizeClusterStats 按节点汇总段、缓存和事务日志统计（各节点持有不同的分片副本，可直接相加）
+func summarizeClusterStats(nodeStats *model.NodeStats) model.ClusterStats {
+	var (
+		stats                 model.ClusterStats
+		queryCache            model.QueryCacheStats
+		requestCache          model.RequestCacheStats
+		segmentBytes, merging int64
+		fieldData, translog   int64
+		uncommitted           int64
+	)
+	for _, node := range nodeStats.Nodes {
+		indices := node.Indices
+		stats.TotalSegments += indices.Segments.Count
+		segmentBytes += indices.Segments.MemoryInBytes
+		stats.MergesCurrent += indices.Merges.Current
+		merging += indices.Merges.CurrentSizeInBytes
+
+		fieldData += indices.FieldData.MemorySizeInBytes
+		stats.FieldDataEvictions += indices.FieldData.Evictions
+		queryCache.MemorySizeInBytes += indices.QueryCache.MemorySizeInBytes
+		queryCache.HitCount += indices.QueryCache.HitCount
+		queryCache.MissCount += indices.QueryCache.MissCount
+		stats.QueryCacheEvictions += indices.QueryCache.Evictions
+		requestCache.MemorySizeInBytes += indices.RequestCache.MemorySizeInBytes
+		requestCache.HitCount += indices.RequestCache.HitCount
+		requestCache.MissCount += indices.RequestCache.MissCount
+
+		stats.TranslogOperations += indices.Translog.Operations
+		translog += indices.Translog.SizeInBytes
+		uncommitted += indices.Translog.UncommittedSizeInBytes
+	}
+
+	const mb = 1024 * 1024
+	stats.SegmentMemoryMB = float64(segmentBytes) / mb
+	stats.MergingMB = float64(merging) / mb
+	stats.FieldDataMemoryMB = float64(fieldData) / mb
+	stats.QueryCacheMemoryMB = float64(queryCache.MemorySizeInBytes) / mb
+	stats.RequestCacheMemoryMB = float64(requestCache.MemorySizeInBytes) / mb
+	stats.QueryCacheHitRatio = queryCache.HitRatio()
+	stats.RequestCacheHitRatio = requestCache.HitRatio()
+	stats.TranslogSizeMB = float64(translog) / mb
+	stats.TranslogUncommittedMB = float64(uncommitted) / mb
+	return stats
+}
+
+// checkClusterIssues 检查集群状态问题
+func (c *EnhancedCollector) checkClusterIssues(health *model.ClusterHealth, metrics *model.EnhancedMetrics, now int64) {
+	switch health.Status {
+	case "red":
+		metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+			Level:      "critical",
+			Component:  "cluster",
+			Key:        "cluster/status",
+			Message:    fmt.Sprintf("集群状态 RED: %d 个分片未分配，存在不可用的主分片", health.UnassignedShards),
+			Value:      health.UnassignedShards,
+			Threshold:  0,
+			Timestamp:  now,
+			Suggestion: "使用 _cluster/allocation/explain 查看主分片未分配原因，检查节点是否离线",
+		})
+	case "yellow":
+		metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+			Level:      "warning",
+			Component:  "cluster",
+			Key:        "cluster/status",
+			Message:    fmt.Sprintf("集群状态 YELLOW: %d 个副本分片未分配", health.UnassignedShards),
+			Value:      health.UnassignedShards,
+			Threshold:  0,
+			Timestamp:  now,
+			Suggestion: "检查数据节点数量是否满足副本数，以及磁盘水位是否阻止分配",
+		})
+	}
+}
+
+// checkSystemIssues 检查本机系统资源问题
+func (c *EnhancedCollector) checkSystemIssues(sys *model.SystemMetrics, metrics *model.EnhancedMetrics, now int64) {
+	thresholds := c.thresholds.ThresholdValues
+
+	// 1. CPU 使用率
+	if level, threshold := config.Level(sys.CPU.UsagePercent, thresholds.CPUWarning, thresholds.CPUCritical); level != "" {
+		metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+			Level:      level,
+			Component:  "system",
+			Key:        "system/cpu",
+			Message:    fmt.Sprintf("本机 CPU 使用率过高: %.1f%%", sys.CPU.UsagePercent),
+			Value:      sys.CPU.UsagePercent,
+			Threshold:  threshold,
+			Timestamp:  now,
+			Suggestion: "检查占用 CPU 的进程，确认是否有大量查询或段合并",
+		})
+	}
+
+	// 2. 内存使用率
+	if level, threshold := config.Level(sys.Memory.UsedPercent, thresholds.MemoryWarning, thresholds.MemoryCritical); level != "" {
+		metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+			Level:      level,
+			Component:  "system",
+			Key:        "system/memory",
+			Message:    fmt.Sprintf("本机内存不足: 已使用 %.1f%%", sys.Memory.UsedPercent),
+			Value:      sys.Memory.UsedPercent,
+			Threshold:  threshold,
+			Timestamp:  now,
+			Suggestion: "检查内存占用，确保为文件系统缓存保留足够内存",
+		})
+	}
+}
+
+// checkSegmentIssues 检查主分片平均段数（段过多时查询需要遍历更多段，文件句柄和堆内存开销增大）
+func (c *EnhancedCollector) checkSegmentIssues(indices []model.IndexInfo, stats *model.IndexStats, metrics *model.EnhancedMetrics, now int64) {
+	for _, idx := range indices {
+		primaries, err := strconv.Atoi(idx.Pri)
+		if err != nil || primaries == 0 {
+			continue
+		}
+		stat, ok := stats.Indices[idx.Index]
+		if !ok {
+			continue
+		}
+		perShard := float64(stat.Primaries.Segments.Count) / float64(primaries)
+
+		thresholds := c.thresholds.ForIndex(idx.Index)
+		if level, threshold := config.Level(perShard, thresholds.SegmentsPerShardWarning, thresholds.SegmentsPerShardCritical); level != "" {
 			metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
-				Level:      "warning",
-				Component:  "disk",
-				NodeName:   node.Name,
-				Message:    fmt.Sprintf("磁盘空间不足: %.1f%%", diskUsedPercent),
-				Value:      diskUsedPercent,
-				Threshold:  85.0,
+				Level:      level,
+				Component:  "index",
+				IndexName:  idx.Index,
+				Key:        "segments/" + idx.Index,
+				Message:    fmt.Sprintf("主分片平均段数过多: %.0f (%d 个主分片, 进行中的合并 %d)", perShard, primaries, stat.Total.Merges.Current),
+				Value:      perShard,
+				Threshold:  threshold,
 				Timestamp:  now,
-				Suggestion: "计划清理旧索引或扩展磁盘，检查索引增长速度",
+				Suggestion: "检查 refresh_interval 是否过短；不再写入的索引可在低峰期执行 _forcemerge",
 			})
 		}
+	}
+}
+
+// checkPendingTaskIssues 检查主节点任务队列中排队最久的任务
+func (c *EnhancedCollector) checkPendingTaskIssues(pending *model.PendingTaskOverview, metrics *model.EnhancedMetrics, now int64) {
+	if len(pending.Tasks) == 0 {
+		return
+	}
 
-		// 4. 文件描述符使用率
-		fdPercent := float64(node.Process.OpenFileDescriptors) / float64(node.Process.MaxFileDescriptors) * 100
-		if fdPercent >= 80 {
+	thresholds := c.thresholds.ThresholdValues
+	oldestSec := float64(pending.OldestMillis) / 1000
+	if level, threshold := config.Level(oldestSec, thresholds.PendingTaskWarningSec, thresholds.PendingTaskCriticalSec); level != "" {
+		oldest := pending.Tasks[0]
+		metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+			Level:      level,
+			Component:  "cluster",
+			Key:        "pending/oldest",
+			Message:    fmt.Sprintf("集群任务排队过久: %.0fs (%s, 队列深度 %d)", oldestSec, oldest.Source, len(pending.Tasks)),
+			Value:      oldestSec,
+			Threshold:  threshold,
+			Timestamp:  now,
+			Suggestion: "主节点处理集群状态更新过慢，检查映射更新、分片数量以及主节点负载",
+		})
+	}
+}
+
+// checkTaskIssues 检查运行时间过长的任务（只提示任务 ID，不会取消任务）
+func (c *EnhancedCollector) checkTaskIssues(tasks *model.TaskOverview, metrics *model.EnhancedMetrics, now int64) {
+	thresholds := c.thresholds.ThresholdValues
+	for _, task := range tasks.Tasks {
+		runningSec := float64(task.RunningMillis) / 1000
+		level, threshold := config.Level(runningSec, thresholds.LongTaskWarningSec, thresholds.LongTaskCriticalSec)
+		if level == "" {
+			continue
+		}
+
+		suggestion := fmt.Sprintf("确认任务是否预期运行这么久，需要终止时由运维人员手动执行 POST _tasks/%s/_cancel", task.TaskID)
+		if !task.Cancellable {
+			suggestion = "该任务不可取消，检查任务来源和节点负载"
+		}
+		metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
+			Level:      level,
+			Component:  "task",
+			NodeName:   task.NodeName,
+			Key:        "task/" + task.TaskID + "/running_time",
+			Message:    fmt.Sprintf("任务运行时间过长: %s %.0fs (%s)", task.Action, runningSec, task.TaskID),
+			Value:      runningSec,
+			Threshold:  threshold,
+			Timestamp:  now,
+			Suggestion: suggestion,
+		})
+	}
+}
+
+// checkLifecycleIssues 检查索引生命周期问题（只提示处理命令，不会重试或修改策略）
+func (c *EnhancedCollector) checkLifecycleIssues(lifecycle *model.LifecycleOverview, metrics *model.EnhancedMetrics, now int64) {
+	for _, s := range lifecycle.Indices {
+		issue := model.HealthIssue{
+			Component: "index",
+			IndexName: s.Index,
+			Timestamp: now,
+		}
+		switch s.Problem {
+		case model.LifecycleError:
+			issue.Level = "critical"
+			issue.Key = "ilm/" + s.Index + "/error"
+			if s.Managed == "dsl" {
+				issue.Message = fmt.Sprintf("数据流生命周期执行失败: %s", s.Reason)
+				issue.Suggestion = "根据错误原因修复（如分片数上限、磁盘水位），数据流生命周期会自动重试"
+			} else {
+				retries := ""
+				if s.RetryCount > 0 {
+					retries = fmt.Sprintf(" (已重试 %d 次)", s.RetryCount)
+				}
+				issue.Message = fmt.Sprintf("ILM 步骤执行失败: %s/%s/%s%s %s", s.Phase, s.Action, s.FailedStep, retries, s.Reason)
+				issue.Suggestion = fmt.Sprintf("根据错误原因修复后由运维人员手动执行 POST %s/_ilm/retry", s.Index)
+			}
+		case model.LifecycleRolloverOverdue:
+			issue.Level = "warning"
+			if s.Overdue >= 2 {
+				issue.Level = "critical"
+			}
+			issue.Key = "ilm/" + s.Index + "/rollover"
+			issue.Message = fmt.Sprintf("热阶段索引已满足滚动条件但未滚动: %s (%.1f 倍)", s.Reason, s.Overdue)
+			issue.Value = s.Overdue
+			issue.Threshold = 1
+			issue.Suggestion = "检查写别名（index.lifecycle.rollover_alias）是否指向该索引且该索引为写索引，以及 _ilm/explain 中的 step_info"
+		default:
+			stepMin := float64(s.StepMillis) / 1000 / 60
+			thresholds := c.thresholds.ForIndex(s.Index)
+			level, threshold := config.Level(stepMin, thresholds.ILMStepWarningMin, thresholds.ILMStepCriticalMin)
+			if level == "" {
+				continue
+			}
+			issue.Level = level
+			issue.Key = "ilm/" + s.Index + "/step"
+			issue.Message = fmt.Sprintf("ILM 长时间停留在同一步骤: %s/%s/%s %.0f 分钟", s.Phase, s.Action, s.Step, stepMin)
+			issue.Value = stepMin
+			issue.Threshold = threshold
+			issue.Suggestion = "查看 _ilm/explain 中的 step_info，常见原因为分片无法分配到目标节点（shrink、迁移阶段）或等待快照完成"
+		}
+		metrics.HealthIssues = append(metrics.HealthIssues, issue)
+	}
+}
+
+// checkSnapshotIssues 检查超过成功窗口仍未成功的快照策略
+// 超过配置的成功窗口时没有可用备份，按严重问题处理；按调度计划推算的窗口可能不准确（调度不规则、快照耗时较长），只作为警告
+func (c *EnhancedCollector) checkSnapshotIssues(snapshots *model.SnapshotOverview, metrics *model.EnhancedMetrics, now int64) {
+	for _, p := range snapshots.Policies {
+		if !p.Overdue {
+			continue
+		}
+
+		level, window := "critical", "成功窗口"
+		if !p.Configured {
+			level, window = "warning", "按调度推算的成功窗口"
+		}
+		message := fmt.Sprintf("快照策略 %s 超过%s %s 没有成功的快照", p.Policy, window, formatWindow(p.WindowMillis))
+		if p.LastSuccessAt == 0 {
+			message = fmt.Sprintf("快照策略 %s 从未成功", p.Policy)
+		}
+		suggestion := "检查快照仓库是否可用，以及 GET _slm/policy 中的 last_failure"
+		if p.FailureReason != "" {
+			suggestion = "最近一次失败原因: " + p.FailureReason
+		}
+		issue := model.HealthIssue{
+			Level:      level,
+			Component:  "snapshot",
+			Key:        "snapshot/" + p.Policy + "/overdue",
+			Message:    message,
+			Threshold:  float64(p.WindowMillis) / 1000 / 3600,
+			Timestamp:  now,
+			Suggestion: suggestion,
+		}
+		if p.LastSuccessAt > 0 {
+			issue.Value = float64(now*1000-p.LastSuccessAt) / 1000 / 3600
+		}
+		metrics.HealthIssues = append(metrics.HealthIssues, issue)
+	}
+}
+
+// formatWindow 格式化成功窗口（精确到分钟），如 36h、35h30m
+func formatWindow(millis int64) string {
+	s := strings.TrimSuffix((time.Duration(millis) * time.Millisecond).Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// checkIndexIssues 检查索引问题（按索引名匹配阈值覆盖规则）
+func (c *EnhancedCollector) checkIndexIssues(indices []model.IndexInfo, metrics *model.EnhancedMetrics, now int64) {
+	for _, idx := range indices {
+		// 主分片平均大小（过大的分片恢复和迁移缓慢）
+		primaries, err := strconv.Atoi(idx.Pri)
+		if err != nil || primaries == 0 {
+			continue
+		}
+		priBytes, err := strconv.ParseInt(idx.PriStoreSize, 10, 64)
+		if err != nil {
+			continue
+		}
+		shardGB := float64(priBytes) / float64(primaries) / 1024 / 1024 / 1024
+
+		thresholds := c.thresholds.ForIndex(idx.Index)
+		if level, threshold := config.Level(shardGB, thresholds.ShardSizeWarningGB, thresholds.ShardSizeCriticalGB); level != "" {
 			metrics.HealthIssues = append(metrics.HealthIssues, model.HealthIssue{
-				Level:      "warning",
-				Component:  "system",
-				NodeName:   node.Name,
-				Message:    fmt.Sprintf("文件描述符使用率过高: %.1f%%", fdPercent),
-				Value:      fdPercent,
-				Threshold:  80.0,
+				Level:      level,
+				Component:  "index",
+				IndexName:  idx.Index,
+				Key:        "index/" + idx.Index + "/shard_size",
+				Message:    fmt.Sprintf("主分片平均大小过大: %.1fGB (%d 个主分片)", shardGB, primaries),
+				Value:      shardGB,
+				Threshold:  threshold,
 				Timestamp:  now,
-				Suggestion: "增加系统文件描述符限制: ulimit -n",
+				Suggestion: "通过 rollover 或增加主分片数控制单个分片大小",
 			})
 		}
-
-		// 5. 段数量过多（影响性能）
-		// 需要从 indices stats 获取
-		// if segments > 1000 per shard {
-		//     warning: too many segments, need force merge
-		// }
 	}
 }
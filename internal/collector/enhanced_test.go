@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// gcNode 构造只带老年代 GC 次数的节点统计
+func gcNode(name string, roles []string, oldGC int, timestamp int64) model.NodeStat {
+	node := model.NodeStat{Name: name, Roles: roles, Timestamp: timestamp}
+	node.JVM.GC.Collectors.Old.CollectionCount = oldGC
+	return node
+}
+
+func TestOldGCDeltaSharedWithIssues(t *testing.T) {
+	c := NewEnhancedCollector(nil, &config.Thresholds{
+		ThresholdValues: config.ThresholdValues{OldGCWarning: 1, OldGCCritical: 5},
+		Overrides: []config.ThresholdOverride{{
+			NodeRole:        "data_frozen",
+			ThresholdValues: config.ThresholdValues{OldGCWarning: 3, OldGCCritical: 10},
+		}},
+	})
+
+	// 每个周期两个节点的累计 Full GC 次数；hot 在第 3 周期重启，计数器归零
+	cycles := []struct {
+		hot, frozen int
+		hotDelta    int64  // -1 表示不应有增量（首次采集）
+		frozenDelta int64  // 同上
+		hotLevel    string // 健康问题级别，空表示没有该问题
+		frozenLevel string
+	}{
+		{10, 10, -1, -1, "", ""},
+		{12, 12, 2, 2, "warning", ""},
+		{1, 15, 1, 3, "warning", "warning"},
+		{1, 25, 0, 10, "", "critical"},
+		{7, 25, 6, 0, "critical", ""},
+	}
+	for i, cy := range cycles {
+		ts := int64(i) * 2000
+		nodeStats := &model.NodeStats{Nodes: map[string]model.NodeStat{
+			"hot-id":    gcNode("hot", []string{"data_hot"}, cy.hot, ts),
+			"frozen-id": gcNode("frozen", []string{"data_frozen"}, cy.frozen, ts),
+		}}
+		metrics := &model.EnhancedMetrics{}
+		c.checkHealthIssues(nodeStats, metrics, ts/1000)
+
+		for _, n := range []struct {
+			id, key string
+			delta   int64
+			level   string
+		}{
+			{"hot-id", "node/hot/jvm.old_gc", cy.hotDelta, cy.hotLevel},
+			{"frozen-id", "node/frozen/jvm.old_gc", cy.frozenDelta, cy.frozenLevel},
+		} {
+			delta, ok := metrics.NodeOldGC[n.id]
+			if n.delta < 0 && ok {
+				t.Errorf("周期 %d: %s Full GC 增量 = %d, 期望首次采集没有增量", i+1, n.id, delta)
+			} else if n.delta >= 0 && (!ok || delta != n.delta) {
+				t.Errorf("周期 %d: %s Full GC 增量 = %d (存在 %v), 期望 %d", i+1, n.id, delta, ok, n.delta)
+			}
+
+			level := ""
+			for _, is := range metrics.HealthIssues {
+				if is.Key == n.key {
+					level = is.Level
+					if is.Value != delta {
+						t.Errorf("周期 %d: %s 健康问题的次数 = %v, 期望与面板一致 %d", i+1, n.key, is.Value, delta)
+					}
+				}
+			}
+			if level != n.level {
+				t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, n.key, level, n.level)
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+const (
+	// hotThreadsPerNode 每个节点采样的热点线程数
+	hotThreadsPerNode = 5
+	// hotThreadsInterval 单次采样的 CPU 统计间隔（与 ES 默认值一致）
+	hotThreadsInterval = 500 * time.Millisecond
+	// maxHotFrames 每个节点保留的热点栈帧数
+	maxHotFrames = 20
+)
+
+// threadPoolName 从线程名中提取线程池：elasticsearch[node-1][write][T#3] -> write
+var threadPoolName = regexp.MustCompile(`^elasticsearch\[[^\]]*\]\[([^\]]+)\]`)
+
+// HotThreadsCollector 热点线程采集器：保留最近 window 次采样并聚合为栈帧排名
+type HotThreadsCollector struct {
+	client  *client.ElasticsearchClient
+	nodes   string // 采样的节点（逗号分隔），为空表示全部节点
+	window  int
+	samples [][]model.NodeHotThreads // 最近的采样（从旧到新）
+}
+
+// NewHotThreadsCollector 创建热点线程采集器，window 为聚合的采样次数
+func NewHotThreadsCollector(client *client.ElasticsearchClient, nodes string, window int) *HotThreadsCollector {
+	return &HotThreadsCollector{
+		client: client,
+		nodes:  nodes,
+		window: max(window, 1),
+	}
+}
+
+// Sample 采样一次热点线程（只读操作）并返回窗口内的聚合结果
+func (c *HotThreadsCollector) Sample(ctx context.Context) (*model.HotThreadsProfile, error) {
+	sample, err := c.client.GetHotThreads(ctx, c.nodes, hotThreadsPerNode, hotThreadsInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	c.samples = append(c.samples, sample)
+	if len(c.samples) > c.window {
+		c.samples = c.samples[len(c.samples)-c.window:]
+	}
+	return c.Profile(), nil
+}
+
+// Profile 聚合窗口内的采样：每个调用栈按 线程 CPU 占比 × 快照占比 × 采样间隔 折算为 CPU 时间
+func (c *HotThreadsCollector) Profile() *model.HotThreadsProfile {
+	profile := &model.HotThreadsProfile{
+		Samples:        len(c.samples),
+		IntervalMillis: hotThreadsInterval.Milliseconds(),
+	}
+
+	type frameStats struct {
+		self, total float64
+		pools       map[string]bool
+	}
+	type nodeStats struct {
+		total  float64
+		frames map[string]*frameStats
+		stacks map[string]float64
+	}
+	nodes := make(map[string]*nodeStats)
+
+	for _, sample := range c.samples {
+		for _, node := range sample {
+			ns, ok := nodes[node.NodeName]
+			if !ok {
+				ns = &nodeStats{frames: make(map[string]*frameStats), stacks: make(map[string]float64)}
+				nodes[node.NodeName] = ns
+			}
+			for _, thread := range node.Threads {
+				pool := threadPool(thread.Name)
+				for _, stack := range thread.Stacks {
+					if stack.Total == 0 || len(stack.Frames) == 0 {
+						continue
+					}
+					millis := thread.CPUPercent / 100 * float64(profile.IntervalMillis) *
+						float64(stack.Snapshots) / float64(stack.Total)
+					ns.total += millis
+
+					// 折叠格式从根帧开始；递归调用的栈帧只计一次总耗时
+					folded := make([]string, 0, len(stack.Frames)+2)
+					folded = append(folded, node.NodeName, pool)
+					seen := make(map[string]bool, len(stack.Frames))
+					for i := len(stack.Frames) - 1; i >= 0; i-- {
+						frame := frameName(stack.Frames[i])
+						folded = append(folded, frame)
+
+						fs, ok := ns.frames[frame]
+						if !ok {
+							fs = &frameStats{pools: make(map[string]bool)}
+							ns.frames[frame] = fs
+						}
+						if !seen[frame] {
+							seen[frame] = true
+							fs.total += millis
+							fs.pools[pool] = true
+						}
+						if i == 0 {
+							fs.self += millis
+						}
+					}
+					ns.stacks[strings.Join(folded, ";")] += millis
+				}
+			}
+		}
+	}
+
+	for name, ns := range nodes {
+		np := model.NodeHotProfile{Node: name, TotalMillis: ns.total}
+		for frame, fs := range ns.frames {
+			hf := model.HotFrame{Frame: frame, SelfMillis: fs.self, TotalMillis: fs.total}
+			for pool := range fs.pools {
+				hf.Pools = append(hf.Pools, pool)
+			}
+			sort.Strings(hf.Pools)
+			np.Frames = append(np.Frames, hf)
+		}
+		sort.Slice(np.Frames, func(i, j int) bool {
+			a, b := np.Frames[i], np.Frames[j]
+			if a.SelfMillis != b.SelfMillis {
+				return a.SelfMillis > b.SelfMillis
+			}
+			if a.TotalMillis != b.TotalMillis {
+				return a.TotalMillis > b.TotalMillis
+			}
+			return a.Frame < b.Frame
+		})
+		if len(np.Frames) > maxHotFrames {
+			np.Frames = np.Frames[:maxHotFrames]
+		}
+
+		for stack, millis := range ns.stacks {
+			np.Stacks = append(np.Stacks, model.FoldedStack{Stack: stack, Millis: millis})
+		}
+		sort.Slice(np.Stacks, func(i, j int) bool {
+			a, b := np.Stacks[i], np.Stacks[j]
+			if a.Millis != b.Millis {
+				return a.Millis > b.Millis
+			}
+			return a.Stack < b.Stack
+		})
+		profile.Nodes = append(profile.Nodes, np)
+	}
+	sort.Slice(profile.Nodes, func(i, j int) bool {
+		a, b := profile.Nodes[i], profile.Nodes[j]
+		if a.TotalMillis != b.TotalMillis {
+			return a.TotalMillis > b.TotalMillis
+		}
+		return a.Node < b.Node
+	})
+	return profile
+}
+
+// WriteFolded 以折叠调用栈格式输出（每行 "节点;线程池;根帧;...;栈顶帧 毫秒数"），
+// 可直接交给 flamegraph.pl、speedscope 等火焰图工具
+func WriteFolded(w io.Writer, profile *model.HotThreadsProfile) error {
+	bw := bufio.NewWriter(w)
+	for _, node := range profile.Nodes {
+		for _, s := range node.Stacks {
+			millis := int64(math.Round(s.Millis))
+			if millis == 0 {
+				continue
+			}
+			if _, err := fmt.Fprintf(bw, "%s %d\n", s.Stack, millis); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// threadPool 返回线程所属的线程池，非 ES 线程池的线程返回线程名
+func threadPool(thread string) string {
+	if m := threadPoolName.FindStringSubmatch(thread); m != nil {
+		return m[1]
+	}
+	return thread
+}
+
+// frameName 去掉栈帧的类加载器、模块前缀和源码位置：
+// app//org.apache.lucene.index.IndexWriter.flush(IndexWriter.java:3900) -> org.apache.lucene.index.IndexWriter.flush
+// lambda 等隐藏类的类名本身带有 /0x...（如 ActionListener$$Lambda/0x00000008018f3a8），只去掉其前面的前缀
+func frameName(frame string) string {
+	frame = strings.TrimPrefix(frame, "at ")
+	if idx := strings.IndexByte(frame, '('); idx != -1 {
+		frame = frame[:idx]
+	}
+	prefix := frame
+	if idx := strings.Index(frame, "/0x"); idx != -1 {
+		prefix = frame[:idx]
+	}
+	if idx := strings.LastIndexByte(prefix, '/'); idx != -1 {
+		frame = frame[idx+1:]
+	}
+	return strings.ReplaceAll(frame, ";", ":")
+}
@@ -0,0 +1,23 @@
+package collector
+
+import "testing"
+
+func TestFrameName(t *testing.T) {
+	for _, c := range []struct{ frame, want string }{
+		{"app//org.apache.lucene.index.IndexWriter.flush(IndexWriter.java:3900)", "org.apache.lucene.index.IndexWriter.flush"},
+		{"java.base@17.0.2/java.lang.Thread.run(Thread.java:833)", "java.lang.Thread.run"},
+		{"at java.lang.Thread.run(Thread.java:748)", "java.lang.Thread.run"}, // 7.x 之前带 at 前缀、没有模块
+		{"app/org.elasticsearch.server@8.11.0/org.elasticsearch.node.Node.start(Node.java:10)", "org.elasticsearch.node.Node.start"},
+		// lambda 隐藏类的类名带有 /0x...，不能当作模块前缀去掉
+		{"app//org.elasticsearch.action.ActionListener$$Lambda/0x00000008018f3a8.onResponse(Unknown Source)",
+			"org.elasticsearch.action.ActionListener$$Lambda/0x00000008018f3a8.onResponse"},
+		{"java.base/java.util.concurrent.ThreadPoolExecutor$$Lambda$312/0x0000000800c03000.run(Unknown Source)",
+			"java.util.concurrent.ThreadPoolExecutor$$Lambda$312/0x0000000800c03000.run"},
+		{"org.example.Outer$$Lambda/0x0000000801234.apply(Unknown Source)", "org.example.Outer$$Lambda/0x0000000801234.apply"},
+		{"app//org.example.Weird;Name.run(Weird.java:1)", "org.example.Weird:Name.run"},
+	} {
+		if got := frameName(c.frame); got != c.want {
+			t.Errorf("frameName(%q) = %q, 期望 %q", c.frame, got, c.want)
+		}
+	}
+}
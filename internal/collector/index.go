@@ -19,9 +19,9 @@ func NewIndexCollector(client *client.ElasticsearchClient) *IndexCollector {
 	}
 }
 
-// CollectStats 采集索引统计（只读操作）
+// CollectStats 采集索引统计（只读操作），指标按集群版本能力选择
 func (c *IndexCollector) CollectStats(ctx context.Context) (*model.IndexStats, error) {
-	return c.client.GetIndexStats(ctx)
+	return c.client.GetIndexStats(ctx, c.client.Capabilities().IndexStatsMetrics())
 }
 
 // CollectList 采集索引列表（只读操作）
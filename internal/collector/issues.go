@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// resolvedRetention 已恢复的问题继续显示的时间（便于发现只持续一两个周期的抖动）
+const resolvedRetention = 5 * time.Minute
+
+// issueLevelOrder 问题级别排序（严重优先）
+var issueLevelOrder = map[string]int{"critical": 0, "warning": 1, "info": 2}
+
+// IssueTracker 跨采集周期跟踪健康问题
+// 同一 Key 的问题保留首次发现时间，每次出现时更新最近发现时间、级别和数值；
+// 本周期未出现的问题标记为已恢复，保留 resolvedRetention 后移除。
+// 某个来源本周期未能检查（如节点统计采集失败）时，该来源的问题保持原状态
+type IssueTracker struct {
+	issues map[string]*model.HealthIssue
+}
+
+// NewIssueTracker 创建问题跟踪器
+func NewIssueTracker() *IssueTracker {
+	return &IssueTracker{issues: make(map[string]*model.HealthIssue)}
+}
+
+// Update 合并本周期发现的问题，checked 为本周期已检查的来源（Key 的第一段），
+// 返回按优先级排序的问题列表：未恢复优先，其次按级别、首次发现时间
+func (t *IssueTracker) Update(found []model.HealthIssue, checked []string, now time.Time) []model.HealthIssue {
+	nowUnix := now.Unix()
+
+	seen := make(map[string]bool, len(found))
+	for i := range found {
+		issue := found[i]
+		seen[issue.Key] = true
+		issue.LastSeen = nowUnix
+		issue.Resolved = false
+		if prev, ok := t.issues[issue.Key]; ok && !prev.Resolved {
+			issue.FirstSeen = prev.FirstSeen
+		} else {
+			issue.FirstSeen = nowUnix
+		}
+		t.issues[issue.Key] = &issue
+	}
+
+	for key, issue := range t.issues {
+		if seen[key] || !containsString(checked, issueSource(key)) {
+			continue
+		}
+		issue.Resolved = true
+		if now.Sub(time.Unix(issue.LastSeen, 0)) > resolvedRetention {
+			delete(t.issues, key)
+		}
+	}
+
+	result := make([]model.HealthIssue, 0, len(t.issues))
+	for _, issue := range t.issues {
+		result = append(result, *issue)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Resolved != b.Resolved {
+			return !a.Resolved
+		}
+		if issueLevelOrder[a.Level] != issueLevelOrder[b.Level] {
+			return issueLevelOrder[a.Level] < issueLevelOrder[b.Level]
+		}
+		if a.FirstSeen != b.FirstSeen {
+			return a.FirstSeen < b.FirstSeen
+		}
+		return a.Key < b.Key
+	})
+	return result
+}
+
+// issueSource 返回问题 Key 的来源（第一段）
+func issueSource(key string) string {
+	source, _, _ := strings.Cut(key, "/")
+	return source
+}
+
+// containsString 判断切片是否包含指定字符串
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
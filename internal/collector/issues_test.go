@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// trackerIssues 构造 "key=level" 形式描述的问题
+func trackerIssues(specs ...string) []model.HealthIssue {
+	issues := make([]model.HealthIssue, 0, len(specs))
+	for _, s := range specs {
+		key, level, _ := strings.Cut(s, "=")
+		issues = append(issues, model.HealthIssue{Key: key, Level: level, Message: key})
+	}
+	return issues
+}
+
+// describe 将问题列表描述为 "key=level" 或 "key=resolved"，保持返回顺序
+func describe(issues []model.HealthIssue) string {
+	parts := make([]string, 0, len(issues))
+	for _, i := range issues {
+		state := i.Level
+		if i.Resolved {
+			state = "resolved"
+		}
+		parts = append(parts, i.Key+"="+state)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestIssueTrackerUpdate(t *testing.T) {
+	all := []string{"node", "cluster", "index"}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cycles := []struct {
+		at      time.Duration
+		found   []model.HealthIssue
+		checked []string
+		want    string
+		first   map[string]time.Duration // 问题的首次发现时间
+	}{
+		{
+			0, trackerIssues("node/node-1/jvm.heap=warning", "cluster/status=critical"), all,
+			"cluster/status=critical node/node-1/jvm.heap=warning",
+			map[string]time.Duration{"node/node-1/jvm.heap": 0},
+		},
+		{
+			// 同时发现的同级问题按 Key 排序；级别变化不影响首次发现时间
+			10 * time.Second, trackerIssues("node/node-1/jvm.heap=critical", "node/node-2/disk=critical", "cluster/status=critical"), all,
+			"cluster/status=critical node/node-1/jvm.heap=critical node/node-2/disk=critical",
+			map[string]time.Duration{"node/node-1/jvm.heap": 0, "node/node-2/disk": 10 * time.Second},
+		},
+		{
+			// 来源已检查且未再出现的问题恢复，排在未恢复的问题之后
+			20 * time.Second, trackerIssues("node/node-2/disk=critical", "index/logs/segments=info"), all,
+			"node/node-2/disk=critical index/logs/segments=info cluster/status=resolved node/node-1/jvm.heap=resolved",
+			nil,
+		},
+		{
+			// 节点统计未能采集：node 来源的问题保持原状态（不恢复，最近发现时间不变）
+			30 * time.Second, nil, []string{"cluster", "index"},
+			"node/node-2/disk=critical cluster/status=resolved node/node-1/jvm.heap=resolved index/logs/segments=resolved",
+			map[string]time.Duration{"node/node-2/disk": 10 * time.Second},
+		},
+		{
+			// 已恢复的问题再次出现时重新计算首次发现时间
+			40 * time.Second, trackerIssues("node/node-1/jvm.heap=warning"), all,
+			"node/node-1/jvm.heap=warning cluster/status=resolved node/node-2/disk=resolved index/logs/segments=resolved",
+			map[string]time.Duration{"node/node-1/jvm.heap": 40 * time.Second},
+		},
+		{
+			// 最近一次发现超过 5 分钟的已恢复问题被移除（cluster/status 最后出现在 10s）
+			10*time.Second + resolvedRetention + time.Second, trackerIssues("node/node-1/jvm.heap=warning"), all,
+			"node/node-1/jvm.heap=warning node/node-2/disk=resolved index/logs/segments=resolved",
+			map[string]time.Duration{"node/node-1/jvm.heap": 40 * time.Second},
+		},
+	}
+
+	tracker := NewIssueTracker()
+	for i, c := range cycles {
+		now := base.Add(c.at)
+		got := tracker.Update(c.found, c.checked, now)
+		if d := describe(got); d != c.want {
+			t.Errorf("周期 %d: 问题 = %s\n期望 %s", i+1, d, c.want)
+		}
+		for _, issue := range got {
+			if want, ok := c.first[issue.Key]; ok && issue.FirstSeen != base.Add(want).Unix() {
+				t.Errorf("周期 %d: %s 首次发现 = %d, 期望 %d", i+1, issue.Key, issue.FirstSeen, base.Add(want).Unix())
+			}
+			checked := containsString(c.checked, issueSource(issue.Key))
+			if checked && !issue.Resolved && issue.LastSeen != now.Unix() {
+				t.Errorf("周期 %d: %s 最近发现 = %d, 期望 %d", i+1, issue.Key, issue.LastSeen, now.Unix())
+			}
+			if !checked && issue.LastSeen >= now.Unix() {
+				t.Errorf("周期 %d: 未检查来源的 %s 不应更新最近发现时间", i+1, issue.Key)
+			}
+		}
+	}
+}
+
+func TestIssueTrackerKeepsFirstSeen(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewIssueTracker()
+	for i := 0; i < 5; i++ {
+		now := base.Add(time.Duration(i) * time.Minute)
+		got := tracker.Update(trackerIssues("cluster/status=warning"), []string{"cluster"}, now)
+		if len(got) != 1 || got[0].FirstSeen != base.Unix() || got[0].LastSeen != now.Unix() {
+			t.Errorf("第 %d 分钟: 问题 = %+v, 期望首次发现 %d、最近发现 %d", i, got, base.Unix(), now.Unix())
+		}
+	}
+}
+
+func TestIssueTrackerOrdering(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	checked := []string{"node", "index"}
+	tracker := NewIssueTracker()
+	tracker.Update(trackerIssues("node/b/cpu=warning", "index/old=info"), checked, base)
+	got := tracker.Update(trackerIssues("node/b/cpu=warning", "node/a/cpu=warning", "index/new=critical", "index/old=info", "node/c/x=unknown"), checked, base.Add(time.Minute))
+
+	// 级别优先，同级别先发现的在前，同时发现的按 Key 排序，未知级别排在最前（与 critical 同序）
+	want := "index/new=critical node/c/x=unknown node/b/cpu=warning node/a/cpu=warning index/old=info"
+	if d := describe(got); d != want {
+		t.Errorf("排序 = %s\n期望 %s", d, want)
+	}
+}
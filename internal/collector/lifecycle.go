@@ -0,0 +1,328 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+const (
+	// maxLifecycleIndices 保留的问题索引数（统计数字包含全部索引）
+	maxLifecycleIndices = 50
+	// ilmPollInterval ILM 默认每 10 分钟检查一次（indices.lifecycle.poll_interval），
+	// 刚满足滚动条件不足一个检查周期属于正常情况
+	ilmPollInterval = 10 * time.Minute
+	// rolloverSizeMargin 大小和文档数条件超出 10% 以上才视为逾期（超出条件后最多等待一个检查周期）
+	rolloverSizeMargin = 1.1
+)
+
+// ilmWaitingSteps 按设计会长时间停留的步骤：等待滚动条件，或等待下一阶段的 min_age
+// （OpenSearch ISM 对应 attempt_rollover 和 attempt_transition_step）
+var ilmWaitingSteps = map[string]bool{
+	"check-rollover-ready":    true,
+	"complete":                true,
+	"attempt_rollover":        true,
+	"attempt_transition_step": true,
+}
+
+// LifecycleCollector 索引生命周期采集器（ILM 和数据流生命周期）
+type LifecycleCollector struct {
+	client     *client.ElasticsearchClient
+	thresholds *config.Thresholds
+}
+
+// NewLifecycleCollector 创建生命周期采集器，thresholds 为空时使用 DefaultThresholds
+func NewLifecycleCollector(client *client.ElasticsearchClient, thresholds *config.Thresholds) *LifecycleCollector {
+	if thresholds == nil {
+		thresholds = &config.DefaultThresholds
+	}
+	return &LifecycleCollector{
+		client:     client,
+		thresholds: thresholds,
+	}
+}
+
+// Collect 采集生命周期状态：出错、滚动逾期和长时间停留在同一步骤的索引（只读操作）
+// indices 用于判断是否已满足大小和文档数滚动条件，可以为空（只判断 max_age）
+// 数据流生命周期失败只记录在 DSLErr 中，不影响 ILM 状态
+func (c *LifecycleCollector) Collect(ctx context.Context, indices []model.IndexInfo) (*model.LifecycleOverview, error) {
+	explain, err := c.client.GetILMExplain(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make(map[string]*model.IndexInfo, len(indices))
+	for i := range indices {
+		infos[indices[i].Index] = &indices[i]
+	}
+
+	now := c.client.Now().UnixMilli()
+	overview := &model.LifecycleOverview{PhaseCounts: make(map[string]int)}
+	for name, idx := range explain.Indices {
+		if !idx.Managed {
+			continue
+		}
+		overview.ILMManaged++
+		overview.PhaseCounts[idx.Phase]++
+		stuckMillis := int64(c.thresholds.ForIndex(name).ILMStepWarningMin) * time.Minute.Milliseconds()
+		if status, ok := ilmStatus(name, idx, now, infos[name], stuckMillis); ok {
+			overview.Indices = append(overview.Indices, status)
+		}
+	}
+
+	dsl, err := c.client.GetDataStreamLifecycle(ctx)
+	var unsupported *client.UnsupportedError
+	switch {
+	case errors.As(err, &unsupported):
+		// 8.11 之前没有数据流生命周期，只显示 ILM 状态
+	case err != nil:
+		overview.DSLErr = err
+	default:
+		for name, idx := range dsl.Indices {
+			if !idx.ManagedByLifecycle {
+				continue
+			}
+			overview.DSLManaged++
+			if idx.Error != "" {
+				overview.Indices = append(overview.Indices, model.LifecycleStatus{
+					Index:   name,
+					Managed: "dsl",
+					Problem: model.LifecycleError,
+					Reason:  lifecycleErrorReason(idx.Error),
+				})
+			}
+		}
+	}
+
+	for _, s := range overview.Indices {
+		switch s.Problem {
+		case model.LifecycleError:
+			overview.Errors++
+		case model.LifecycleStuck:
+			overview.Stuck++
+		case model.LifecycleRolloverOverdue:
+			overview.Overdue++
+		}
+	}
+	sort.Slice(overview.Indices, func(i, j int) bool {
+		a, b := overview.Indices[i], overview.Indices[j]
+		if a.Problem != b.Problem {
+			return lifecycleProblemOrder(a.Problem) < lifecycleProblemOrder(b.Problem)
+		}
+		if a.Overdue != b.Overdue {
+			return a.Overdue > b.Overdue
+		}
+		if a.StepMillis != b.StepMillis {
+			return a.StepMillis > b.StepMillis
+		}
+		return a.Index < b.Index
+	})
+	if len(overview.Indices) > maxLifecycleIndices {
+		overview.Indices = overview.Indices[:maxLifecycleIndices]
+	}
+	return overview, nil
+}
+
+// ilmStatus 判断 ILM 管理的索引是否有问题：ERROR 步骤、滚动逾期，或在非等待步骤停留达到 stuckMillis
+// （与健康问题使用同一个 ilm_step_warning_min 阈值，为 0 时不判断停留过久）
+func ilmStatus(name string, idx model.ILMIndex, now int64, info *model.IndexInfo, stuckMillis int64) (model.LifecycleStatus, bool) {
+	status := model.LifecycleStatus{
+		Index:       name,
+		Managed:     "ilm",
+		Policy:      idx.Policy,
+		Phase:       idx.Phase,
+		Action:      idx.Action,
+		Step:        idx.Step,
+		FailedStep:  idx.FailedStep,
+		RetryCount:  idx.FailedStepRetryCount,
+		PhaseMillis: elapsedMillis(now, idx.PhaseTimeMillis),
+		StepMillis:  elapsedMillis(now, idx.StepTimeMillis),
+	}
+	if info := idx.StepInfo; info != nil {
+		status.Reason = info.Message
+		if info.Reason != "" {
+			status.Reason = info.Type + ": " + info.Reason
+		}
+	}
+
+	if idx.Step == "ERROR" {
+		status.Problem = model.LifecycleError
+		return status, true
+	}
+	if idx.Action == "rollover" {
+		if ratio, condition := rolloverOverdue(idx, now, info); ratio > 0 {
+			status.Problem = model.LifecycleRolloverOverdue
+			status.Overdue = ratio
+			status.Reason = condition
+			return status, true
+		}
+	}
+	if stuckMillis > 0 && !ilmWaitingSteps[idx.Step] && status.StepMillis >= stuckMillis {
+		status.Problem = model.LifecycleStuck
+		return status, true
+	}
+	return status, false
+}
+
+// rolloverOverdue 判断索引是否已满足滚动条件却仍未滚动，返回达到条件的倍数和对应条件（未逾期时倍数为 0）
+// 主分片大小和文档数按平均值计算（最大的主分片不小于平均值，因此不会误报）
+func rolloverOverdue(idx model.ILMIndex, now int64, info *model.IndexInfo) (float64, string) {
+	if idx.PhaseExecution == nil || idx.PhaseExecution.PhaseDefinition.Actions.Rollover == nil {
+		return 0, ""
+	}
+	cond := idx.PhaseExecution.PhaseDefinition.Actions.Rollover
+
+	created := idx.IndexCreationDateMillis
+	if created == 0 {
+		created = idx.LifecycleDateMillis
+	}
+	age := float64(elapsedMillis(now, created))
+
+	// 索引列表不可用时只能判断 max_age
+	var docs, priBytes, primaries float64
+	known := false
+	if info != nil {
+		d, err1 := strconv.ParseFloat(info.DocsCount, 64)
+		b, err2 := strconv.ParseFloat(info.PriStoreSize, 64)
+		p, err3 := strconv.ParseFloat(info.Pri, 64)
+		if err1 == nil && err2 == nil && err3 == nil && p > 0 {
+			docs, priBytes, primaries, known = d, b, p, true
+		}
+	}
+
+	// 任一 min_* 条件未满足（或无法判断）时不会滚动
+	if ms, ok := parseTimeMillis(cond.MinAge); ok && age < ms {
+		return 0, ""
+	}
+	mins := []struct {
+		value, limit float64
+	}{
+		{priBytes, parseBytes(cond.MinSize)},
+		{priBytes / max(primaries, 1), parseBytes(cond.MinPrimaryShardSize)},
+		{docs, float64(cond.MinDocs)},
+		{docs / max(primaries, 1), float64(cond.MinPrimaryShardDocs)},
+	}
+	for _, m := range mins {
+		if m.limit > 0 && (!known || m.value < m.limit) {
+			return 0, ""
+		}
+	}
+
+	ratio, condition := 0.0, ""
+	check := func(value, limit, margin float64, name, setting string) {
+		if limit <= 0 || value < limit+margin {
+			return
+		}
+		if r := value / limit; r > ratio {
+			ratio, condition = r, name+"="+setting
+		}
+	}
+	if ms, ok := parseTimeMillis(cond.MaxAge); ok {
+		check(age, ms, float64(ilmPollInterval.Milliseconds()), "max_age", cond.MaxAge)
+	}
+	if known {
+		limit := parseBytes(cond.MaxSize)
+		check(priBytes, limit, limit*(rolloverSizeMargin-1), "max_size", cond.MaxSize)
+		limit = parseBytes(cond.MaxPrimaryShardSize)
+		check(priBytes/primaries, limit, limit*(rolloverSizeMargin-1), "max_primary_shard_size", cond.MaxPrimaryShardSize)
+		limit = float64(cond.MaxDocs)
+		check(docs, limit, limit*(rolloverSizeMargin-1), "max_docs", strconv.FormatInt(cond.MaxDocs, 10))
+		limit = float64(cond.MaxPrimaryShardDocs)
+		check(docs/primaries, limit, limit*(rolloverSizeMargin-1), "max_primary_shard_docs", strconv.FormatInt(cond.MaxPrimaryShardDocs, 10))
+	}
+	return ratio, condition
+}
+
+// lifecycleProblemOrder 问题排序优先级：出错、滚动逾期、停留过久
+func lifecycleProblemOrder(problem string) int {
+	switch problem {
+	case model.LifecycleError:
+		return 0
+	case model.LifecycleRolloverOverdue:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// lifecycleErrorReason 提取数据流生命周期错误（ES 以 JSON 字符串返回异常）
+func lifecycleErrorReason(raw string) string {
+	var info model.ILMStepInfo
+	if err := json.Unmarshal([]byte(raw), &info); err == nil && info.Reason != "" {
+		return info.Type + ": " + info.Reason
+	}
+	return raw
+}
+
+// elapsedMillis 从时间戳到现在经过的毫秒数（时间戳缺失时为 0）
+func elapsedMillis(now, since int64) int64 {
+	if since <= 0 || now < since {
+		return 0
+	}
+	return now - since
+}
+
+// timeUnits ES 时间单位（按顺序匹配后缀，ms 必须排在 s 和 m 之前）
+var timeUnits = []struct {
+	suffix string
+	millis float64
+}{
+	{"nanos", 1e-6},
+	{"micros", 1e-3},
+	{"ms", 1},
+	{"s", 1000},
+	{"m", 60 * 1000},
+	{"h", 60 * 60 * 1000},
+	{"d", 24 * 60 * 60 * 1000},
+}
+
+// parseTimeMillis 解析 ES 时间值（如 30d、12h、500ms），未设置或无法解析时返回 false
+func parseTimeMillis(s string) (float64, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, u := range timeUnits {
+		if num, ok := strings.CutSuffix(s, u.suffix); ok {
+			v, err := strconv.ParseFloat(num, 64)
+			if err != nil || v < 0 {
+				return 0, false
+			}
+			return v * u.millis, true
+		}
+	}
+	return 0, false
+}
+
+// byteUnits ES 大小单位（按顺序匹配后缀，b 必须排在最后）
+var byteUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"kb", 1 << 10},
+	{"mb", 1 << 20},
+	{"gb", 1 << 30},
+	{"tb", 1 << 40},
+	{"pb", 1 << 50},
+	{"b", 1},
+}
+
+// parseBytes 解析 ES 大小值（如 50gb、512mb），未设置或无法解析时返回 0
+func parseBytes(s string) float64 {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, u := range byteUnits {
+		if num, ok := strings.CutSuffix(s, u.suffix); ok {
+			v, err := strconv.ParseFloat(num, 64)
+			if err != nil || v < 0 {
+				return 0
+			}
+			return v * u.bytes
+		}
+	}
+	return 0
+}
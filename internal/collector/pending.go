@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// pendingHistorySize 保留的队列深度历史周期数
+const pendingHistorySize = 30
+
+// PendingTaskCollector 待处理集群任务采集器
+type PendingTaskCollector struct {
+	client  *client.ElasticsearchClient
+	history []int // 最近若干周期的队列深度（从旧到新）
+}
+
+// NewPendingTaskCollector 创建待处理任务采集器
+func NewPendingTaskCollector(client *client.ElasticsearchClient) *PendingTaskCollector {
+	return &PendingTaskCollector{
+		client: client,
+	}
+}
+
+// Collect 采集主节点待处理任务队列（只读操作）
+// 集群健康显示队列为空时跳过请求，只记录深度
+func (c *PendingTaskCollector) Collect(ctx context.Context, health *model.ClusterHealth) (*model.PendingTaskOverview, error) {
+	overview := &model.PendingTaskOverview{}
+	if health == nil || health.PendingTasks > 0 {
+		tasks, err := c.client.GetPendingTasks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		overview.Tasks = tasks.Tasks
+	}
+
+	sort.SliceStable(overview.Tasks, func(i, j int) bool {
+		a, b := overview.Tasks[i], overview.Tasks[j]
+		if a.TimeInQueueMillis != b.TimeInQueueMillis {
+			return a.TimeInQueueMillis > b.TimeInQueueMillis
+		}
+		return a.InsertOrder < b.InsertOrder
+	})
+	for _, task := range overview.Tasks {
+		if task.Executing {
+			overview.Executing++
+		}
+		if task.TimeInQueueMillis > overview.OldestMillis {
+			overview.OldestMillis = task.TimeInQueueMillis
+		}
+	}
+
+	c.history = append(c.history, len(overview.Tasks))
+	if len(c.history) > pendingHistorySize {
+		c.history = c.history[len(c.history)-pendingHistorySize:]
+	}
+	overview.History = append([]int(nil), c.history...)
+	return overview, nil
+}
@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// maxExplainedShards 每个周期最多诊断的未分配分片数（allocation explain 需要主节点计算，开销较大）
+const maxExplainedShards = 3
+
+// ShardCollector 分片采集器
+type ShardCollector struct {
+	client *client.ElasticsearchClient
+	filter string // 索引通配符（逗号分隔），为空表示全部索引
+}
+
+// NewShardCollector 创建分片采集器
+func NewShardCollector(client *client.ElasticsearchClient, filter string) *ShardCollector {
+	return &ShardCollector{
+		client: client,
+		filter: filter,
+	}
+}
+
+// Collect 采集非 STARTED 分片和未分配原因（只读操作）
+// 分配诊断失败只记录在 ExplainErr 中，不影响分片列表
+func (c *ShardCollector) Collect(ctx context.Context) (*model.ShardOverview, error) {
+	shards, err := c.client.GetCatShards(ctx, c.filter)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &model.ShardOverview{
+		Filter: c.filter,
+		Total:  len(shards),
+	}
+	for _, s := range shards {
+		switch s.State {
+		case "STARTED":
+			overview.Started++
+			continue
+		case "RELOCATING":
+			overview.Relocating++
+		case "INITIALIZING":
+			overview.Initializing++
+		case "UNASSIGNED":
+			overview.Unassigned++
+		}
+		overview.Shards = append(overview.Shards, s)
+	}
+	sortShards(overview.Shards)
+
+	if overview.Unassigned > 0 {
+		overview.Allocations, overview.ExplainErr = c.explain(ctx, overview.Shards)
+	}
+	return overview, nil
+}
+
+// explain 诊断排在最前面的未分配分片
+// 旧版本无法指定分片，只能诊断 ES 选择的第一个未分配分片（不受索引过滤限制）
+func (c *ShardCollector) explain(ctx context.Context, shards []model.ShardInfo) ([]model.ShardAllocation, error) {
+	if !c.client.Capabilities().AllocationExplainParams {
+		explain, err := c.client.ExplainAllocation(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []model.ShardAllocation{summarizeAllocation(explain)}, nil
+	}
+
+	var result []model.ShardAllocation
+	for i := range shards {
+		if len(result) >= maxExplainedShards {
+			break
+		}
+		if shards[i].State != "UNASSIGNED" {
+			continue
+		}
+		explain, err := c.client.ExplainAllocation(ctx, &shards[i])
+		if err != nil {
+			return result, err
+		}
+		result = append(result, summarizeAllocation(explain))
+	}
+	return result, nil
+}
+
+// summarizeAllocation 汇总分配诊断：统计每个决策器在多少个节点上拒绝或限流
+func summarizeAllocation(explain *model.AllocationExplain) model.ShardAllocation {
+	summary := model.ShardAllocation{
+		Index:       explain.Index,
+		Shard:       explain.Shard,
+		Primary:     explain.Primary,
+		CanAllocate: explain.CanAllocate,
+		Explanation: explain.AllocateExplanation,
+	}
+
+	counts := make(map[string]*model.DeciderCount)
+	for _, node := range explain.NodeAllocationDecisions {
+		for _, d := range node.Deciders {
+			if d.Decision != "NO" && d.Decision != "THROTTLE" {
+				continue
+			}
+			count, ok := counts[d.Decider]
+			if !ok {
+				count = &model.DeciderCount{Decider: d.Decider, Explanation: d.Explanation}
+				counts[d.Decider] = count
+			}
+			count.Nodes++
+		}
+	}
+	for _, count := range counts {
+		summary.Deciders = append(summary.Deciders, *count)
+	}
+	sort.Slice(summary.Deciders, func(i, j int) bool {
+		a, b := summary.Deciders[i], summary.Deciders[j]
+		if a.Nodes != b.Nodes {
+			return a.Nodes > b.Nodes
+		}
+		return a.Decider < b.Decider
+	})
+	return summary
+}
+
+// shardStateOrder 分片排序优先级：未分配主分片、未分配副本、初始化中、迁移中
+func shardStateOrder(s model.ShardInfo) int {
+	switch {
+	case s.State == "UNASSIGNED" && s.Primary():
+		return 0
+	case s.State == "UNASSIGNED":
+		return 1
+	case s.State == "INITIALIZING":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// sortShards 按优先级、索引名、分片号排序
+func sortShards(shards []model.ShardInfo) {
+	sort.SliceStable(shards, func(i, j int) bool {
+		a, b := shards[i], shards[j]
+		if oa, ob := shardStateOrder(a), shardStateOrder(b); oa != ob {
+			return oa < ob
+		}
+		if a.Index != b.Index {
+			return a.Index < b.Index
+		}
+		na, _ := strconv.Atoi(a.Shard)
+		nb, _ := strconv.Atoi(b.Shard)
+		return na < nb
+	})
+}
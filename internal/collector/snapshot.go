@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// SnapshotCollector 快照采集器（快照生命周期策略和正在执行的快照）
+type SnapshotCollector struct {
+	client  *client.ElasticsearchClient
+	windows map[string]time.Duration // 配置的策略成功窗口
+}
+
+// NewSnapshotCollector 创建快照采集器，windows 为按策略 ID 配置的成功窗口（可以为空）
+func NewSnapshotCollector(client *client.ElasticsearchClient, windows map[string]time.Duration) *SnapshotCollector {
+	return &SnapshotCollector{
+		client:  client,
+		windows: windows,
+	}
+}
+
+// Collect 采集快照策略的最近执行情况、累计统计和正在执行的快照进度（只读操作）
+func (c *SnapshotCollector) Collect(ctx context.Context) (*model.SnapshotOverview, error) {
+	policies, err := c.client.GetSLMPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.client.GetSLMStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := c.client.GetSnapshotStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.client.Now()
+	overview := &model.SnapshotOverview{
+		Running: status.Snapshots,
+		Stats:   *stats,
+	}
+	for id, p := range policies {
+		s := model.SnapshotPolicyStatus{
+			Policy:     id,
+			Repository: p.Policy.Repository,
+			Schedule:   p.Policy.Schedule,
+			NextRunAt:  p.NextExecutionMillis,
+			Taken:      p.Stats.SnapshotsTaken,
+			Failed:     p.Stats.SnapshotsFailed,
+		}
+		if p.LastSuccess != nil {
+			s.LastSuccess, s.LastSuccessAt = p.LastSuccess.SnapshotName, p.LastSuccess.Time
+		}
+		if p.LastFailure != nil {
+			s.LastFailure, s.LastFailureAt = p.LastFailure.SnapshotName, p.LastFailure.Time
+			s.FailureReason = lifecycleErrorReason(p.LastFailure.Details)
+		}
+		if p.InProgress != nil {
+			s.InProgress = p.InProgress.Name
+		}
+
+		c.successDeadline(&s, p)
+		if s.DeadlineAt > 0 && now.UnixMilli() > s.DeadlineAt {
+			s.Overdue = true
+			overview.Overdue++
+		}
+		overview.Policies = append(overview.Policies, s)
+	}
+
+	sort.Slice(overview.Policies, func(i, j int) bool {
+		return overview.Policies[i].Policy < overview.Policies[j].Policy
+	})
+	sort.Slice(overview.Running, func(i, j int) bool {
+		return overview.Running[i].Stats.StartTimeInMillis < overview.Running[j].Stats.StartTimeInMillis
+	})
+	return overview, nil
+}
+
+// successDeadline 计算策略应当出现下一次成功的最晚时间
+// 配置了成功窗口时为上次成功的完成时间加窗口；否则按调度计划推算：
+// 上次成功开始后的第一次计划执行再加半个调度周期（留给快照执行的时间）
+// 从未成功的策略从策略修改时间开始计算
+func (c *SnapshotCollector) successDeadline(s *model.SnapshotPolicyStatus, p model.SLMPolicy) {
+	finished, started := p.ModifiedDateMillis, p.ModifiedDateMillis
+	if p.LastSuccess != nil {
+		finished, started = p.LastSuccess.Time, p.LastSuccess.Time
+		if p.LastSuccess.StartTime > 0 {
+			started = p.LastSuccess.StartTime
+		}
+	}
+	if finished <= 0 {
+		return
+	}
+
+	if window, ok := c.windows[s.Policy]; ok {
+		s.Configured = true
+		s.WindowMillis = window.Milliseconds()
+		s.DeadlineAt = finished + s.WindowMillis
+		return
+	}
+
+	schedule, err := parseCron(p.Policy.Schedule)
+	if err != nil {
+		return
+	}
+	first := schedule.next(time.UnixMilli(started))
+	if first.IsZero() {
+		return
+	}
+	second := schedule.next(first)
+	if second.IsZero() {
+		return
+	}
+	s.DeadlineAt = first.Add(second.Sub(first) / 2).UnixMilli()
+	s.WindowMillis = s.DeadlineAt - finished
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+const (
+	// maxRunningTasks 保留的运行时间最长的任务数
+	maxRunningTasks = 10
+	// minTaskRunningMillis 短于该时间的任务不列出（大部分查询和写入请求在此之前完成）
+	minTaskRunningMillis = 1000
+	// taskListAction 监控自身查询任务列表的请求，不计入
+	taskListAction = "cluster:monitor/tasks/lists"
+)
+
+// TaskCollector 长时间运行任务采集器
+type TaskCollector struct {
+	client *client.ElasticsearchClient
+}
+
+// NewTaskCollector 创建任务采集器
+func NewTaskCollector(client *client.ElasticsearchClient) *TaskCollector {
+	return &TaskCollector{
+		client: client,
+	}
+}
+
+// Collect 采集运行时间最长的顶层任务（只读操作，不会取消任何任务）
+// nodeStats 用于把节点 ID 解析为节点名称，可以为空
+func (c *TaskCollector) Collect(ctx context.Context, nodeStats *model.NodeStats) (*model.TaskOverview, error) {
+	tasks, err := c.client.GetTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &model.TaskOverview{}
+	for key, group := range tasks.Tasks {
+		if strings.HasPrefix(group.Action, taskListAction) {
+			continue
+		}
+		overview.Total++
+
+		running := group.RunningTimeInNanos / 1e6
+		if running < minTaskRunningMillis {
+			continue
+		}
+		task := model.RunningTask{
+			TaskID:        key,
+			NodeName:      group.Node,
+			Action:        group.Action,
+			Description:   group.Description,
+			RunningMillis: running,
+			Cancellable:   group.Cancellable,
+			Cancelled:     group.Cancelled,
+			Children:      len(group.Children),
+		}
+		if key == "" {
+			task.TaskID = group.Node + ":" + strconv.FormatInt(group.ID, 10)
+		}
+		if nodeStats != nil {
+			if node, ok := nodeStats.Nodes[group.Node]; ok {
+				task.NodeName = node.Name
+			}
+		}
+		overview.Tasks = append(overview.Tasks, task)
+	}
+
+	sort.Slice(overview.Tasks, func(i, j int) bool {
+		a, b := overview.Tasks[i], overview.Tasks[j]
+		if a.RunningMillis != b.RunningMillis {
+			return a.RunningMillis > b.RunningMillis
+		}
+		return a.TaskID < b.TaskID
+	})
+	if len(overview.Tasks) > maxRunningTasks {
+		overview.Tasks = overview.Tasks[:maxRunningTasks]
+	}
+	return overview, nil
+}
@@ -9,52 +9,105 @@ type Config struct {
 	Username string
 	Password string
 	Interval time.Duration
-	ReadOnly bool // 只读模式，生产环境必须为 true
-}
+	ReadOnly bool          // 只读模式，生产环境必须为 true
+	Safety   *SafetyConfig // 安全配置，为空时使用 DefaultSafetyConfig
 
-// Thresholds 阈值配置
-type Thresholds struct {
-	JVMHeapWarning  int // JVM 堆内存警告阈值
-	JVMHeapCritical int // JVM 堆内存严重阈值
-	CPUWarning      int // CPU 警告阈值
-	CPUCritical     int // CPU 严重阈值
-	MemoryWarning   int // 内存警告阈值
-	MemoryCritical  int // 内存严重阈值
-	DiskWarning     int // 磁盘警告阈值
-	DiskCritical    int // 磁盘严重阈值
-}
+	// 告警阈值（采集器、显示和告警共用），为空时使用 DefaultThresholds
+	Thresholds *Thresholds
+
+	// 分片视图的索引通配符过滤（逗号分隔，为空表示全部索引）
+	ShardFilter string
+
+	// 快照生命周期策略的成功窗口（键为策略 ID），超过窗口没有成功的快照时告警
+	// 未配置的策略按调度计划推算（上次成功后的第一次计划执行再加半个调度周期）
+	SnapshotWindows map[string]time.Duration
+
+	// 索引生命周期刷新间隔：_ilm/explain 和 _lifecycle/explain 的响应与索引数成正比，
+	// 间隔内沿用上次结果，0 表示每个采集周期都刷新
+	LifecycleInterval time.Duration
+
+	// 热点线程采样
+	HotThreadsEvery  int    // 每隔多少个采集周期采样一次 _nodes/hot_threads，0 表示不采样
+	HotThreadsWindow int    // 聚合最近多少次采样
+	HotThreadsNodes  string // 采样的节点（逗号分隔的节点 ID 或名称），为空表示全部节点
+	HotThreadsFolded string // 折叠调用栈导出文件（每次采样后覆盖），为空时不导出
+
+	// 多节点配置
+	Hosts         []string      // 种子节点列表（host:port 或完整 URL），为空时使用 Host:Port
+	PathPrefix    string        // URL 路径前缀（集群位于反向代理之后时使用，如 /es）
+	Sniff         bool          // 是否通过 /_nodes/http 自动发现节点
+	SniffInterval time.Duration // 节点发现刷新间隔
 
-// DefaultThresholds 默认阈值
-var DefaultThresholds = Thresholds{
-	JVMHeapWarning:  90,
-	JVMHeapCritical: 95,
-	CPUWarning:      90,
-	CPUCritical:     95,
-	MemoryWarning:   90,
-	MemoryCritical:  95,
-	DiskWarning:     90,
-	DiskCritical:    95,
+	// TLS 配置
+	Scheme             string // 协议：http 或 https
+	CACert             string // 自定义 CA 证书文件（PEM）
+	ClientCert         string // 客户端证书文件（双向 TLS）
+	ClientKey          string // 客户端私钥文件（双向 TLS）
+	InsecureSkipVerify bool   // 跳过证书校验（仅限测试环境）
+	CAFingerprint      string // CA 证书 SHA-256 指纹（ES 8 首次启动时输出）
+
+	// 代理配置（HTTP/SOCKS5 代理与 SSH 隧道二选一）
+	Proxy         string // 代理地址（http://、https:// 或 socks5://）
+	SSHTunnel     string // SSH 跳板机（user@host[:port]），连接经跳板机转发
+	SSHKey        string // SSH 私钥文件
+	SSHKnownHosts string // known_hosts 文件，为空时使用 ~/.ssh/known_hosts
+
+	// 录制与回放
+	Record      string  // 录制目录：保存收到的每个响应，为空时不录制
+	Replay      string  // 回放目录：使用录制的响应代替真实集群
+	ReplaySpeed float64 // 回放倍速，0 表示单步模式
+
+	// 审计日志
+	AuditLog        string // JSONL 审计日志路径，为空时不记录
+	AuditMaxSizeMB  int    // 单个日志文件大小上限，超过后轮转
+	AuditMaxBackups int    // 保留的历史日志文件数
+
+	// 认证配置（Basic / API Key / Bearer / 服务账号 四选一）
+	APIKey       string // API Key（base64 编码形式或 id:key）
+	BearerToken  string // OAuth2 / JWT Bearer 令牌
+	ServiceToken string // ES 服务账号令牌
 }
 
 // SafetyConfig 生产环境安全配置
 type SafetyConfig struct {
-	// 只允许读取操作的 API 端点
-	AllowedEndpoints []string
+	// 只读端点策略（方法 + 路径模板 + 禁用参数）
+	Policy EndpointPolicy
 	// 请求超时时间（避免长时间占用连接）
 	RequestTimeout time.Duration
+	// 按端点前缀覆盖的超时时间（最长前缀优先）
+	EndpointTimeouts map[string]time.Duration
 	// 最大并发请求数（避免对 ES 造成压力）
 	MaxConcurrency int
+	// 全局每秒请求数上限（<= 0 表示不限制）
+	RequestsPerSecond float64
+	// 令牌桶容量（允许的瞬时突发请求数）
+	RequestBurst int
+	// 幂等 GET 请求的最大重试次数（0 表示不重试）
+	MaxRetries int
+	// 重试退避基础时间（指数增长并加入随机抖动）
+	RetryBaseDelay time.Duration
+	// 重试退避上限；请求没有截止时间时，Retry-After 超过该值则放弃重试（不会提前重试）
+	RetryMaxDelay time.Duration
+	// 单个响应体解压后的大小上限（字节，<= 0 表示不限制）
+	MaxResponseBytes int64
 }
 
 // DefaultSafetyConfig 默认安全配置
 var DefaultSafetyConfig = SafetyConfig{
-	AllowedEndpoints: []string{
-		"/_cluster/health",
-		"/_nodes/stats",
-		"/_stats",
-		"/_cat/indices",
-		"/",
-	},
+	Policy:         DefaultEndpointPolicy,
 	RequestTimeout: 10 * time.Second,
-	MaxConcurrency: 5,
+	EndpointTimeouts: map[string]time.Duration{
+		"/_cluster/health": 3 * time.Second,
+		"/_nodes/http":     5 * time.Second,
+		"/_cat/":           10 * time.Second,
+		"/_nodes/stats":    15 * time.Second,
+		"/_stats":          30 * time.Second,
+	},
+	MaxConcurrency:    5,
+	RequestsPerSecond: 10,
+	RequestBurst:      5,
+	MaxRetries:        2,
+	RetryBaseDelay:    200 * time.Millisecond,
+	RetryMaxDelay:     5 * time.Second,
+	MaxResponseBytes:  64 * 1024 * 1024,
 }
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// FileConfig 配置文件结构（JSON），未出现的字段保持当前值
+type FileConfig struct {
+	Policy     *EndpointPolicy `json:"policy"`
+	Thresholds *Thresholds     `json:"thresholds"`
+
+	// 快照生命周期策略的成功窗口，如 {"nightly-snapshots": "26h"}
+	SnapshotWindows map[string]string `json:"snapshot_windows"`
+}
+
+// LoadFile 从 JSON 配置文件加载配置并合并到 cfg
+func LoadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	if cfg.Safety == nil {
+		safety := DefaultSafetyConfig
+		cfg.Safety = &safety
+	}
+	// 复制切片，避免解码时覆盖默认配置共享的底层数组
+	cfg.Safety.Policy.Rules = slices.Clone(cfg.Safety.Policy.Rules)
+	cfg.Safety.Policy.DeniedParams = slices.Clone(cfg.Safety.Policy.DeniedParams)
+
+	if cfg.Thresholds == nil {
+		thresholds := DefaultThresholds
+		cfg.Thresholds = &thresholds
+	}
+	cfg.Thresholds.Overrides = slices.Clone(cfg.Thresholds.Overrides)
+
+	fc := FileConfig{
+		Policy:     &cfg.Safety.Policy,
+		Thresholds: cfg.Thresholds,
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	if err := cfg.Thresholds.Validate(); err != nil {
+		return err
+	}
+
+	for policy, value := range fc.SnapshotWindows {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("快照策略 %s 的成功窗口无效: %s", policy, value)
+		}
+		if cfg.SnapshotWindows == nil {
+			cfg.SnapshotWindows = make(map[string]time.Duration)
+		}
+		cfg.SnapshotWindows[policy] = window
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+// EndpointRule 只读端点规则
+type EndpointRule struct {
+	// HTTP 方法，只允许 GET 或 HEAD
+	Method string `json:"method"`
+	// 路径模板，按段匹配：
+	//   {index} 匹配不以 "_" 开头的单个路径段（索引名或索引表达式）
+	//   {其他}  匹配任意非空的单个路径段
+	Pattern string `json:"pattern"`
+}
+
+// EndpointPolicy 只读端点策略
+type EndpointPolicy struct {
+	// 允许访问的端点规则，未命中任何规则的请求一律拒绝
+	Rules []EndpointRule `json:"rules"`
+	// 禁止出现的查询参数（可能产生写入、阻塞或服务端资源占用）
+	DeniedParams []string `json:"denied_params"`
+}
+
+// DefaultEndpointPolicy 默认只读端点策略
+var DefaultEndpointPolicy = EndpointPolicy{
+	Rules: []EndpointRule{
+		{Method: "GET", Pattern: "/"},
+		{Method: "GET", Pattern: "/_cluster/health"},
+		{Method: "GET", Pattern: "/_health_report"},
+		{Method: "GET", Pattern: "/_nodes/http"},
+		{Method: "GET", Pattern: "/_nodes/stats"},
+		{Method: "GET", Pattern: "/_nodes/stats/{metric}"},
+		{Method: "GET", Pattern: "/_nodes/stats/{metric}/{index_metric}"},
+		{Method: "GET", Pattern: "/_nodes/hot_threads"},
+		{Method: "GET", Pattern: "/_nodes/{node_id}/hot_threads"},
+		{Method: "GET", Pattern: "/_stats"},
+		{Method: "GET", Pattern: "/_stats/{metric}"},
+		{Method: "GET", Pattern: "/_cat/{name}"},
+		{Method: "GET", Pattern: "/_cat/shards/{index}"},
+		{Method: "GET", Pattern: "/_cluster/allocation/explain"},
+		{Method: "GET", Pattern: "/_cluster/pending_tasks"},
+		{Method: "GET", Pattern: "/_tasks"},
+		{Method: "GET", Pattern: "/_slm/policy"},
+		{Method: "GET", Pattern: "/_slm/stats"},
+		{Method: "GET", Pattern: "/_snapshot/_status"},
+		{Method: "GET", Pattern: "/{index}/_stats"},
+		{Method: "GET", Pattern: "/{index}/_stats/{metric}"},
+		{Method: "GET", Pattern: "/{index}/_ilm/explain"},
+		{Method: "GET", Pattern: "/{index}/_lifecycle/explain"},
+		{Method: "GET", Pattern: "/_plugins/_ism/explain/{index}"},
+	},
+	DeniedParams: []string{
+		"source",                 // 允许通过 GET 携带请求体
+		"source_content_type",    // 同上
+		"refresh",                // 触发刷新
+		"wait_for_completion",    // 长时间阻塞
+		"wait_for_active_shards", // 长时间阻塞
+		"op_type",                // 写操作参数
+		"pipeline",               // 写操作参数
+		"scroll",                 // 在服务端保持搜索上下文
+		"requests_per_second",    // by-query 类操作参数
+		"slices",                 // by-query 类操作参数
+		"conflicts",              // by-query 类操作参数
+	},
+}
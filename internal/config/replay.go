@@ -0,0 +1,20 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseReplaySpeed 解析回放速度："1x"、"10x"、"0.5x" 或 "step"（单步，返回 0）
+func ParseReplaySpeed(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "step" {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("无效的回放速度: %s（如 1x、10x 或 step）", s)
+	}
+	return speed, nil
+}
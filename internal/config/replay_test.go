@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestParseReplaySpeed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"1x", 1, false},
+		{"10x", 10, false},
+		{"0.5x", 0.5, false},
+		{"2", 2, false},
+		{" 4X ", 4, false},
+		{"step", 0, false},
+		{"STEP", 0, false},
+		{"0x", 0, true},
+		{"-1x", 0, true},
+		{"fast", 0, true},
+		{"x", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReplaySpeed(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseReplaySpeed(%q) = %v, %v, 期望 %v, 出错 %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
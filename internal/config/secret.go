@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 凭据环境变量（避免凭据出现在 ps 输出或 k8s 启动参数中）
+const (
+	EnvPassword     = "ES_PASSWORD"
+	EnvAPIKey       = "ES_API_KEY"
+	EnvBearerToken  = "ES_BEARER_TOKEN"
+	EnvServiceToken = "ES_SERVICE_TOKEN"
+)
+
+// ResolveSecret 解析凭据值
+//   - "file:/path/to/secret" 从文件读取（去除首尾空白）
+//   - "env:NAME"             从指定环境变量读取
+//   - 其他                   原样返回（空值由 ApplyEnvCredentials 按认证方式回退到默认环境变量）
+func ResolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "file:"):
+		path := strings.TrimPrefix(value, "file:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("读取凭据文件失败: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("环境变量未设置: %s", name)
+		}
+		return strings.TrimSpace(secret), nil
+	}
+	return value, nil
+}
+
+// ApplyEnvCredentials 用默认环境变量补全命令行未指定的凭据
+// 指定了用户名时只读取 $ES_PASSWORD；未指定任何认证方式时才读取 $ES_API_KEY、$ES_BEARER_TOKEN、
+// $ES_SERVICE_TOKEN，避免挂载的 Secret 与命令行指定的认证方式冲突
+func ApplyEnvCredentials(cfg *Config) {
+	if cfg.Username != "" {
+		if cfg.Password == "" {
+			cfg.Password = strings.TrimSpace(os.Getenv(EnvPassword))
+		}
+		return
+	}
+	// 未指定用户名时忽略密码
+	cfg.Password = ""
+	if cfg.APIKey != "" || cfg.BearerToken != "" || cfg.ServiceToken != "" {
+		return
+	}
+
+	cfg.APIKey = strings.TrimSpace(os.Getenv(EnvAPIKey))
+	cfg.BearerToken = strings.TrimSpace(os.Getenv(EnvBearerToken))
+	cfg.ServiceToken = strings.TrimSpace(os.Getenv(EnvServiceToken))
+}
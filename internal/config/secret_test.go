@@ -0,0 +1,45 @@
+package config
+
+import "testing"
+
+func TestApplyEnvCredentials(t *testing.T) {
+	t.Setenv(EnvPassword, "env-pass")
+	t.Setenv(EnvAPIKey, "id:key")
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{
+			name: "用户名密码优先于挂载的 API Key",
+			cfg:  Config{Username: "elastic", Password: "secret"},
+			want: Config{Username: "elastic", Password: "secret"},
+		},
+		{
+			name: "指定用户名时读取默认密码",
+			cfg:  Config{Username: "elastic"},
+			want: Config{Username: "elastic", Password: "env-pass"},
+		},
+		{
+			name: "指定令牌时不读取 API Key",
+			cfg:  Config{BearerToken: "token"},
+			want: Config{BearerToken: "token"},
+		},
+		{
+			name: "未指定认证方式时读取 API Key",
+			cfg:  Config{Password: "ignored"},
+			want: Config{APIKey: "id:key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			ApplyEnvCredentials(&cfg)
+			if cfg.Username != tt.want.Username || cfg.Password != tt.want.Password ||
+				cfg.APIKey != tt.want.APIKey || cfg.BearerToken != tt.want.BearerToken {
+				t.Errorf("凭据 = %+v, 期望 %+v", cfg, tt.want)
+			}
+		})
+	}
+}
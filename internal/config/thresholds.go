@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// ThresholdValues 一组告警阈值（百分比，除特别说明外）
+// 0 表示不检查该项；在覆盖规则中 0 表示沿用上一级的值
+type ThresholdValues struct {
+	JVMHeapWarning           int `json:"jvm_heap_warning,omitempty"`            // JVM 堆内存警告阈值
+	JVMHeapCritical          int `json:"jvm_heap_critical,omitempty"`           // JVM 堆内存严重阈值
+	CPUWarning               int `json:"cpu_warning,omitempty"`                 // CPU 警告阈值
+	CPUCritical              int `json:"cpu_critical,omitempty"`                // CPU 严重阈值
+	MemoryWarning            int `json:"memory_warning,omitempty"`              // 内存警告阈值
+	MemoryCritical           int `json:"memory_critical,omitempty"`             // 内存严重阈值
+	DiskWarning              int `json:"disk_warning,omitempty"`                // 磁盘警告阈值
+	DiskCritical             int `json:"disk_critical,omitempty"`               // 磁盘严重阈值
+	FileDescriptorWarning    int `json:"file_descriptor_warning,omitempty"`     // 文件描述符警告阈值
+	FileDescriptorCritical   int `json:"file_descriptor_critical,omitempty"`    // 文件描述符严重阈值
+	BreakerWarning           int `json:"breaker_warning,omitempty"`             // 断路器使用率警告阈值
+	BreakerCritical          int `json:"breaker_critical,omitempty"`            // 断路器使用率严重阈值
+	OldGCWarning             int `json:"old_gc_warning,omitempty"`              // 单个采集周期内的 Full GC 次数
+	OldGCCritical            int `json:"old_gc_critical,omitempty"`             // 单个采集周期内的 Full GC 次数
+	ShardSizeWarningGB       int `json:"shard_size_warning_gb,omitempty"`       // 主分片平均大小警告阈值（GB）
+	ShardSizeCriticalGB      int `json:"shard_size_critical_gb,omitempty"`      // 主分片平均大小严重阈值（GB）
+	SegmentsPerShardWarning  int `json:"segments_per_shard_warning,omitempty"`  // 主分片平均段数警告阈值
+	SegmentsPerShardCritical int `json:"segments_per_shard_critical,omitempty"` // 主分片平均段数严重阈值
+	PendingTaskWarningSec    int `json:"pending_task_warning_sec,omitempty"`    // 集群任务排队时间警告阈值（秒）
+	PendingTaskCriticalSec   int `json:"pending_task_critical_sec,omitempty"`   // 集群任务排队时间严重阈值（秒）
+	LongTaskWarningSec       int `json:"long_task_warning_sec,omitempty"`       // 任务运行时间警告阈值（秒）
+	LongTaskCriticalSec      int `json:"long_task_critical_sec,omitempty"`      // 任务运行时间严重阈值（秒）
+	ILMStepWarningMin        int `json:"ilm_step_warning_min,omitempty"`        // 生命周期停留在同一步骤的警告阈值（分钟）
+	ILMStepCriticalMin       int `json:"ilm_step_critical_min,omitempty"`       // 生命周期停留在同一步骤的严重阈值（分钟）
+}
+
+// ThresholdOverride 按节点角色或索引名覆盖部分阈值（二者选其一）
+type ThresholdOverride struct {
+	NodeRole     string `json:"node_role,omitempty"`     // 节点角色，如 data_frozen
+	IndexPattern string `json:"index_pattern,omitempty"` // 索引名通配符，如 logs-*
+	ThresholdValues
+}
+
+// Thresholds 阈值配置：全局阈值加按顺序生效的覆盖规则（后面的规则优先）
+type Thresholds struct {
+	ThresholdValues
+	Overrides []ThresholdOverride `json:"overrides,omitempty"`
+}
+
+// DefaultThresholds 默认阈值（磁盘与 ES 默认水位 low 85% / high 90% 一致）
+var DefaultThresholds = Thresholds{
+	ThresholdValues: ThresholdValues{
+		JVMHeapWarning:           75,
+		JVMHeapCritical:          85,
+		CPUWarning:               80,
+		CPUCritical:              90,
+		MemoryWarning:            80,
+		MemoryCritical:           90,
+		DiskWarning:              85,
+		DiskCritical:             90,
+		FileDescriptorWarning:    80,
+		FileDescriptorCritical:   90,
+		BreakerWarning:           80,
+		BreakerCritical:          95,
+		OldGCWarning:             1,
+		OldGCCritical:            5,
+		ShardSizeWarningGB:       50,
+		ShardSizeCriticalGB:      100,
+		SegmentsPerShardWarning:  200,
+		SegmentsPerShardCritical: 1000,
+		PendingTaskWarningSec:    30,
+		PendingTaskCriticalSec:   120,
+		LongTaskWarningSec:       300,
+		LongTaskCriticalSec:      1800,
+		ILMStepWarningMin:        60,
+		ILMStepCriticalMin:       360,
+	},
+}
+
+// ForNode 返回节点的生效阈值（匹配节点任一角色的覆盖规则）
+func (t *Thresholds) ForNode(roles []string) ThresholdValues {
+	values := t.ThresholdValues
+	for _, o := range t.Overrides {
+		if o.NodeRole != "" && slices.Contains(roles, o.NodeRole) {
+			values = values.merge(o.ThresholdValues)
+		}
+	}
+	return values
+}
+
+// ForIndex 返回索引的生效阈值（匹配索引名通配符的覆盖规则）
+func (t *Thresholds) ForIndex(name string) ThresholdValues {
+	values := t.ThresholdValues
+	for _, o := range t.Overrides {
+		if o.IndexPattern == "" {
+			continue
+		}
+		if ok, _ := path.Match(o.IndexPattern, name); ok {
+			values = values.merge(o.ThresholdValues)
+		}
+	}
+	return values
+}
+
+// Validate 检查阈值配置：警告阈值不能高于严重阈值，覆盖规则必须指定节点角色或索引通配符之一
+func (t *Thresholds) Validate() error {
+	if err := t.ThresholdValues.validate(); err != nil {
+		return fmt.Errorf("阈值配置无效: %w", err)
+	}
+	for i, o := range t.Overrides {
+		if (o.NodeRole == "") == (o.IndexPattern == "") {
+			return fmt.Errorf("阈值覆盖规则 %d 必须指定 node_role 或 index_pattern 之一", i+1)
+		}
+		if o.IndexPattern != "" {
+			if _, err := path.Match(o.IndexPattern, ""); err != nil {
+				return fmt.Errorf("阈值覆盖规则 %d 的索引通配符无效: %s", i+1, o.IndexPattern)
+			}
+		}
+		if err := t.ThresholdValues.merge(o.ThresholdValues).validate(); err != nil {
+			return fmt.Errorf("阈值覆盖规则 %d 无效: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Level 按警告 / 严重阈值判断级别，返回 critical、warning 或空字符串（未超过阈值）
+// 以及触发的阈值；阈值为 0 表示不检查该级别
+func Level(value float64, warning, critical int) (string, int) {
+	switch {
+	case critical > 0 && value >= float64(critical):
+		return "critical", critical
+	case warning > 0 && value >= float64(warning):
+		return "warning", warning
+	default:
+		return "", 0
+	}
+}
+
+// merge 用覆盖规则中的非零值替换当前值
+func (v ThresholdValues) merge(o ThresholdValues) ThresholdValues {
+	pick := func(dst *int, src int) {
+		if src != 0 {
+			*dst = src
+		}
+	}
+	pick(&v.JVMHeapWarning, o.JVMHeapWarning)
+	pick(&v.JVMHeapCritical, o.JVMHeapCritical)
+	pick(&v.CPUWarning, o.CPUWarning)
+	pick(&v.CPUCritical, o.CPUCritical)
+	pick(&v.MemoryWarning, o.MemoryWarning)
+	pick(&v.MemoryCritical, o.MemoryCritical)
+	pick(&v.DiskWarning, o.DiskWarning)
+	pick(&v.DiskCritical, o.DiskCritical)
+	pick(&v.FileDescriptorWarning, o.FileDescriptorWarning)
+	pick(&v.FileDescriptorCritical, o.FileDescriptorCritical)
+	pick(&v.BreakerWarning, o.BreakerWarning)
+	pick(&v.BreakerCritical, o.BreakerCritical)
+	pick(&v.OldGCWarning, o.OldGCWarning)
+	pick(&v.OldGCCritical, o.OldGCCritical)
+	pick(&v.ShardSizeWarningGB, o.ShardSizeWarningGB)
+	pick(&v.ShardSizeCriticalGB, o.ShardSizeCriticalGB)
+	pick(&v.SegmentsPerShardWarning, o.SegmentsPerShardWarning)
+	pick(&v.SegmentsPerShardCritical, o.SegmentsPerShardCritical)
+	pick(&v.PendingTaskWarningSec, o.PendingTaskWarningSec)
+	pick(&v.PendingTaskCriticalSec, o.PendingTaskCriticalSec)
+	pick(&v.LongTaskWarningSec, o.LongTaskWarningSec)
+	pick(&v.LongTaskCriticalSec, o.LongTaskCriticalSec)
+	pick(&v.ILMStepWarningMin, o.ILMStepWarningMin)
+	pick(&v.ILMStepCriticalMin, o.ILMStepCriticalMin)
+	return v
+}
+
+// validate 检查每组警告 / 严重阈值
+func (v ThresholdValues) validate() error {
+	pairs := []struct {
+		name              string
+		warning, critical int
+	}{
+		{"jvm_heap", v.JVMHeapWarning, v.JVMHeapCritical},
+		{"cpu", v.CPUWarning, v.CPUCritical},
+		{"memory", v.MemoryWarning, v.MemoryCritical},
+		{"disk", v.DiskWarning, v.DiskCritical},
+		{"file_descriptor", v.FileDescriptorWarning, v.FileDescriptorCritical},
+		{"breaker", v.BreakerWarning, v.BreakerCritical},
+		{"old_gc", v.OldGCWarning, v.OldGCCritical},
+		{"shard_size", v.ShardSizeWarningGB, v.ShardSizeCriticalGB},
+		{"segments_per_shard", v.SegmentsPerShardWarning, v.SegmentsPerShardCritical},
+		{"pending_task", v.PendingTaskWarningSec, v.PendingTaskCriticalSec},
+		{"long_task", v.LongTaskWarningSec, v.LongTaskCriticalSec},
+		{"ilm_step", v.ILMStepWarningMin, v.ILMStepCriticalMin},
+	}
+	for _, p := range pairs {
+		if p.warning < 0 || p.critical < 0 {
+			return fmt.Errorf("%s 阈值不能为负数", p.name)
+		}
+		if p.warning > 0 && p.critical > 0 && p.warning > p.critical {
+			return fmt.Errorf("%s 警告阈值 %d 高于严重阈值 %d", p.name, p.warning, p.critical)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// testThresholds 返回带覆盖规则的阈值配置（全局值为默认阈值）
+func testThresholds(overrides ...ThresholdOverride) *Thresholds {
+	t := DefaultThresholds
+	t.Overrides = overrides
+	return &t
+}
+
+func TestThresholdsForNode(t *testing.T) {
+	th := testThresholds(
+		ThresholdOverride{NodeRole: "data_frozen", ThresholdValues: ThresholdValues{DiskWarning: 95, DiskCritical: 98}},
+		ThresholdOverride{NodeRole: "data", ThresholdValues: ThresholdValues{BreakerWarning: 70, BreakerCritical: 90}},
+		ThresholdOverride{NodeRole: "ml", ThresholdValues: ThresholdValues{BreakerWarning: 60}},
+		ThresholdOverride{IndexPattern: "logs-*", ThresholdValues: ThresholdValues{DiskWarning: 50}},
+	)
+
+	tests := []struct {
+		name                            string
+		roles                           []string
+		diskWarning, diskCritical       int
+		breakerWarning, breakerCritical int
+	}{
+		{"无匹配角色沿用全局值", []string{"master"}, 85, 90, 80, 95},
+		{"data_frozen 磁盘覆盖", []string{"data_frozen"}, 95, 98, 80, 95},
+		{"索引规则不影响节点", []string{"ingest"}, 85, 90, 80, 95},
+		{"多个角色都生效", []string{"data_frozen", "data"}, 95, 98, 70, 90},
+		{"后面的规则优先，0 沿用前一规则", []string{"data", "ml"}, 85, 90, 60, 90},
+		{"规则顺序与角色顺序无关", []string{"ml", "data"}, 85, 90, 60, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := th.ForNode(tt.roles)
+			if v.DiskWarning != tt.diskWarning || v.DiskCritical != tt.diskCritical {
+				t.Errorf("磁盘阈值 = %d/%d, 期望 %d/%d", v.DiskWarning, v.DiskCritical, tt.diskWarning, tt.diskCritical)
+			}
+			if v.BreakerWarning != tt.breakerWarning || v.BreakerCritical != tt.breakerCritical {
+				t.Errorf("断路器阈值 = %d/%d, 期望 %d/%d", v.BreakerWarning, v.BreakerCritical, tt.breakerWarning, tt.breakerCritical)
+			}
+			if v.JVMHeapWarning != DefaultThresholds.JVMHeapWarning {
+				t.Errorf("JVM 警告阈值 = %d, 期望沿用全局值 %d", v.JVMHeapWarning, DefaultThresholds.JVMHeapWarning)
+			}
+		})
+	}
+}
+
+func TestThresholdsForIndex(t *testing.T) {
+	th := testThresholds(
+		ThresholdOverride{IndexPattern: "logs-*", ThresholdValues: ThresholdValues{ShardSizeWarningGB: 30, ShardSizeCriticalGB: 60}},
+		ThresholdOverride{IndexPattern: "logs-audit-*", ThresholdValues: ThresholdValues{ShardSizeWarningGB: 10}},
+		ThresholdOverride{NodeRole: "logs-app", ThresholdValues: ThresholdValues{ShardSizeWarningGB: 1}},
+	)
+
+	tests := []struct {
+		index             string
+		warning, critical int
+	}{
+		{"metrics-1", 50, 100},
+		{"logs-app", 30, 60},
+		{"logs-audit-2024", 10, 60},
+	}
+	for _, tt := range tests {
+		v := th.ForIndex(tt.index)
+		if v.ShardSizeWarningGB != tt.warning || v.ShardSizeCriticalGB != tt.critical {
+			t.Errorf("%s: 分片大小阈值 = %d/%d, 期望 %d/%d",
+				tt.index, v.ShardSizeWarningGB, v.ShardSizeCriticalGB, tt.warning, tt.critical)
+		}
+	}
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds *Thresholds
+		wantErr    string // 为空表示配置有效
+	}{
+		{"默认阈值", testThresholds(), ""},
+		{"有效覆盖规则", testThresholds(
+			ThresholdOverride{NodeRole: "data_frozen", ThresholdValues: ThresholdValues{DiskWarning: 95, DiskCritical: 98}},
+			ThresholdOverride{IndexPattern: "logs-*", ThresholdValues: ThresholdValues{ShardSizeWarningGB: 30}},
+		), ""},
+		{"全局警告高于严重", &Thresholds{ThresholdValues: ThresholdValues{CPUWarning: 95, CPUCritical: 90}},
+			"cpu 警告阈值 95 高于严重阈值 90"},
+		{"全局阈值为负数", &Thresholds{ThresholdValues: ThresholdValues{DiskWarning: -1}}, "disk 阈值不能为负数"},
+		{"合并后警告高于严重", testThresholds(
+			ThresholdOverride{NodeRole: "data_frozen", ThresholdValues: ThresholdValues{DiskWarning: 95}},
+		), "阈值覆盖规则 1 无效: disk 警告阈值 95 高于严重阈值 90"},
+		{"同时指定角色和通配符", testThresholds(
+			ThresholdOverride{NodeRole: "data", IndexPattern: "logs-*"},
+		), "必须指定 node_role 或 index_pattern 之一"},
+		{"未指定角色和通配符", testThresholds(
+			ThresholdOverride{NodeRole: "data"},
+			ThresholdOverride{ThresholdValues: ThresholdValues{DiskWarning: 80}},
+		), "阈值覆盖规则 2 必须指定"},
+		{"无效通配符", testThresholds(
+			ThresholdOverride{IndexPattern: "logs-[*"},
+		), "索引通配符无效: logs-[*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.thresholds.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() 错误 = %v, 期望有效", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() 错误 = %v, 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThresholdValuesMerge(t *testing.T) {
+	base := ThresholdValues{DiskWarning: 85, DiskCritical: 90, OldGCWarning: 1, OldGCCritical: 5}
+	got := base.merge(ThresholdValues{DiskCritical: 97, OldGCWarning: 3})
+	want := ThresholdValues{DiskWarning: 85, DiskCritical: 97, OldGCWarning: 3, OldGCCritical: 5}
+	if got != want {
+		t.Errorf("merge() = %+v, 期望 %+v", got, want)
+	}
+	if base.DiskCritical != 90 {
+		t.Errorf("merge() 修改了原值: DiskCritical = %d", base.DiskCritical)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		value             float64
+		warning, critical int
+		level             string
+		threshold         int
+	}{
+		{50, 80, 90, "", 0},
+		{80, 80, 90, "warning", 80},
+		{90, 80, 90, "critical", 90},
+		{95, 0, 90, "critical", 90},
+		{85, 80, 0, "warning", 80},
+		{100, 0, 0, "", 0},
+	}
+	for _, tt := range tests {
+		level, threshold := Level(tt.value, tt.warning, tt.critical)
+		if level != tt.level || threshold != tt.threshold {
+			t.Errorf("Level(%v, %d, %d) = %q, %d, 期望 %q, %d",
+				tt.value, tt.warning, tt.critical, level, threshold, tt.level, tt.threshold)
+		}
+	}
+}
@@ -3,12 +3,13 @@ package display
 import (
 	"fmt"
 	"sort"
+	"time"
 
-	"github.com/fatih/color"
 	"github.com/Y-vQv-Y/es-monitor/internal/model"
+	"github.com/fatih/color"
 )
 
-// DisplayHealthIssues 显示健康问题
+// DisplayHealthIssues 显示健康问题面板（问题列表已按优先级排序：未恢复、级别、首次发现时间）
 func (t *Terminal) DisplayHealthIssues(issues []model.HealthIssue) {
 	if len(issues) == 0 {
 		StatusGreen.Println("[集群健康检查] 未发现问题")
@@ -16,34 +17,33 @@ func (t *Terminal) DisplayHealthIssues(issues []model.HealthIssue) {
 		return
 	}
 
-	// 按严重程度排序
-	sort.Slice(issues, func(i, j int) bool {
-		levelOrder := map[string]int{"critical": 0, "warning": 1, "info": 2}
-		return levelOrder[issues[i].Level] < levelOrder[issues[j].Level]
-	})
-
 	SectionColor.Println("[集群健康检查 - 发现问题]")
 	fmt.Println(DrawSeparator(DisplayWidth, "-"))
-	fmt.Println()
 
+	now := t.now().Unix()
 	criticalCount := 0
 	warningCount := 0
 	infoCount := 0
+	resolvedCount := 0
 
 	for _, issue := range issues {
 		var levelColor *color.Color
 		var levelText string
 
-		switch issue.Level {
-		case "critical":
+		switch {
+		case issue.Resolved:
+			levelColor = StatusGreen
+			levelText = "[恢复]"
+			resolvedCount++
+		case issue.Level == "critical":
 			levelColor = StatusRed
 			levelText = "[严重]"
 			criticalCount++
-		case "warning":
+		case issue.Level == "warning":
 			levelColor = StatusYellow
 			levelText = "[警告]"
 			warningCount++
-		case "info":
+		default:
 			levelColor = InfoColor
 			levelText = "[提示]"
 			infoCount++
@@ -59,10 +59,17 @@ func (t *Terminal) DisplayHealthIssues(issues []model.HealthIssue) {
 		}
 		fmt.Println()
 
-		if issue.Suggestion != "" {
-			fmt.Printf("       建议: %s\n", issue.Suggestion)
+		// 持续时间：未恢复的问题从首次发现算起，已恢复的问题显示恢复了多久
+		if issue.Resolved {
+			fmt.Printf("       持续 %s，已恢复 %s\n",
+				formatSeconds(issue.LastSeen-issue.FirstSeen), formatSeconds(now-issue.LastSeen))
+		} else {
+			fmt.Printf("       首次发现 %s，已持续 %s\n",
+				time.Unix(issue.FirstSeen, 0).Format("15:04:05"), formatSeconds(now-issue.FirstSeen))
+			if issue.Suggestion != "" {
+				fmt.Printf("       建议: %s\n", issue.Suggestion)
+			}
 		}
-		fmt.Println()
 	}
 
 	// 统计摘要
@@ -74,14 +81,28 @@ func (t *Terminal) DisplayHealthIssues(issues []model.HealthIssue) {
 		StatusYellow.Printf("警告: %d  ", warningCount)
 	}
 	if infoCount > 0 {
-		InfoColor.Printf("提示: %d", infoCount)
+		InfoColor.Printf("提示: %d  ", infoCount)
+	}
+	if resolvedCount > 0 {
+		StatusGreen.Printf("已恢复: %d", resolvedCount)
 	}
 	fmt.Println()
 	fmt.Println()
 }
 
-// DisplayThreadPools 显示线程池状态
-func (t *Terminal) DisplayThreadPools(pools map[string]model.ThreadPoolStats) {
+// formatSeconds 格式化秒数（不足一秒按 0 秒显示）
+func formatSeconds(seconds int64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	return FormatDuration(seconds * 1000)
+}
+
+// keyThreadPools 重点关注的线程池（按显示顺序）
+var keyThreadPools = []string{"search", "write", "get", "management"}
+
+// DisplayThreadPools 显示各节点关键线程池状态（拒绝数按本周期增量和每秒速率显示）
+func (t *Terminal) DisplayThreadPools(pools map[string]map[string]model.ThreadPoolStats) {
 	if len(pools) == 0 {
 		return
 	}
@@ -89,30 +110,46 @@ func (t *Terminal) DisplayThreadPools(pools map[string]model.ThreadPoolStats) {
 	SectionColor.Println("[线程池状态]")
 	fmt.Println(DrawSeparator(DisplayWidth, "-"))
 
-	fmt.Printf("%-15s %8s %8s %10s %12s %12s\n",
-		"线程池", "活跃", "队列", "队列大小", "拒绝数", "完成数")
+	fmt.Printf("%-20s %-12s %6s %6s %8s %10s %12s %12s %14s\n",
+		"节点", "线程池", "线程", "活跃", "队列", "拒绝/秒", "本周期拒绝", "累计拒绝", "完成数")
 	fmt.Println(DrawSeparator(DisplayWidth, "-"))
 
-	// 关键线程池顺序
-	keyPools := []string{"search", "write", "get", "bulk", "management"}
-
-	for _, poolName := range keyPools {
-		if pool, ok := pools[poolName]; ok {
-			fmt.Printf("%-15s %8d %8d %10d %12d %12d",
-				pool.PoolName,
-				pool.Active,
-				pool.Queue,
-				pool.QueueSize,
-				pool.Rejected,
-				pool.Completed)
-
-			// 警告判断
-			if pool.Rejected > 0 {
-				StatusRed.Print(" [有拒绝]")
+	names := make(map[string]string, len(pools))
+	for nodeID, nodePools := range pools {
+		for _, pool := range nodePools {
+			names[nodeID] = pool.NodeName
+			break
+		}
+	}
+
+	for _, nodeID := range sortNodesByName(names) {
+		for _, poolName := range keyThreadPools {
+			pool, ok := pools[nodeID][poolName]
+			if !ok {
+				continue
+			}
+			fmt.Printf("%-20s %-12s %6d %6d %8d ",
+				TruncateString(pool.NodeName, 20), pool.PoolName, pool.Threads, pool.Active, pool.Queue)
+			if pool.RateValid {
+				rateColor := ValueColor
+				if pool.RejectedDelta > 0 {
+					rateColor = StatusRed
+				}
+				rateColor.Printf("%10.1f %12d", pool.RejectedPerSec, pool.RejectedDelta)
+			} else {
+				fmt.Printf("%10s %12s", "-", "-")
 			}
-			queuePercent := float64(pool.Queue) / float64(pool.QueueSize) * 100
-			if queuePercent > 80 {
-				StatusYellow.Printf(" [队列满: %.1f%%]", queuePercent)
+			fmt.Printf(" %12d %14d", pool.Rejected, pool.Completed)
+
+			// 警告判断：只有本周期新增的拒绝才提示
+			if pool.RejectedDelta > 0 {
+				StatusRed.Print(" [拒绝中]")
+			}
+			if pool.QueueSize > 0 {
+				queuePercent := float64(pool.Queue) / float64(pool.QueueSize) * 100
+				if queuePercent > 80 {
+					StatusYellow.Printf(" [队列满: %.1f%%]", queuePercent)
+				}
 			}
 			fmt.Println()
 		}
@@ -121,8 +158,41 @@ func (t *Terminal) DisplayThreadPools(pools map[string]model.ThreadPoolStats) {
 	fmt.Println()
 }
 
-// DisplayCircuitBreakers 显示断路器状态
-func (t *Terminal) DisplayCircuitBreakers(breakers map[string]model.CircuitBreakerStats) {
+// keyBreakers 重点关注的断路器（按显示顺序）
+// DisplayClusterStats 显示集群段、缓存和事务日志汇总（各节点之和）
+func (t *Terminal) DisplayClusterStats(stats model.ClusterStats) {
+	SectionColor.Println("[段与缓存汇总]")
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	fmt.Printf("  段: 总数 %s, 内存 %s | 进行中的合并: %d (%s)\n",
+		ValueColor.Sprint(formatInt64WithCommas(int64(stats.TotalSegments))), formatMB(stats.SegmentMemoryMB),
+		stats.MergesCurrent, formatMB(stats.MergingMB))
+	fmt.Printf("  FieldData: %s (累计驱逐 %d)\n", formatMB(stats.FieldDataMemoryMB), stats.FieldDataEvictions)
+	fmt.Printf("  查询缓存: %s, 命中率 %s (累计驱逐 %d)\n",
+		formatMB(stats.QueryCacheMemoryMB), formatHitRatio(stats.QueryCacheHitRatio), stats.QueryCacheEvictions)
+	fmt.Printf("  请求缓存: %s, 命中率 %s\n", formatMB(stats.RequestCacheMemoryMB), formatHitRatio(stats.RequestCacheHitRatio))
+	fmt.Printf("  事务日志: %s 条操作, %s, 未提交 %s\n",
+		formatInt64WithCommas(stats.TranslogOperations), formatMB(stats.TranslogSizeMB), formatMB(stats.TranslogUncommittedMB))
+	fmt.Println()
+}
+
+// formatMB 格式化以 MB 为单位的大小
+func formatMB(mb float64) string {
+	return FormatBytes(int64(mb * 1024 * 1024))
+}
+
+// formatHitRatio 格式化缓存命中率，没有查询过缓存时显示 -
+func formatHitRatio(ratio float64) string {
+	if ratio < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", ratio)
+}
+
+var keyBreakers = []string{"parent", "fielddata", "request", "in_flight_requests", "accounting"}
+
+// DisplayCircuitBreakers 显示各节点断路器状态（触发次数按本周期增量显示）
+func (t *Terminal) DisplayCircuitBreakers(breakers map[string]map[string]model.CircuitBreakerStats) {
 	if len(breakers) == 0 {
 		return
 	}
@@ -130,26 +200,64 @@ func (t *Terminal) DisplayCircuitBreakers(breakers map[string]model.CircuitBreak
 	SectionColor.Println("[断路器状态]")
 	fmt.Println(DrawSeparator(DisplayWidth, "-"))
 
-	fmt.Printf("%-20s %12s %12s %10s %10s\n",
-		"断路器", "限制", "使用", "触发次数", "使用率")
+	fmt.Printf("%-20s %-20s %12s %12s %9s %12s %10s\n",
+		"节点", "断路器", "限制", "使用", "使用率", "本周期触发", "累计触发")
 	fmt.Println(DrawSeparator(DisplayWidth, "-"))
 
-	for _, breaker := range breakers {
-		fmt.Printf("%-20s %10.2fMB %10.2fMB %10d %9.1f%%",
-			breaker.Name,
-			breaker.LimitSizeMB,
-			breaker.EstimatedMB,
-			breaker.Tripped,
-			breaker.UsedPercent)
-
-		// 警告判断
-		if breaker.Tripped > 0 {
-			StatusRed.Print(" [已触发]")
-		} else if breaker.UsedPercent > 80 {
-			StatusYellow.Print(" [接近限制]")
+	names := make(map[string]string, len(breakers))
+	for nodeID, nodeBreakers := range breakers {
+		for _, breaker := range nodeBreakers {
+			names[nodeID] = breaker.NodeName
+			break
+		}
+	}
+
+	for _, nodeID := range sortNodesByName(names) {
+		for _, breakerName := range keyBreakers {
+			breaker, ok := breakers[nodeID][breakerName]
+			if !ok {
+				continue
+			}
+			fmt.Printf("%-20s %-20s %10.2fMB %10.2fMB ",
+				TruncateString(breaker.NodeName, 20), breaker.Name, breaker.LimitSizeMB, breaker.EstimatedMB)
+			thresholds := t.thresholds.ForNode(breaker.NodeRoles)
+			usedColor, usedLevel := thresholdColor(breaker.UsedPercent, thresholds.BreakerWarning, thresholds.BreakerCritical)
+			usedColor.Printf("%8.1f%%", breaker.UsedPercent)
+			if breaker.RateValid {
+				deltaColor := ValueColor
+				if breaker.TrippedDelta > 0 {
+					deltaColor = StatusRed
+				}
+				deltaColor.Printf(" %12d", breaker.TrippedDelta)
+			} else {
+				fmt.Printf(" %12s", "-")
+			}
+			fmt.Printf(" %10d", breaker.Tripped)
+
+			// 警告判断：只有本周期触发才提示
+			if breaker.TrippedDelta > 0 {
+				StatusRed.Print(" [已触发]")
+			} else if usedLevel != "" {
+				StatusYellow.Print(" [接近限制]")
+			}
+			fmt.Println()
 		}
-		fmt.Println()
 	}
 
 	fmt.Println()
 }
+
+// sortNodesByName 返回按节点名称排序的节点 ID（names 为节点 ID -> 名称，同名节点按 ID 排序）
+func sortNodesByName(names map[string]string) []string {
+	ids := make([]string, 0, len(names))
+	for id := range names {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if names[ids[i]] != names[ids[j]] {
+			return names[ids[i]] < names[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
@@ -0,0 +1,51 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// maxHotFrameRows 每个节点显示的热点栈帧数
+const maxHotFrameRows = 5
+
+// DisplayHotThreads 显示热点线程聚合结果（按节点列出自身耗时最高的栈帧）
+// CPU 百分比为窗口内平均占用单核的比例
+func (t *Terminal) DisplayHotThreads(profile *model.HotThreadsProfile) {
+	SectionColor.Printf("[热点线程 - 最近 %d 次采样]\n", profile.Samples)
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	if len(profile.Nodes) == 0 {
+		fmt.Println("  没有活跃的热点线程")
+		fmt.Println()
+		return
+	}
+
+	window := float64(profile.Samples) * float64(profile.IntervalMillis)
+	percent := func(millis float64) float64 {
+		if window == 0 {
+			return 0
+		}
+		return millis / window * 100
+	}
+
+	for _, node := range profile.Nodes {
+		fmt.Printf("  节点: %s, 热点线程 CPU: ", LabelColor.Sprint(node.Node))
+		cpuColor, _ := thresholdColor(percent(node.TotalMillis), t.thresholds.CPUWarning, t.thresholds.CPUCritical)
+		cpuColor.Printf("%.1f%%\n", percent(node.TotalMillis))
+		if len(node.Frames) == 0 {
+			continue
+		}
+
+		fmt.Printf("    %4s %6s   %-20s %s\n", "自身", "总计", "线程池", "栈帧") // 中文字符占两列
+		for i, f := range node.Frames {
+			if i >= maxHotFrameRows {
+				break
+			}
+			fmt.Printf("    %5.1f%% %5.1f%%  %-20s %s\n", percent(f.SelfMillis), percent(f.TotalMillis),
+				TruncateString(strings.Join(f.Pools, ","), 20), TruncateString(f.Frame, DisplayWidth-42))
+		}
+	}
+	fmt.Println()
+}
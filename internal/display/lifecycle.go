@@ -0,0 +1,129 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// maxLifecycleRows 生命周期问题列表最多显示的行数
+const maxLifecycleRows = 15
+
+// ilmPhaseOrder ILM 阶段的显示顺序（未知阶段按名称排在后面）
+var ilmPhaseOrder = []string{"new", "hot", "warm", "cold", "frozen", "delete"}
+
+// DisplayLifecycle 显示索引生命周期状态：各阶段索引数，以及出错、滚动逾期和停留过久的索引
+// 没有受生命周期管理的索引时不显示
+func (t *Terminal) DisplayLifecycle(overview *model.LifecycleOverview) {
+	if overview.ILMManaged+overview.DSLManaged == 0 && overview.DSLErr == nil {
+		return
+	}
+
+	SectionColor.Printf("[索引生命周期 - ILM 管理 %d 个索引, 数据流生命周期管理 %d 个索引]\n",
+		overview.ILMManaged, overview.DSLManaged)
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	if len(overview.PhaseCounts) > 0 {
+		fmt.Printf("  ILM 阶段: %s\n", formatPhaseCounts(overview.PhaseCounts))
+	}
+	errorColor, overdueColor, stuckColor := StatusGreen, StatusGreen, StatusGreen
+	if overview.Errors > 0 {
+		errorColor = StatusRed
+	}
+	if overview.Overdue > 0 {
+		overdueColor = StatusYellow
+	}
+	if overview.Stuck > 0 {
+		stuckColor = StatusYellow
+	}
+	fmt.Print("  ")
+	errorColor.Printf("出错: %d", overview.Errors)
+	fmt.Print(", ")
+	overdueColor.Printf("滚动逾期: %d", overview.Overdue)
+	fmt.Print(", ")
+	stuckColor.Printf("停留过久: %d\n", overview.Stuck)
+	if overview.DSLErr != nil {
+		t.DisplayError("获取数据流生命周期失败", overview.DSLErr)
+	}
+
+	if len(overview.Indices) == 0 {
+		fmt.Println()
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-33s %-6s %-39s %6s %6s\n", "索引", "问题", "阶段/动作/步骤", "阶段时间", "步骤时间") // 中文字符占两列
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+	for i, s := range overview.Indices {
+		if i >= maxLifecycleRows {
+			fmt.Printf("\n  ... 还有 %d 个索引未显示\n", len(overview.Indices)-maxLifecycleRows)
+			break
+		}
+
+		fmt.Printf("  %-35s ", TruncateString(s.Index, 35))
+		stepColor := StatusYellow
+		switch s.Problem {
+		case model.LifecycleError:
+			StatusRed.Printf("%-6s", "出错")
+		case model.LifecycleRolloverOverdue:
+			StatusYellow.Printf("%-6s", "逾期")
+		default:
+			thresholds := t.thresholds.ForIndex(s.Index)
+			stepColor, _ = thresholdColor(float64(s.StepMillis)/1000/60, thresholds.ILMStepWarningMin, thresholds.ILMStepCriticalMin)
+			stepColor.Printf("%-6s", "停留")
+		}
+
+		if s.Managed == "dsl" {
+			fmt.Printf(" %-38s %10s %10s\n", "数据流生命周期", "-", "-") // 中文字符占两列
+		} else {
+			step := s.Step
+			if s.Problem == model.LifecycleError && s.FailedStep != "" {
+				step = s.FailedStep + " (ERROR)"
+			}
+			fmt.Printf(" %-45s %10s ", TruncateString(s.Phase+"/"+s.Action+"/"+step, 45), FormatDuration(s.PhaseMillis))
+			stepColor.Printf("%10s\n", FormatDuration(s.StepMillis))
+		}
+
+		switch {
+		case s.Problem == model.LifecycleRolloverOverdue:
+			fmt.Printf("      已满足滚动条件 %s (%.1f 倍) 但仍未滚动, 策略: %s\n", s.Reason, s.Overdue, s.Policy)
+		case s.Reason != "":
+			reason := s.Reason
+			if s.RetryCount > 0 {
+				reason = fmt.Sprintf("[已重试 %d 次] %s", s.RetryCount, reason)
+			}
+			fmt.Printf("      %s\n", TruncateString(reason, DisplayWidth-8))
+		}
+	}
+	fmt.Println()
+}
+
+// formatPhaseCounts 按阶段顺序格式化各阶段索引数，如 "hot 12, warm 30, delete 2"
+func formatPhaseCounts(counts map[string]int) string {
+	phases := make([]string, 0, len(counts))
+	for phase := range counts {
+		phases = append(phases, phase)
+	}
+	order := func(phase string) int {
+		for i, p := range ilmPhaseOrder {
+			if p == phase {
+				return i
+			}
+		}
+		return len(ilmPhaseOrder)
+	}
+	sort.Slice(phases, func(i, j int) bool {
+		if oi, oj := order(phases[i]), order(phases[j]); oi != oj {
+			return oi < oj
+		}
+		return phases[i] < phases[j]
+	})
+
+	parts := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		parts = append(parts, fmt.Sprintf("%s %d", phase, counts[phase]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,84 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// maxPendingRows 待处理任务列表最多显示的行数
+const maxPendingRows = 10
+
+// sparkBlocks 队列深度趋势使用的字符（由低到高）
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// DisplayPendingTasks 显示主节点待处理任务队列（按排队时间降序）
+// 队列为空且最近没有积压时不显示
+func (t *Terminal) DisplayPendingTasks(overview *model.PendingTaskOverview) {
+	peak := 0
+	for _, depth := range overview.History {
+		peak = max(peak, depth)
+	}
+	if len(overview.Tasks) == 0 && peak == 0 {
+		return
+	}
+
+	SectionColor.Println("[待处理集群任务]")
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	thresholds := t.thresholds.ThresholdValues
+	oldestColor, _ := thresholdColor(float64(overview.OldestMillis)/1000,
+		thresholds.PendingTaskWarningSec, thresholds.PendingTaskCriticalSec)
+	fmt.Printf("  队列深度: %d, 执行中: %d, 最久排队: ", len(overview.Tasks), overview.Executing)
+	oldestColor.Println(FormatDuration(overview.OldestMillis))
+
+	if len(overview.History) > 1 {
+		fmt.Printf("  深度趋势: %s (最近 %d 个周期, 峰值 %d)\n",
+			sparkline(overview.History, peak), len(overview.History), peak)
+	}
+
+	if len(overview.Tasks) == 0 {
+		fmt.Println()
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-7s %6s %-3s %s\n", "优先级", "排队时间", "执行中", "来源") // 中文字符占两列
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+	for i, task := range overview.Tasks {
+		if i >= maxPendingRows {
+			fmt.Printf("\n  ... 还有 %d 个任务未显示\n", len(overview.Tasks)-maxPendingRows)
+			break
+		}
+
+		priorityColor := LabelColor
+		if task.Priority == "IMMEDIATE" || task.Priority == "URGENT" {
+			priorityColor = StatusYellow
+		}
+		fmt.Print("  ")
+		priorityColor.Printf("%-10s", task.Priority)
+		ageColor, _ := thresholdColor(float64(task.TimeInQueueMillis)/1000,
+			thresholds.PendingTaskWarningSec, thresholds.PendingTaskCriticalSec)
+		ageColor.Printf(" %10s", FormatDuration(task.TimeInQueueMillis))
+		executing := "否"
+		if task.Executing {
+			executing = "是"
+		}
+		fmt.Printf(" %-6s %s\n", executing, TruncateString(task.Source, DisplayWidth-32))
+	}
+	fmt.Println()
+}
+
+// sparkline 把数值序列绘制为迷你趋势图（按峰值归一化）
+func sparkline(values []int, peak int) string {
+	var sb strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 {
+			level = v * (len(sparkBlocks) - 1) / peak
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
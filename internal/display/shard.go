@@ -0,0 +1,87 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// maxShardRows 分片列表最多显示的行数
+const maxShardRows = 20
+
+// DisplayShards 显示非 STARTED 分片、未分配原因和分配诊断
+func (t *Terminal) DisplayShards(overview *model.ShardOverview) {
+	title := "[分片状态]"
+	if overview.Filter != "" {
+		title = fmt.Sprintf("[分片状态 - 索引: %s]", overview.Filter)
+	}
+	SectionColor.Println(title)
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	fmt.Printf("  分片总数: %d, 正常: %d, 迁移中: %d, 初始化中: %d, ",
+		overview.Total, overview.Started, overview.Relocating, overview.Initializing)
+	unassignedColor := StatusGreen
+	if overview.Unassigned > 0 {
+		unassignedColor = StatusRed
+	}
+	unassignedColor.Printf("未分配: %d\n", overview.Unassigned)
+
+	if len(overview.Shards) == 0 {
+		fmt.Println()
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-35s %5s %-4s %-13s %-20s %s\n", "索引", "分片", "类型", "状态", "节点", "原因")
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+	for i, s := range overview.Shards {
+		if i >= maxShardRows {
+			fmt.Printf("\n  ... 还有 %d 个分片未显示\n", len(overview.Shards)-maxShardRows)
+			break
+		}
+
+		kind := "副本"
+		if s.Primary() {
+			kind = "主"
+		}
+		fmt.Printf("  %-35s %5s %-4s ", TruncateString(s.Index, 35), s.Shard, kind)
+		stateColor := StatusYellow
+		if s.State == "UNASSIGNED" && s.Primary() {
+			stateColor = StatusRed
+		}
+		stateColor.Printf("%-13s", s.State)
+		fmt.Printf(" %-20s", TruncateString(s.Node, 20))
+		if s.UnassignedReason != "" {
+			fmt.Printf(" %s", s.UnassignedReason)
+			if at, err := time.Parse(time.RFC3339, s.UnassignedAt); err == nil {
+				fmt.Printf(" (%s 前)", FormatDuration(t.now().Sub(at).Milliseconds()))
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	// 分配诊断
+	if overview.ExplainErr != nil {
+		t.DisplayError("获取分配诊断失败", overview.ExplainErr)
+	}
+	for _, a := range overview.Allocations {
+		kind := "副本"
+		if a.Primary {
+			kind = "主分片"
+		}
+		fmt.Printf("  [分配诊断] %s[%d] %s: ", a.Index, a.Shard, kind)
+		StatusRed.Println(strings.ToUpper(a.CanAllocate))
+		if a.Explanation != "" {
+			fmt.Printf("      %s\n", a.Explanation)
+		}
+		for _, d := range a.Deciders {
+			fmt.Printf("      %s (%d 个节点): %s\n", LabelColor.Sprint(d.Decider), d.Nodes, d.Explanation)
+		}
+	}
+	if len(overview.Allocations) > 0 || overview.ExplainErr != nil {
+		fmt.Println()
+	}
+}
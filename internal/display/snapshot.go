@@ -0,0 +1,109 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// DisplaySnapshots 显示快照生命周期策略的最近成功 / 失败、下一次执行，以及正在执行的快照进度
+func (t *Terminal) DisplaySnapshots(overview *model.SnapshotOverview) {
+	stats := overview.Stats
+	SectionColor.Printf("[快照 - %d 个策略, 累计完成 %d, 失败 %d, 已删除 %d]\n", len(overview.Policies),
+		stats.TotalSnapshotsTaken, stats.TotalSnapshotsFailed, stats.TotalSnapshotsDeleted)
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	if stats.RetentionRuns > 0 {
+		fmt.Printf("  保留策略: 执行 %d 次, ", stats.RetentionRuns)
+		retentionColor := StatusGreen
+		if stats.RetentionFailed+stats.RetentionTimedOut+stats.TotalSnapshotDeletionFailures > 0 {
+			retentionColor = StatusYellow
+		}
+		retentionColor.Printf("失败 %d 次, 超时 %d 次, 删除失败 %d 个\n",
+			stats.RetentionFailed, stats.RetentionTimedOut, stats.TotalSnapshotDeletionFailures)
+	}
+
+	if len(overview.Policies) == 0 {
+		fmt.Println("  未配置快照生命周期策略")
+	} else {
+		now := t.now().UnixMilli()
+		fmt.Printf("  %-22s %-18s %12s %12s %12s %10s\n", "策略", "仓库", "上次成功", "上次失败", "下次执行", "成功窗口") // 中文字符占两列
+		fmt.Println(DrawSeparator(DisplayWidth, "-"))
+		for _, p := range overview.Policies {
+			nameColor := StatusGreen
+			switch {
+			case p.Overdue && p.Configured:
+				nameColor = StatusRed
+			case p.Overdue:
+				nameColor = StatusYellow // 按调度计划推算的窗口只作为警告
+			}
+			nameColor.Printf("  %-24s", TruncateString(p.Policy, 24))
+			fmt.Printf(" %-20s ", TruncateString(p.Repository, 20))
+
+			// 时间列都以一个中文字符（前 / 后）结尾，占两列
+			if p.LastSuccessAt > 0 {
+				nameColor.Printf("%15s", formatAgo(now, p.LastSuccessAt))
+			} else {
+				nameColor.Printf("%12s", "从未成功")
+			}
+			if p.LastFailureAt > p.LastSuccessAt {
+				StatusRed.Printf(" %15s", formatAgo(now, p.LastFailureAt))
+			} else if p.LastFailureAt > 0 {
+				fmt.Printf(" %15s", formatAgo(now, p.LastFailureAt))
+			} else {
+				fmt.Printf(" %16s", "-")
+			}
+			if p.NextRunAt > 0 {
+				fmt.Printf(" %15s", formatIn(now, p.NextRunAt))
+			} else {
+				fmt.Printf(" %16s", "-")
+			}
+			window := "-"
+			if p.WindowMillis > 0 {
+				window = FormatDuration(p.WindowMillis)
+				if p.Configured {
+					window += "*"
+				}
+			}
+			fmt.Printf(" %14s\n", window)
+
+			if p.InProgress != "" {
+				InfoColor.Printf("      执行中: %s\n", p.InProgress)
+			}
+			if p.LastFailureAt > p.LastSuccessAt && p.FailureReason != "" {
+				fmt.Printf("      最近失败: %s\n", TruncateString(p.FailureReason, DisplayWidth-20))
+			}
+		}
+		fmt.Println("  成功窗口: 上次成功后应在该时间内再次成功（* 表示来自配置文件，否则按调度计划推算）")
+	}
+
+	if len(overview.Running) > 0 {
+		fmt.Println()
+		LabelColor.Println("  正在执行的快照:")
+		for _, s := range overview.Running {
+			shards := s.ShardsStats
+			fmt.Printf("    %s/%s %s, 分片 %d/%d", s.Repository, s.Snapshot, s.State, shards.Done, shards.Total)
+			if shards.Failed > 0 {
+				StatusRed.Printf(" (失败 %d)", shards.Failed)
+			}
+			processed, total := s.Stats.Processed.SizeInBytes, s.Stats.Total.SizeInBytes
+			percent := 0.0
+			if total > 0 {
+				percent = float64(processed) / float64(total) * 100
+			}
+			fmt.Printf(", 数据 %s / %s (%.1f%%), 已用时 %s\n",
+				FormatBytes(processed), FormatBytes(total), percent, FormatDuration(s.Stats.TimeInMillis))
+		}
+	}
+	fmt.Println()
+}
+
+// formatAgo 格式化距今的时长，如 "3h 20m 前"
+func formatAgo(now, at int64) string {
+	return FormatDuration(max(now-at, 0)) + " 前"
+}
+
+// formatIn 格式化距离未来时间的时长，如 "2h 5m 后"
+func formatIn(now, at int64) string {
+	return FormatDuration(max(at-now, 0)) + " 后"
+}
@@ -0,0 +1,42 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// DisplayTasks 显示运行时间最长的任务（只显示任务 ID，监控程序不会取消任务）
+// 没有运行超过 1 秒的任务时不显示
+func (t *Terminal) DisplayTasks(overview *model.TaskOverview) {
+	if len(overview.Tasks) == 0 {
+		return
+	}
+
+	SectionColor.Printf("[长时间运行任务 - 共 %d 个任务]\n", overview.Total)
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+	fmt.Printf("  %-26s %-14s %8s %3s %-3s %s\n", "任务 ID", "节点", "运行时间", "子任务", "可取消", "动作") // 中文字符占两列
+	fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+	thresholds := t.thresholds.ThresholdValues
+	for _, task := range overview.Tasks {
+		fmt.Printf("  %-28s %-16s ", TruncateString(task.TaskID, 28), TruncateString(task.NodeName, 16))
+		ageColor, _ := thresholdColor(float64(task.RunningMillis)/1000,
+			thresholds.LongTaskWarningSec, thresholds.LongTaskCriticalSec)
+		ageColor.Printf("%12s", FormatDuration(task.RunningMillis))
+		cancellable := "否"
+		switch {
+		case task.Cancelled:
+			cancellable = "取消中"
+		case task.Cancellable:
+			cancellable = "是"
+		}
+		fmt.Printf(" %6d %-6s %s\n", task.Children, cancellable, task.Action)
+		if task.Description != "" {
+			fmt.Printf("      %s\n", TruncateString(task.Description, DisplayWidth-8))
+		}
+	}
+	fmt.Println()
+	fmt.Println("  需要终止任务时请手动执行: POST _tasks/<任务 ID>/_cancel（监控程序为只读，不会取消任务）")
+	fmt.Println()
+}
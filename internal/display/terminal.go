@@ -2,12 +2,14 @@ package display
 
 import (
   "fmt"
+  "sort"
   "strings"
   "time"
   "strconv"
 
   "github.com/Y-vQv-Y/es-monitor/internal/config"
   "github.com/Y-vQv-Y/es-monitor/internal/model"
+  "github.com/fatih/color"
 )
 
 const (
@@ -16,27 +18,48 @@ const (
 
 // Terminal 终端显示
 type Terminal struct {
-  thresholds config.Thresholds
+  thresholds *config.Thresholds
+  now        func() time.Time // 时钟（回放模式下为录制时间）
 }
 
-// NewTerminal 创建终端显示器
-func NewTerminal() *Terminal {
+// NewTerminal 创建终端显示器（颜色和警告标记与告警使用同一组阈值），thresholds 为空时使用 DefaultThresholds
+func NewTerminal(thresholds *config.Thresholds) *Terminal {
+  if thresholds == nil {
+    thresholds = &config.DefaultThresholds
+  }
   return &Terminal{
-    thresholds: config.DefaultThresholds,
+    thresholds: thresholds,
+    now:        time.Now,
   }
 }
 
+// SetClock 设置时钟（回放模式下速率和更新时间按录制时间计算）
+func (t *Terminal) SetClock(now func() time.Time) {
+  t.now = now
+}
+
 // Clear 清屏
 func (t *Terminal) Clear() {
   fmt.Print("\033[H\033[2J")
 }
 
-// DisplayHeader 显示标题
-func (t *Terminal) DisplayHeader() {
+// DisplayHeader 显示标题（包含当前连接的节点）
+func (t *Terminal) DisplayHeader(endpoint string, alive, total int) {
   t.Clear()
   TitleColor.Println(DrawSeparator(DisplayWidth, "="))
   TitleColor.Println(PadRight("  Elasticsearch 生产环境监控工具 (只读安全模式)", DisplayWidth))
   TitleColor.Println(DrawSeparator(DisplayWidth, "="))
+
+  if endpoint != "" {
+    fmt.Printf("当前节点: %s  ", ValueColor.Sprint(endpoint))
+    nodeColor := StatusGreen
+    if alive == 0 {
+      nodeColor = StatusRed
+    } else if alive < total {
+      nodeColor = StatusYellow
+    }
+    nodeColor.Printf("(可用节点 %d/%d)\n", alive, total)
+  }
   fmt.Println()
 }
 
@@ -76,6 +99,37 @@ func (t *Terminal) DisplayClusterHealth(health *model.ClusterHealth) {
   fmt.Println()
 }
 
+// DisplayHealthReport 显示健康报告（ES 8.7+）
+func (t *Terminal) DisplayHealthReport(report *model.HealthReport) {
+  SectionColor.Println("[健康报告]")
+  fmt.Println(DrawSeparator(DisplayWidth, "-"))
+
+  statusColor := GetStatusColor(report.Status)
+  fmt.Printf("总体状态: %s\n", statusColor.Sprint(strings.ToUpper(report.Status)))
+
+  names := make([]string, 0, len(report.Indicators))
+  for name := range report.Indicators {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  for _, name := range names {
+    indicator := report.Indicators[name]
+    if indicator.Status == "green" {
+      continue
+    }
+    GetStatusColor(indicator.Status).Printf("  %-30s %-8s", name, strings.ToUpper(indicator.Status))
+    fmt.Printf(" %s\n", TruncateString(indicator.Symptom, 80))
+  }
+  fmt.Println()
+}
+
+// DisplayUnsupported 显示当前版本不支持的区域
+func (t *Terminal) DisplayUnsupported(section string, err error) {
+  InfoColor.Printf("[%s] %v\n", section, err)
+  fmt.Println()
+}
+
 // DisplaySystemMetrics 显示系统资源详细信息（完整版）
 func (t *Terminal) DisplaySystemMetrics(metrics *model.SystemMetrics) {
   SectionColor.Println("[系统资源详细监控 - 实时数据]")
@@ -91,12 +145,12 @@ func (t *Terminal) DisplaySystemMetrics(metrics *model.SystemMetrics) {
     metrics.CPU.LogicalCores)
 
   // CPU 总体使用率
-  cpuColor := GetPercentColor(metrics.CPU.UsagePercent, 60, 80)
+  cpuColor, cpuLevel := thresholdColor(metrics.CPU.UsagePercent, t.thresholds.CPUWarning, t.thresholds.CPUCritical)
   fmt.Print("  总使用率: ")
   cpuColor.Printf("%.2f%%", metrics.CPU.UsagePercent)
-  if metrics.CPU.UsagePercent >= 80 {
+  if cpuLevel == "critical" {
     fmt.Print(" [严重: CPU 过载]")
-  } else if metrics.CPU.UsagePercent >= 60 {
+  } else if cpuLevel == "warning" {
     fmt.Print(" [警告: CPU 偏高]")
   }
   fmt.Println()
@@ -168,13 +222,13 @@ func (t *Terminal) DisplaySystemMetrics(metrics *model.SystemMetrics) {
   fmt.Println(DrawSeparator(90, "."))
 
   // 物理内存
-  memColor := GetPercentColor(metrics.Memory.UsedPercent, 80, 90)
+  memColor, memLevel := thresholdColor(metrics.Memory.UsedPercent, t.thresholds.MemoryWarning, t.thresholds.MemoryCritical)
   fmt.Printf("  物理内存: 总量=%s\n", FormatBytesUint64(metrics.Memory.Total))
   fmt.Print("            已使用=")
   memColor.Printf("%s (%.2f%%)", FormatBytesUint64(metrics.Memory.Used), metrics.Memory.UsedPercent)
-  if metrics.Memory.UsedPercent >= 90 {
+  if memLevel == "critical" {
     fmt.Print(" [严重: 内存不足]")
-  } else if metrics.Memory.UsedPercent >= 80 {
+  } else if memLevel == "warning" {
     fmt.Print(" [警告: 内存偏高]")
   }
   fmt.Println()
@@ -354,7 +408,7 @@ func (t *Terminal) DisplayDiskMetrics(metrics *model.DiskMetrics) {
         continue
       }
 
-      diskColor := GetPercentColor(part.UsedPercent, 80, 90)
+      diskColor, diskLevel := thresholdColor(part.UsedPercent, t.thresholds.DiskWarning, t.thresholds.DiskCritical)
       mountpoint := TruncateString(part.Mountpoint, 25)
       device := TruncateString(part.Device, 20)
 
@@ -367,9 +421,9 @@ func (t *Terminal) DisplayDiskMetrics(metrics *model.DiskMetrics) {
 
       diskColor.Printf("%9.2f%%", part.UsedPercent)
 
-      if part.UsedPercent >= 90 {
+      if diskLevel == "critical" {
         StatusRed.Print(" [严重]")
-      } else if part.UsedPercent >= 80 {
+      } else if diskLevel == "warning" {
         StatusYellow.Print(" [警告]")
       }
       fmt.Println()
@@ -503,12 +557,14 @@ func (t *Terminal) DisplayNetworkMetrics(metrics *model.NetworkMetrics) {
 }
 
 // DisplayNodeStats 显示节点统计（简化版，避免重复）
-func (t *Terminal) DisplayNodeStats(stats *model.NodeStats, prevData map[string]*PrevNodeMetrics) {
+// oldGC 为采集器计算的本周期 Full GC 次数（节点 ID -> 次数），与健康问题使用同一份增量
+func (t *Terminal) DisplayNodeStats(stats *model.NodeStats, prevData map[string]*PrevNodeMetrics, oldGC map[string]int64) {
   SectionColor.Println("[Elasticsearch 节点统计]")
   fmt.Println(DrawSeparator(DisplayWidth, "-"))
 
   for nodeID, node := range stats.Nodes {
     fmt.Printf("\n节点: %s (IP: %s)\n", LabelColor.Sprint(node.Name), node.IP)
+    thresholds := t.thresholds.ForNode(node.Roles)
     fmt.Println(DrawSeparator(DisplayWidth, "."))
 
     // JVM 堆内存
@@ -516,37 +572,95 @@ func (t *Terminal) DisplayNodeStats(stats *model.NodeStats, prevData map[string]
     heapUsedGB := float64(node.JVM.Mem.HeapUsedInBytes) / 1024 / 1024 / 1024
     heapMaxGB := float64(node.JVM.Mem.HeapMaxInBytes) / 1024 / 1024 / 1024
 
-    heapColor := GetThresholdColor(heapPercent, t.thresholds.JVMHeapWarning, t.thresholds.JVMHeapCritical)
+    heapColor, heapLevel := thresholdColor(float64(heapPercent), thresholds.JVMHeapWarning, thresholds.JVMHeapCritical)
     fmt.Print("  JVM 堆内存: ")
     heapColor.Printf("%d%% (%.2fG/%.2fG)", heapPercent, heapUsedGB, heapMaxGB)
-    if heapPercent >= t.thresholds.JVMHeapCritical {
+    if heapLevel == "critical" {
       fmt.Print(" [严重: 内存压力大]")
-    } else if heapPercent >= t.thresholds.JVMHeapWarning {
+    } else if heapLevel == "warning" {
       fmt.Print(" [警告: 内存偏高]")
     }
     fmt.Println()
 
-    // GC 统计
+    // GC 统计（Full GC 按本周期增量判断，累计次数随运行时间增长）
     youngGC := node.JVM.GC.Collectors.Young.CollectionCount
-    oldGC := node.JVM.GC.Collectors.Old.CollectionCount
-    fmt.Printf("  GC 次数: Young=%d, Old=%d", youngGC, oldGC)
-    if oldGC > 10 {
-      StatusYellow.Print(" [警告: Full GC 频繁]")
+    fmt.Printf("  GC 次数: Young=%d, Old=%d", youngGC, node.JVM.GC.Collectors.Old.CollectionCount)
+    if delta, ok := oldGC[nodeID]; ok {
+      gcColor, gcLevel := thresholdColor(float64(delta), thresholds.OldGCWarning, thresholds.OldGCCritical)
+      if gcLevel == "critical" {
+        gcColor.Printf(" [严重: 本周期 Full GC %d 次]", delta)
+      } else if gcLevel == "warning" {
+        gcColor.Printf(" [警告: 本周期 Full GC %d 次]", delta)
+      }
     }
     fmt.Println()
 
+    // 磁盘（按节点角色的阈值，如冷冻层节点）
+    if total := node.FS.Total.TotalInBytes; total > 0 {
+      diskPercent := float64(total-node.FS.Total.AvailableInBytes) / float64(total) * 100
+      diskColor, diskLevel := thresholdColor(diskPercent, thresholds.DiskWarning, thresholds.DiskCritical)
+      fmt.Print("  磁盘: ")
+      diskColor.Printf("%.1f%%", diskPercent)
+      fmt.Printf(" (可用 %s / 总量 %s)", FormatBytes(node.FS.Total.AvailableInBytes), FormatBytes(total))
+      if diskLevel == "critical" {
+        fmt.Print(" [严重: 磁盘空间不足]")
+      } else if diskLevel == "warning" {
+        fmt.Print(" [警告: 磁盘偏高]")
+      }
+      fmt.Println()
+    }
+
+    // 写入压力（ES 7.9+）
+    if ip := node.IndexingPressure; ip != nil {
+      rejections := ip.Memory.Total.CoordinatingRejections +
+        ip.Memory.Total.PrimaryRejections +
+        ip.Memory.Total.ReplicaRejections
+      fmt.Printf("  写入压力: %s / %s",
+        FormatBytes(ip.Memory.Current.AllInBytes),
+        FormatBytes(ip.Memory.LimitInBytes))
+      if ip.Memory.LimitInBytes > 0 {
+        fmt.Printf(" (%.1f%%)", float64(ip.Memory.Current.AllInBytes)/float64(ip.Memory.LimitInBytes)*100)
+      }
+      fmt.Printf(", 累计拒绝=%d", rejections)
+      if rejections > 0 {
+        StatusYellow.Print(" [警告: 存在写入拒绝]")
+      }
+      fmt.Println()
+    } else {
+      fmt.Println("  写入压力: 当前版本不支持 (需要 7.9+)")
+    }
+
     // 文档和存储
     fmt.Printf("  文档总数: %s, 存储大小: %s\n",
       ValueColor.Sprint(formatInt64WithCommas(int64(node.Indices.Docs.Count))),
       ValueColor.Sprint(FormatBytes(node.Indices.Store.SizeInBytes)))
 
+    // 段、合并和事务日志
+    indices := node.Indices
+    fmt.Printf("  段: %d 个 (%s), 合并中: %d, 刷新: %d 次, 刷盘: %d 次, 事务日志未提交: %s\n",
+      indices.Segments.Count, FormatBytes(indices.Segments.MemoryInBytes), indices.Merges.Current,
+      indices.Refresh.Total, indices.Flush.Total, FormatBytes(indices.Translog.UncommittedSizeInBytes))
+
+    // 缓存
+    fmt.Printf("  缓存: FieldData=%s, 查询缓存=%s (命中率 %s), 请求缓存=%s (命中率 %s)\n",
+      FormatBytes(indices.FieldData.MemorySizeInBytes),
+      FormatBytes(indices.QueryCache.MemorySizeInBytes), formatHitRatio(indices.QueryCache.HitRatio()),
+      FormatBytes(indices.RequestCache.MemorySizeInBytes), formatHitRatio(indices.RequestCache.HitRatio()))
+
     // 实时速率（添加计算说明）
-    if prev, ok := prevData[nodeID]; ok {
-      elapsed := time.Since(prev.Timestamp).Seconds()
+    if prevData == nil {
+      fmt.Println("  写入速率: 数据已过期，暂停计算")
+      fmt.Println("  查询速率: 数据已过期，暂停计算")
+    } else if prev, ok := prevData[nodeID]; ok {
+      elapsed := t.now().Sub(prev.Timestamp).Seconds()
       if elapsed > 0 {
         // 计算速率：(当前值 - 上次值) / 时间间隔
         indexDelta := node.Indices.Indexing.IndexTotal - prev.IndexTotal
         queryDelta := node.Indices.Search.QueryTotal - prev.QueryTotal
+        if indexDelta < 0 || queryDelta < 0 {
+          // 计数器变小说明节点重启过，以当前值作为增量
+          indexDelta, queryDelta = node.Indices.Indexing.IndexTotal, node.Indices.Search.QueryTotal
+        }
         
         indexRate := float64(indexDelta) / elapsed
         queryRate := float64(queryDelta) / elapsed
@@ -582,8 +696,8 @@ func (t *Terminal) DisplayIndexStats(indices []model.IndexInfo, stats *model.Ind
   }
 
   // 显示表头（固定宽度）
-  fmt.Printf("%-35s %-10s %-12s %-15s %-15s\n",
-    "索引名称", "状态", "分片(主/副)", "文档数", "大小")
+  fmt.Printf("%-35s %-10s %-12s %-15s %-15s %-10s\n",
+    "索引名称", "状态", "分片(主/副)", "文档数", "大小", "段/主分片")
   fmt.Println(DrawSeparator(DisplayWidth, "-"))
 
   count := 0
@@ -608,10 +722,36 @@ func (t *Terminal) DisplayIndexStats(indices []model.IndexInfo, stats *model.Ind
     // 左对齐索引名，右对齐数字
     fmt.Printf("%-35s ", indexName)
     statusColor.Printf("%-10s ", strings.ToUpper(idx.Health))
-    fmt.Printf("%-12s %15s %15s\n",
+    fmt.Printf("%-12s %15s %15s",
       shardInfo,
       docCount,
       size)
+
+    // 主分片平均段数和平均大小（按索引名匹配阈值覆盖规则）
+    primaries, _ := strconv.Atoi(idx.Pri)
+    thresholds := t.thresholds.ForIndex(idx.Index)
+    var stat model.IndexStat
+    ok := false
+    if stats != nil {
+      stat, ok = stats.Indices[idx.Index]
+    }
+    if ok && primaries > 0 {
+      perShard := float64(stat.Primaries.Segments.Count) / float64(primaries)
+      segColor, _ := thresholdColor(perShard, thresholds.SegmentsPerShardWarning, thresholds.SegmentsPerShardCritical)
+      segColor.Printf(" %10.0f", perShard)
+    } else {
+      fmt.Printf(" %10s", "-")
+    }
+    priBytes, err := strconv.ParseInt(idx.PriStoreSize, 10, 64)
+    if primaries > 0 && err == nil {
+      shardGB := float64(priBytes) / float64(primaries) / 1024 / 1024 / 1024
+      if _, level := thresholdColor(shardGB, thresholds.ShardSizeWarningGB, thresholds.ShardSizeCriticalGB); level == "critical" {
+        StatusRed.Printf(" [分片过大: %.1fG]", shardGB)
+      } else if level == "warning" {
+        StatusYellow.Printf(" [分片偏大: %.1fG]", shardGB)
+      }
+    }
+    fmt.Println()
   }
 
   if len(indices) > 20 {
@@ -621,6 +761,19 @@ func (t *Terminal) DisplayIndexStats(indices []model.IndexInfo, stats *model.Ind
   fmt.Println()
 }
 
+// thresholdColor 按阈值判断级别（与告警一致）并返回对应颜色
+func thresholdColor(value float64, warning, critical int) (*color.Color, string) {
+  level, _ := config.Level(value, warning, critical)
+  switch level {
+  case "critical":
+    return StatusRed, level
+  case "warning":
+    return StatusYellow, level
+  default:
+    return StatusGreen, level
+  }
+}
+
 // formatNumberWithCommas 为数字添加千分位分隔符
 func formatNumberWithCommas(numStr string) string {
   // 如果是空或 "0"，直接返回
@@ -655,10 +808,50 @@ func formatInt64WithCommas(n int64) string {
 func (t *Terminal) DisplayFooter() {
   fmt.Println(DrawSeparator(DisplayWidth, "="))
   fmt.Printf("最后更新: %s | 只读安全模式 | 按 Ctrl+C 安全退出\n",
-    time.Now().Format("2006-01-02 15:04:05"))
+    t.now().Format("2006-01-02 15:04:05"))
 }
 
 // DisplayError 显示错误
 func (t *Terminal) DisplayError(msg string, err error) {
   ErrorColor.Printf("[错误] %s: %v\n", msg, err)
 }
+
+// DisplayStaleNotice 显示数据过期提示（本轮采集失败，沿用上次成功的数据）
+func (t *Terminal) DisplayStaleNotice(msg string, err error, collectedAt time.Time) {
+  StatusYellow.Printf("[数据过期] %s: %v\n", msg, err)
+  StatusYellow.Printf("           以下为 %d 秒前的数据\n", int(t.now().Sub(collectedAt).Seconds()))
+}
+
+// DisplayReplayStatus 显示回放进度（total 为 0 表示连续回放）
+func (t *Terminal) DisplayReplayStatus(at time.Time, tick, total int) {
+  if total > 0 {
+    StatusYellow.Printf("[回放] 录制时间 %s | 第 %d/%d 个周期 | 按回车前进\n\n",
+      at.Format("2006-01-02 15:04:05"), tick, total)
+    return
+  }
+  StatusYellow.Printf("[回放] 录制时间 %s\n\n", at.Format("2006-01-02 15:04:05"))
+}
+
+// DisplayEndpointErrors 显示最近出现错误的节点连接统计
+func (t *Terminal) DisplayEndpointErrors(statuses []model.EndpointStatus) {
+  printed := false
+  for _, st := range statuses {
+    if st.Errors == 0 || time.Since(st.LastErrorAt) > 10*time.Minute {
+      continue
+    }
+    printed = true
+    statusColor := StatusYellow
+    state := "可用"
+    if !st.Alive {
+      statusColor = StatusRed
+      state = "已隔离"
+    }
+    statusColor.Printf("节点 %s [%s] 失败 %d/%d 次", st.URL, state, st.Errors, st.Requests)
+    fmt.Printf(", 最近错误 (%d 秒前): %s\n",
+      int(time.Since(st.LastErrorAt).Seconds()),
+      TruncateString(st.LastError, 80))
+  }
+  if printed {
+    fmt.Println()
+  }
+}
@@ -17,3 +17,30 @@ type ClusterHealth struct {
 	InFlightFetch       int     `json:"number_of_in_flight_fetch"`
 	ActiveShardsPercent float64 `json:"active_shards_percent_as_number"`
 }
+
+// ClusterInfo 根端点 / 返回的集群信息
+type ClusterInfo struct {
+	Name        string `json:"name"`
+	ClusterName string `json:"cluster_name"`
+	ClusterUUID string `json:"cluster_uuid"`
+	Version     struct {
+		Number        string `json:"number"`
+		Distribution  string `json:"distribution"` // OpenSearch 返回 "opensearch"，ES 无此字段
+		BuildFlavor   string `json:"build_flavor"`
+		LuceneVersion string `json:"lucene_version"`
+	} `json:"version"`
+	Tagline string `json:"tagline"`
+}
+
+// HealthReport 健康报告（/_health_report，ES 8.7+）
+type HealthReport struct {
+	ClusterName string                           `json:"cluster_name"`
+	Status      string                           `json:"status"`
+	Indicators  map[string]HealthReportIndicator `json:"indicators"`
+}
+
+// HealthReportIndicator 健康报告指标
+type HealthReportIndicator struct {
+	Status  string `json:"status"`
+	Symptom string `json:"symptom"`
+}
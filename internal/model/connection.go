@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// EndpointStatus 单个 ES 节点的连接状态
+type EndpointStatus struct {
+	URL           string
+	Alive         bool
+	Requests      int64     // 累计请求数
+	Errors        int64     // 累计失败数
+	LastError     string    // 最近一次错误
+	LastErrorAt   time.Time // 最近一次错误时间
+	LastSuccessAt time.Time // 最近一次成功时间
+}
@@ -6,8 +6,9 @@ type EnhancedMetrics struct {
 	ClusterStats ClusterStats
 	
 	// 节点级别
-	NodeThreadPools  map[string]ThreadPoolStats
-	NodeCircuitBreakers map[string]CircuitBreakerStats
+	NodeThreadPools  map[string]map[string]ThreadPoolStats        // 节点 ID -> 线程池名称 -> 统计
+	NodeCircuitBreakers map[string]map[string]CircuitBreakerStats // 节点 ID -> 断路器名称 -> 统计
+	NodeOldGC        map[string]int64                            // 节点 ID -> 本周期 Full GC 次数（首次采集的节点没有）
 	
 	// 索引级别
 	SlowLogs       []SlowLog
@@ -36,32 +37,56 @@ type ClusterStats struct {
 	// 段统计
 	TotalSegments        int     // 总段数
 	SegmentMemoryMB      float64 // 段内存占用
+	MergesCurrent        int     // 进行中的段合并数
+	MergingMB            float64 // 进行中的段合并大小
 	
 	// 缓存统计
 	FieldDataMemoryMB    float64 // FieldData 内存
 	QueryCacheMemoryMB   float64 // 查询缓存内存
 	RequestCacheMemoryMB float64 // 请求缓存内存
+	FieldDataEvictions   int64   // FieldData 累计驱逐次数
+	QueryCacheEvictions  int64   // 查询缓存累计驱逐次数
+	QueryCacheHitRatio   float64 // 查询缓存命中率（%），没有查询过缓存时为 -1
+	RequestCacheHitRatio float64 // 请求缓存命中率（%），没有查询过缓存时为 -1
+	
+	// 事务日志
+	TranslogOperations    int64   // 事务日志操作数
+	TranslogSizeMB        float64 // 事务日志大小
+	TranslogUncommittedMB float64 // 未提交（未 flush）的事务日志大小
 }
 
 // ThreadPoolStats 线程池统计
 type ThreadPoolStats struct {
+	NodeName   string
 	PoolName   string
 	Active     int   // 活跃线程
 	Queue      int   // 队列中任务
 	QueueSize  int   // 队列大小
-	Rejected   int64 // 拒绝数
+	Rejected   int64 // 累计拒绝数
 	Completed  int64 // 完成数
 	Threads    int   // 线程数
+	Largest    int   // 历史最大线程数
+	
+	// 本周期变化（首次采集时 RateValid 为 false）
+	RejectedDelta  int64   // 本周期拒绝数
+	RejectedPerSec float64 // 每秒拒绝数
+	RateValid      bool
 }
 
 // CircuitBreakerStats 断路器统计
 type CircuitBreakerStats struct {
+	NodeName      string
+	NodeRoles     []string // 节点角色（按角色选择使用率阈值）
 	Name          string
 	LimitSizeMB   float64 // 限制大小
 	EstimatedMB   float64 // 估计使用
 	Overhead      float64 // 开销倍数
-	Tripped       int64   // 触发次数
+	Tripped       int64   // 累计触发次数
 	UsedPercent   float64 // 使用百分比
+	
+	// 本周期变化（首次采集时 RateValid 为 false）
+	TrippedDelta  int64   // 本周期触发次数
+	RateValid     bool
 }
 
 // SlowLog 慢查询日志
@@ -92,4 +117,10 @@ type HealthIssue struct {
 	Threshold   interface{}
 	Timestamp   int64
 	Suggestion  string // 修复建议
+	
+	// 跨周期跟踪（由问题跟踪器维护）
+	Key         string // 问题标识：来源/对象/类型，如 node/node-1/jvm.heap
+	FirstSeen   int64  // 首次发现时间（Unix 秒）
+	LastSeen    int64  // 最近一次发现时间（Unix 秒）
+	Resolved    bool   // 本周期已不再出现（保留一段时间后移除）
 }
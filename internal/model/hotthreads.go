@@ -0,0 +1,51 @@
+package model
+
+// NodeHotThreads 单个节点的一次热点线程采样（解析自 _nodes/hot_threads 文本响应）
+type NodeHotThreads struct {
+	NodeName string
+	NodeID   string
+	Threads  []HotThread
+}
+
+// HotThread 热点线程及其调用栈
+type HotThread struct {
+	Name       string  // 线程名，如 elasticsearch[node-1][write][T#3]
+	CPUPercent float64 // 采样间隔内占用单核 CPU 的百分比
+	Stacks     []HotThreadStack
+}
+
+// HotThreadStack 多次快照中相同的调用栈
+type HotThreadStack struct {
+	Snapshots int      // 出现该调用栈的快照数
+	Total     int      // 快照总数
+	Frames    []string // 栈帧（栈顶在前）
+}
+
+// HotThreadsProfile 多次热点线程采样的聚合结果
+type HotThreadsProfile struct {
+	Samples        int   // 聚合的采样次数
+	IntervalMillis int64 // 单次采样的 CPU 统计间隔（毫秒）
+	Nodes          []NodeHotProfile
+}
+
+// NodeHotProfile 单个节点的热点栈帧排名
+type NodeHotProfile struct {
+	Node        string
+	TotalMillis float64       // 热点线程 CPU 时间合计（毫秒）
+	Frames      []HotFrame    // 按自身耗时降序
+	Stacks      []FoldedStack // 折叠调用栈（按耗时降序）
+}
+
+// HotFrame 栈帧的 CPU 耗时
+type HotFrame struct {
+	Frame       string   // 类名.方法名（不含模块前缀和行号）
+	SelfMillis  float64  // 位于栈顶的 CPU 时间
+	TotalMillis float64  // 出现在调用栈中的 CPU 时间（含下层调用）
+	Pools       []string // 出现该栈帧的线程池
+}
+
+// FoldedStack 折叠格式的调用栈（节点;线程池;根帧;...;栈顶帧）
+type FoldedStack struct {
+	Stack  string
+	Millis float64
+}
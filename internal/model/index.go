@@ -33,6 +33,91 @@ type IndexShardStats struct {
 		QueryTimeInMillis int64 `json:"query_time_in_millis"`
 		QueryCurrent      int   `json:"query_current"`
 	} `json:"search"`
+	Segments     SegmentStats      `json:"segments"`
+	Translog     TranslogStats     `json:"translog"`
+	Merges       MergeStats        `json:"merges"`
+	Refresh      RefreshStats      `json:"refresh"`
+	Flush        FlushStats        `json:"flush"`
+	FieldData    FieldDataStats    `json:"fielddata"`
+	QueryCache   QueryCacheStats   `json:"query_cache"`
+	RequestCache RequestCacheStats `json:"request_cache"`
+}
+
+// SegmentStats 段统计（memory_in_bytes 在 ES 8 中已不再统计，始终为 0）
+type SegmentStats struct {
+	Count         int   `json:"count"`
+	MemoryInBytes int64 `json:"memory_in_bytes"`
+}
+
+// TranslogStats 事务日志统计
+type TranslogStats struct {
+	Operations             int64 `json:"operations"`
+	SizeInBytes            int64 `json:"size_in_bytes"`
+	UncommittedOperations  int64 `json:"uncommitted_operations"`
+	UncommittedSizeInBytes int64 `json:"uncommitted_size_in_bytes"`
+}
+
+// MergeStats 段合并统计
+type MergeStats struct {
+	Current            int   `json:"current"`
+	CurrentDocs        int   `json:"current_docs"`
+	CurrentSizeInBytes int64 `json:"current_size_in_bytes"`
+	Total              int   `json:"total"`
+	TotalTimeInMillis  int64 `json:"total_time_in_millis"`
+	TotalDocs          int   `json:"total_docs"`
+	TotalSizeInBytes   int64 `json:"total_size_in_bytes"`
+}
+
+// RefreshStats 刷新统计
+type RefreshStats struct {
+	Total             int   `json:"total"`
+	TotalTimeInMillis int64 `json:"total_time_in_millis"`
+}
+
+// FlushStats 刷盘统计
+type FlushStats struct {
+	Total             int   `json:"total"`
+	TotalTimeInMillis int64 `json:"total_time_in_millis"`
+}
+
+// FieldDataStats fielddata 缓存统计
+type FieldDataStats struct {
+	MemorySizeInBytes int64 `json:"memory_size_in_bytes"`
+	Evictions         int64 `json:"evictions"`
+}
+
+// QueryCacheStats 查询缓存（节点级 filter 缓存）统计
+type QueryCacheStats struct {
+	MemorySizeInBytes int64 `json:"memory_size_in_bytes"`
+	HitCount          int64 `json:"hit_count"`
+	MissCount         int64 `json:"miss_count"`
+	Evictions         int64 `json:"evictions"`
+}
+
+// HitRatio 命中率（%），没有查询过缓存时返回 -1
+func (s QueryCacheStats) HitRatio() float64 {
+	return hitRatio(s.HitCount, s.MissCount)
+}
+
+// RequestCacheStats 请求缓存（分片级聚合结果缓存）统计
+type RequestCacheStats struct {
+	MemorySizeInBytes int64 `json:"memory_size_in_bytes"`
+	HitCount          int64 `json:"hit_count"`
+	MissCount         int64 `json:"miss_count"`
+	Evictions         int64 `json:"evictions"`
+}
+
+// HitRatio 命中率（%），没有查询过缓存时返回 -1
+func (s RequestCacheStats) HitRatio() float64 {
+	return hitRatio(s.HitCount, s.MissCount)
+}
+
+// hitRatio 按命中数和未命中数计算命中率
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return -1
+	}
+	return float64(hits) / float64(hits+misses) * 100
 }
 
 // IndexInfo 索引信息
@@ -0,0 +1,153 @@
+package model
+
+// ILMExplain /<index>/_ilm/explain 响应
+type ILMExplain struct {
+	Indices map[string]ILMIndex `json:"indices"`
+}
+
+// ILMIndex 索引的 ILM 执行状态（未被 ILM 管理的索引只有 index 和 managed）
+type ILMIndex struct {
+	Index                   string             `json:"index"`
+	Managed                 bool               `json:"managed"`
+	Policy                  string             `json:"policy"`
+	IndexCreationDateMillis int64              `json:"index_creation_date_millis"` // ES 7.9+，此前只有 lifecycle_date_millis
+	LifecycleDateMillis     int64              `json:"lifecycle_date_millis"`      // 滚动后为滚动时间
+	Phase                   string             `json:"phase"`
+	PhaseTimeMillis         int64              `json:"phase_time_millis"`
+	Action                  string             `json:"action"`
+	ActionTimeMillis        int64              `json:"action_time_millis"`
+	Step                    string             `json:"step"` // 出错时为 ERROR
+	StepTimeMillis          int64              `json:"step_time_millis"`
+	FailedStep              string             `json:"failed_step"`
+	FailedStepRetryCount    int                `json:"failed_step_retry_count"`
+	IsAutoRetryableError    bool               `json:"is_auto_retryable_error"`
+	StepInfo                *ILMStepInfo       `json:"step_info"`
+	PhaseExecution          *ILMPhaseExecution `json:"phase_execution"`
+}
+
+// ILMStepInfo 步骤附加信息：出错时为异常类型和原因，等待时为等待说明
+type ILMStepInfo struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// ILMPhaseExecution 当前阶段执行的策略定义（只解析滚动条件）
+type ILMPhaseExecution struct {
+	Policy          string `json:"policy"`
+	Version         int64  `json:"version"`
+	PhaseDefinition struct {
+		MinAge  string `json:"min_age"`
+		Actions struct {
+			Rollover *RolloverConditions `json:"rollover"`
+		} `json:"actions"`
+	} `json:"phase_definition"`
+}
+
+// RolloverConditions 滚动条件：满足全部 min_* 条件且任一 max_* 条件时滚动
+// 时间和大小为 ES 单位字符串（如 30d、50gb），未设置的条件为空值
+type RolloverConditions struct {
+	MaxAge              string `json:"max_age"`
+	MaxSize             string `json:"max_size"`
+	MaxPrimaryShardSize string `json:"max_primary_shard_size"` // ES 7.13+
+	MaxDocs             int64  `json:"max_docs"`
+	MaxPrimaryShardDocs int64  `json:"max_primary_shard_docs"` // ES 8.2+
+	MinAge              string `json:"min_age"`                // ES 8.4+
+	MinSize             string `json:"min_size"`
+	MinPrimaryShardSize string `json:"min_primary_shard_size"`
+	MinDocs             int64  `json:"min_docs"`
+	MinPrimaryShardDocs int64  `json:"min_primary_shard_docs"`
+}
+
+// DataStreamLifecycleExplain /<index>/_lifecycle/explain 响应（ES 8.11+）
+type DataStreamLifecycleExplain struct {
+	Indices map[string]DataStreamLifecycleIndex `json:"indices"`
+}
+
+// DataStreamLifecycleIndex 数据流后备索引的生命周期状态
+type DataStreamLifecycleIndex struct {
+	Index                   string `json:"index"`
+	ManagedByLifecycle      bool   `json:"managed_by_lifecycle"`
+	IndexCreationDateMillis int64  `json:"index_creation_date_millis"`
+	RolloverDateMillis      int64  `json:"rollover_date_millis"` // 写索引尚未滚动时为 0
+	Lifecycle               struct {
+		Enabled       bool   `json:"enabled"`
+		DataRetention string `json:"data_retention"`
+	} `json:"lifecycle"`
+	Error string `json:"error"` // 最近一次执行失败的原因（JSON 字符串）
+}
+
+// ISMExplain OpenSearch /_plugins/_ism/explain/<index> 响应（键为索引名，另有 total_managed_indices 计数）
+type ISMExplain map[string]ISMIndex
+
+// ISMIndex 索引的 ISM 执行状态（未被 ISM 管理的索引没有 policy_id）
+type ISMIndex struct {
+	Index             string     `json:"index"`
+	PolicyID          string     `json:"policy_id"`
+	Enabled           bool       `json:"enabled"`
+	IndexCreationDate int64      `json:"index_creation_date"`
+	State             *ISMStage  `json:"state"`
+	Action            *ISMAction `json:"action"`
+	Step              *ISMStep   `json:"step"`
+	Info              *struct {
+		Message string `json:"message"`
+		Cause   string `json:"cause"`
+	} `json:"info"`
+}
+
+// ISMStage ISM 状态（对应 ILM 阶段）
+type ISMStage struct {
+	Name      string `json:"name"`
+	StartTime int64  `json:"start_time"`
+}
+
+// ISMAction ISM 动作及其重试情况
+type ISMAction struct {
+	Name            string `json:"name"`
+	StartTime       int64  `json:"start_time"`
+	Failed          bool   `json:"failed"`
+	ConsumedRetries int    `json:"consumed_retries"`
+}
+
+// ISMStep ISM 步骤，step_status 为 starting、condition_not_met、completed 或 failed
+type ISMStep struct {
+	Name       string `json:"name"`
+	StartTime  int64  `json:"start_time"`
+	StepStatus string `json:"step_status"`
+}
+
+// 生命周期问题类型
+const (
+	LifecycleError           = "error"            // 步骤执行失败（ILM ERROR 步骤或数据流生命周期报错）
+	LifecycleStuck           = "stuck"            // 长时间停留在同一步骤
+	LifecycleRolloverOverdue = "rollover_overdue" // 已满足滚动条件但仍未滚动
+)
+
+// LifecycleOverview 生命周期概览
+type LifecycleOverview struct {
+	ILMManaged  int               // ILM 管理的索引数
+	DSLManaged  int               // 数据流生命周期管理的索引数（ES 8.11+）
+	PhaseCounts map[string]int    // ILM 各阶段的索引数
+	Errors      int               // 执行出错的索引数
+	Stuck       int               // 停留过久的索引数
+	Overdue     int               // 滚动逾期的索引数
+	Indices     []LifecycleStatus // 有问题的索引（出错、滚动逾期、停留过久的顺序，只保留前若干个）
+	DSLErr      error             // 获取数据流生命周期失败的原因（不影响 ILM 状态）
+}
+
+// LifecycleStatus 生命周期异常的索引
+type LifecycleStatus struct {
+	Index       string
+	Managed     string // ilm 或 dsl
+	Policy      string
+	Problem     string // LifecycleError、LifecycleStuck 或 LifecycleRolloverOverdue
+	Phase       string
+	Action      string
+	Step        string
+	FailedStep  string
+	RetryCount  int
+	Reason      string  // 错误原因、等待说明或已满足的滚动条件
+	PhaseMillis int64   // 在当前阶段的时间
+	StepMillis  int64   // 在当前步骤的时间
+	Overdue     float64 // 滚动逾期时已达到条件的倍数（如 1.5 表示超过 max_age 50%）
+}
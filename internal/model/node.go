@@ -19,6 +19,15 @@ type NodeStat struct {
 	Transport Transport `json:"transport"`
 	HTTP      HTTP      `json:"http"`
 	Indices   Indices   `json:"indices"`
+
+	// 写入压力（ES 7.9+，旧版本为空）
+	IndexingPressure *IndexingPressure `json:"indexing_pressure,omitempty"`
+
+	// 线程池（键为线程池名称：search、write、get、management 等）
+	ThreadPool map[string]ThreadPool `json:"thread_pool"`
+
+	// 断路器（键为断路器名称：parent、fielddata、request 等）
+	Breakers map[string]Breaker `json:"breakers"`
 }
 
 // JVMStats JVM 统计
@@ -165,21 +174,45 @@ type Indices struct {
 		ScrollTimeInMillis int64 `json:"scroll_time_in_millis"`
 		ScrollCurrent      int   `json:"scroll_current"`
 	} `json:"search"`
-	Merges struct {
-		Current            int   `json:"current"`
-		CurrentDocs        int   `json:"current_docs"`
-		CurrentSizeInBytes int64 `json:"current_size_in_bytes"`
-		Total              int   `json:"total"`
-		TotalTimeInMillis  int64 `json:"total_time_in_millis"`
-		TotalDocs          int   `json:"total_docs"`
-		TotalSizeInBytes   int64 `json:"total_size_in_bytes"`
-	} `json:"merges"`
-	Refresh struct {
-		Total             int   `json:"total"`
-		TotalTimeInMillis int64 `json:"total_time_in_millis"`
-	} `json:"refresh"`
-	Flush struct {
-		Total             int   `json:"total"`
-		TotalTimeInMillis int64 `json:"total_time_in_millis"`
-	} `json:"flush"`
+	Segments     SegmentStats      `json:"segments"`
+	Translog     TranslogStats     `json:"translog"`
+	Merges       MergeStats        `json:"merges"`
+	Refresh      RefreshStats      `json:"refresh"`
+	Flush        FlushStats        `json:"flush"`
+	FieldData    FieldDataStats    `json:"fielddata"`
+	QueryCache   QueryCacheStats   `json:"query_cache"`
+	RequestCache RequestCacheStats `json:"request_cache"`
+}
+
+// IndexingPressure 写入压力统计
+type IndexingPressure struct {
+	Memory struct {
+		Current struct {
+			AllInBytes int64 `json:"all_in_bytes"`
+		} `json:"current"`
+		Total struct {
+			CoordinatingRejections int64 `json:"coordinating_rejections"`
+			PrimaryRejections      int64 `json:"primary_rejections"`
+			ReplicaRejections      int64 `json:"replica_rejections"`
+		} `json:"total"`
+		LimitInBytes int64 `json:"limit_in_bytes"`
+	} `json:"memory"`
+}
+
+// Breaker 断路器统计（tripped 为启动以来的累计触发次数）
+type Breaker struct {
+	LimitSizeInBytes     int64   `json:"limit_size_in_bytes"`
+	EstimatedSizeInBytes int64   `json:"estimated_size_in_bytes"`
+	Overhead             float64 `json:"overhead"`
+	Tripped              int64   `json:"tripped"`
+}
+
+// ThreadPool 线程池统计（rejected、completed 为启动以来的累计值）
+type ThreadPool struct {
+	Threads   int   `json:"threads"`
+	Queue     int   `json:"queue"`
+	Active    int   `json:"active"`
+	Rejected  int64 `json:"rejected"`
+	Largest   int   `json:"largest"`
+	Completed int64 `json:"completed"`
 }
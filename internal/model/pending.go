@@ -0,0 +1,23 @@
+package model
+
+// PendingTasks /_cluster/pending_tasks 响应
+type PendingTasks struct {
+	Tasks []PendingTask `json:"tasks"`
+}
+
+// PendingTask 主节点队列中的集群状态更新任务
+type PendingTask struct {
+	InsertOrder       int64  `json:"insert_order"`
+	Priority          string `json:"priority"` // IMMEDIATE、URGENT、HIGH、NORMAL、LOW、LANGUID
+	Source            string `json:"source"`
+	Executing         bool   `json:"executing"`
+	TimeInQueueMillis int64  `json:"time_in_queue_millis"`
+}
+
+// PendingTaskOverview 待处理任务概览
+type PendingTaskOverview struct {
+	Tasks        []PendingTask // 按排队时间降序
+	Executing    int           // 正在执行的任务数
+	OldestMillis int64         // 最久排队时间（毫秒）
+	History      []int         // 最近若干周期的队列深度（从旧到新）
+}
@@ -0,0 +1,82 @@
+package model
+
+// ShardInfo _cat/shards 返回的分片信息（format=json&bytes=b，数值均为字符串）
+type ShardInfo struct {
+	Index             string `json:"index"`
+	Shard             string `json:"shard"`
+	PriRep            string `json:"prirep"` // p 主分片 / r 副本
+	State             string `json:"state"`  // STARTED、RELOCATING、INITIALIZING、UNASSIGNED
+	Docs              string `json:"docs"`
+	Store             string `json:"store"`
+	Node              string `json:"node"` // 迁移中为 "源节点 -> 目标IP 目标ID 目标节点"
+	UnassignedReason  string `json:"unassigned.reason"`
+	UnassignedAt      string `json:"unassigned.at"`
+	UnassignedDetails string `json:"unassigned.details"`
+}
+
+// Primary 是否为主分片
+func (s ShardInfo) Primary() bool {
+	return s.PriRep == "p"
+}
+
+// AllocationExplain _cluster/allocation/explain 响应（只保留诊断所需字段）
+type AllocationExplain struct {
+	Index          string `json:"index"`
+	Shard          int    `json:"shard"`
+	Primary        bool   `json:"primary"`
+	CurrentState   string `json:"current_state"`
+	UnassignedInfo struct {
+		Reason               string `json:"reason"`
+		At                   string `json:"at"`
+		LastAllocationStatus string `json:"last_allocation_status"`
+		Details              string `json:"details"`
+	} `json:"unassigned_info"`
+	CanAllocate             string                   `json:"can_allocate"` // yes、no、throttled、no_valid_shard_copy 等
+	AllocateExplanation     string                   `json:"allocate_explanation"`
+	NodeAllocationDecisions []NodeAllocationDecision `json:"node_allocation_decisions"`
+}
+
+// NodeAllocationDecision 分片在某个节点上的分配决策
+type NodeAllocationDecision struct {
+	NodeName     string              `json:"node_name"`
+	NodeDecision string              `json:"node_decision"` // yes、no、throttled、worse_balance
+	Deciders     []AllocationDecider `json:"deciders"`
+}
+
+// AllocationDecider 单个分配决策器的结论
+type AllocationDecider struct {
+	Decider     string `json:"decider"`
+	Decision    string `json:"decision"`
+	Explanation string `json:"explanation"`
+}
+
+// ShardOverview 分片概览：非 STARTED 分片及未分配原因诊断
+type ShardOverview struct {
+	Filter       string // 索引通配符过滤（空表示全部索引）
+	Total        int    // 匹配过滤条件的分片总数
+	Started      int
+	Relocating   int
+	Initializing int
+	Unassigned   int
+	Shards       []ShardInfo // 非 STARTED 分片（未分配主分片优先）
+
+	Allocations []ShardAllocation // 未分配分片的分配诊断（前几个）
+	ExplainErr  error             // 分配诊断失败原因（不影响分片列表）
+}
+
+// ShardAllocation 单个未分配分片的分配诊断摘要
+type ShardAllocation struct {
+	Index       string
+	Shard       int
+	Primary     bool
+	CanAllocate string
+	Explanation string         // 分配器的总体结论
+	Deciders    []DeciderCount // 拒绝分配的决策器（按涉及节点数降序）
+}
+
+// DeciderCount 决策器在多少个节点上拒绝了分配
+type DeciderCount struct {
+	Decider     string
+	Nodes       int
+	Explanation string // 第一条说明（各节点通常相同）
+}
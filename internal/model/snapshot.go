@@ -0,0 +1,119 @@
+package model
+
+// SLMPolicies /_slm/policy 响应（键为策略 ID）
+type SLMPolicies map[string]SLMPolicy
+
+// SLMPolicy 快照生命周期策略及其执行状态
+type SLMPolicy struct {
+	Version            int64 `json:"version"`
+	ModifiedDateMillis int64 `json:"modified_date_millis"`
+	Policy             struct {
+		Name       string `json:"name"`     // 快照名称模板，如 <nightly-snap-{now/d}>
+		Schedule   string `json:"schedule"` // cron 表达式（UTC），如 0 30 1 * * ?
+		Repository string `json:"repository"`
+	} `json:"policy"`
+	LastSuccess         *SLMInvocation `json:"last_success"`
+	LastFailure         *SLMInvocation `json:"last_failure"`
+	NextExecutionMillis int64          `json:"next_execution_millis"`
+	InProgress          *SLMInProgress `json:"in_progress"`
+	Stats               SLMPolicyStats `json:"stats"`
+}
+
+// SLMInvocation 策略最近一次成功或失败的执行
+type SLMInvocation struct {
+	SnapshotName string `json:"snapshot_name"`
+	StartTime    int64  `json:"start_time"` // ES 8.x 起提供
+	Time         int64  `json:"time"`       // 完成时间
+	Details      string `json:"details"`    // 失败原因（JSON 字符串）
+}
+
+// SLMInProgress 策略正在执行的快照
+type SLMInProgress struct {
+	Name            string `json:"name"`
+	UUID            string `json:"uuid"`
+	State           string `json:"state"`
+	StartTimeMillis int64  `json:"start_time_millis"`
+}
+
+// SLMPolicyStats 策略的累计执行统计
+type SLMPolicyStats struct {
+	SnapshotsTaken           int64 `json:"snapshots_taken"`
+	SnapshotsFailed          int64 `json:"snapshots_failed"`
+	SnapshotsDeleted         int64 `json:"snapshots_deleted"`
+	SnapshotDeletionFailures int64 `json:"snapshot_deletion_failures"`
+}
+
+// SLMStats /_slm/stats 响应（全部策略的累计统计和保留策略执行情况）
+type SLMStats struct {
+	RetentionRuns                 int64 `json:"retention_runs"`
+	RetentionFailed               int64 `json:"retention_failed"`
+	RetentionTimedOut             int64 `json:"retention_timed_out"`
+	TotalSnapshotsTaken           int64 `json:"total_snapshots_taken"`
+	TotalSnapshotsFailed          int64 `json:"total_snapshots_failed"`
+	TotalSnapshotsDeleted         int64 `json:"total_snapshots_deleted"`
+	TotalSnapshotDeletionFailures int64 `json:"total_snapshot_deletion_failures"`
+}
+
+// SnapshotStatus /_snapshot/_status 响应（所有仓库中正在执行的快照）
+type SnapshotStatus struct {
+	Snapshots []SnapshotProgress `json:"snapshots"`
+}
+
+// SnapshotProgress 正在执行的快照进度
+type SnapshotProgress struct {
+	Snapshot    string `json:"snapshot"`
+	Repository  string `json:"repository"`
+	UUID        string `json:"uuid"`
+	State       string `json:"state"` // STARTED、SUCCESS、FAILED 等
+	ShardsStats struct {
+		Initializing int `json:"initializing"`
+		Started      int `json:"started"`
+		Finalizing   int `json:"finalizing"`
+		Done         int `json:"done"`
+		Failed       int `json:"failed"`
+		Total        int `json:"total"`
+	} `json:"shards_stats"`
+	Stats struct {
+		Processed struct {
+			FileCount   int64 `json:"file_count"`
+			SizeInBytes int64 `json:"size_in_bytes"`
+		} `json:"processed"`
+		Total struct {
+			FileCount   int64 `json:"file_count"`
+			SizeInBytes int64 `json:"size_in_bytes"`
+		} `json:"total"`
+		StartTimeInMillis int64 `json:"start_time_in_millis"`
+		TimeInMillis      int64 `json:"time_in_millis"`
+	} `json:"stats"`
+}
+
+// SnapshotOverview 快照概览
+type SnapshotOverview struct {
+	Policies []SnapshotPolicyStatus // 按策略 ID 排序
+	Running  []SnapshotProgress     // 正在执行的快照
+	Stats    SLMStats
+	Overdue  int // 超过成功窗口的策略数
+}
+
+// SnapshotPolicyStatus 快照策略状态
+type SnapshotPolicyStatus struct {
+	Policy     string
+	Repository string
+	Schedule   string
+
+	LastSuccess   string // 最近一次成功的快照名称
+	LastSuccessAt int64  // 最近一次成功的完成时间（毫秒时间戳，0 表示从未成功）
+	LastFailure   string // 最近一次失败的快照名称
+	LastFailureAt int64
+	FailureReason string
+	NextRunAt     int64  // 下一次计划执行时间
+	InProgress    string // 正在执行的快照名称
+
+	WindowMillis int64 // 成功窗口（0 表示无法推算）
+	DeadlineAt   int64 // 应当出现下一次成功的最晚时间（0 表示无法推算）
+	Overdue      bool  // 超过成功窗口仍未成功
+	Configured   bool  // 成功窗口来自配置文件（否则按调度计划推算）
+
+	Taken  int64
+	Failed int64
+}
@@ -0,0 +1,50 @@
+package model
+
+// Tasks /_tasks?detailed&group_by=parents 响应（键为 节点ID:任务号）
+type Tasks struct {
+	Tasks map[string]TaskGroup `json:"tasks"`
+}
+
+// TaskGroup 顶层任务及其直接子任务
+type TaskGroup struct {
+	Task
+	Children []TaskChild `json:"children"`
+}
+
+// TaskChild 子任务（只用于统计，不解析详细信息）
+type TaskChild struct {
+	Node   string `json:"node"`
+	ID     int64  `json:"id"`
+	Action string `json:"action"`
+}
+
+// Task 集群中正在运行的任务
+type Task struct {
+	Node               string `json:"node"` // 节点 ID
+	ID                 int64  `json:"id"`
+	Type               string `json:"type"`
+	Action             string `json:"action"` // 如 indices:data/write/update/byquery
+	Description        string `json:"description"`
+	StartTimeInMillis  int64  `json:"start_time_in_millis"`
+	RunningTimeInNanos int64  `json:"running_time_in_nanos"`
+	Cancellable        bool   `json:"cancellable"`
+	Cancelled          bool   `json:"cancelled"`
+}
+
+// TaskOverview 长时间运行任务概览
+type TaskOverview struct {
+	Total int           // 顶层任务总数（不含监控自身的请求）
+	Tasks []RunningTask // 运行时间最长的顶层任务（按运行时间降序）
+}
+
+// RunningTask 运行中的顶层任务
+type RunningTask struct {
+	TaskID        string // 节点ID:任务号（取消时使用）
+	NodeName      string // 节点名称（无法解析时为节点 ID）
+	Action        string
+	Description   string
+	RunningMillis int64
+	Cancellable   bool
+	Cancelled     bool
+	Children      int // 直接子任务数（如 reindex 的各分片 bulk 请求）
+}
@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/collector"
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+	"github.com/Y-vQv-Y/es-monitor/internal/display"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// SampleHotThreads 按需采样热点线程：每隔 cfg.Interval 采样一次，共 cfg.HotThreadsWindow 次，
+// 显示聚合后的栈帧排名并按配置导出折叠调用栈（只读操作，不进入监控界面）
+func SampleHotThreads(ctx context.Context, client *client.ElasticsearchClient, cfg *config.Config) error {
+	samples := max(cfg.HotThreadsWindow, 1)
+	hot := collector.NewHotThreadsCollector(client, cfg.HotThreadsNodes, samples)
+	terminal := display.NewTerminal(cfg.Thresholds)
+
+	var profile *model.HotThreadsProfile
+	for i := 0; i < samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(cfg.Interval):
+			}
+		}
+		fmt.Printf("正在采样热点线程 (%d/%d) ...\n", i+1, samples)
+
+		var err error
+		profile, err = hot.Sample(ctx)
+		if err != nil {
+			return fmt.Errorf("采样热点线程失败: %w", err)
+		}
+	}
+
+	fmt.Println()
+	terminal.DisplayHotThreads(profile)
+	if cfg.HotThreadsFolded != "" {
+		if err := writeFoldedFile(cfg.HotThreadsFolded, profile); err != nil {
+			return err
+		}
+		fmt.Printf("折叠调用栈已导出到 %s\n", cfg.HotThreadsFolded)
+	}
+	return nil
+}
+
+// writeFoldedFile 导出折叠调用栈（先写临时文件再替换，火焰图工具不会读到写了一半的文件）
+func writeFoldedFile(path string, profile *model.HotThreadsProfile) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("导出折叠调用栈失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := collector.WriteFolded(tmp, profile); err != nil {
+		tmp.Close()
+		return fmt.Errorf("导出折叠调用栈失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("导出折叠调用栈失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("导出折叠调用栈失败: %w", err)
+	}
+	return nil
+}
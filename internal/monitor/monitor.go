@@ -2,6 +2,8 @@ package monitor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -14,41 +16,79 @@ import (
 
 // Monitor 监控器
 type Monitor struct {
-	client           *client.ElasticsearchClient
-	config           *config.Config
-	terminal         *display.Terminal
-	clusterCollector *collector.ClusterCollector
-	nodeCollector    *collector.NodeCollector
-	indexCollector   *collector.IndexCollector
-	systemCollector  *collector.SystemCollector
-	prevNodeData     map[string]*display.PrevNodeMetrics
-	prevIndexData    map[string]*display.PrevIndexMetrics
-	ticker           *time.Ticker
-	stopChan         chan struct{}
-	wg               sync.WaitGroup
-	mu               sync.Mutex
+	client            *client.ElasticsearchClient
+	config            *config.Config
+	terminal          *display.Terminal
+	clusterCollector  *collector.ClusterCollector
+	nodeCollector     *collector.NodeCollector
+	indexCollector    *collector.IndexCollector
+	systemCollector   *collector.SystemCollector
+	enhancedCollector *collector.EnhancedCollector
+	shardCollector    *collector.ShardCollector
+	pendingCollector  *collector.PendingTaskCollector
+	taskCollector     *collector.TaskCollector
+	lifecycle         *collector.LifecycleCollector
+	snapshots         *collector.SnapshotCollector
+	hotThreads        *collector.HotThreadsCollector // 未开启热点线程采样时为空
+	prevNodeData      map[string]*display.PrevNodeMetrics
+	prevIndexData     map[string]*display.PrevIndexMetrics
+
+	// 上次成功采集的数据（本轮失败时显示为过期数据，而不是清空该区域）
+	lastHealth       *model.ClusterHealth
+	lastHealthAt     time.Time
+	lastNodeStats    *model.NodeStats
+	lastNodeStatsAt  time.Time
+	lastIndexList    []model.IndexInfo
+	lastIndexStats   *model.IndexStats
+	lastIndexStatsAt time.Time
+	lastHotThreads   *model.HotThreadsProfile
+	lastLifecycle    *model.LifecycleOverview
+	lastLifecycleAt  time.Time
+
+	cycles int // 已执行的采集周期数
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
 }
 
 // NewMonitor 创建监控器
 func NewMonitor(client *client.ElasticsearchClient, cfg *config.Config) *Monitor {
-	return &Monitor{
-		client:           client,
-		config:           cfg,
-		terminal:         display.NewTerminal(),
-		clusterCollector: collector.NewClusterCollector(client),
-		nodeCollector:    collector.NewNodeCollector(client),
-		indexCollector:   collector.NewIndexCollector(client),
-		systemCollector:  collector.NewSystemCollector(),
-		prevNodeData:     make(map[string]*display.PrevNodeMetrics),
-		prevIndexData:    make(map[string]*display.PrevIndexMetrics),
-		stopChan:         make(chan struct{}),
+	thresholds := cfg.Thresholds
+	if thresholds == nil {
+		thresholds = &config.DefaultThresholds
+	}
+	terminal := display.NewTerminal(thresholds)
+	terminal.SetClock(client.Now)
+
+	m := &Monitor{
+		client:            client,
+		config:            cfg,
+		terminal:          terminal,
+		clusterCollector:  collector.NewClusterCollector(client),
+		nodeCollector:     collector.NewNodeCollector(client),
+		indexCollector:    collector.NewIndexCollector(client),
+		systemCollector:   collector.NewSystemCollector(),
+		enhancedCollector: collector.NewEnhancedCollector(client, thresholds),
+		shardCollector:    collector.NewShardCollector(client, cfg.ShardFilter),
+		pendingCollector:  collector.NewPendingTaskCollector(client),
+		taskCollector:     collector.NewTaskCollector(client),
+		lifecycle:         collector.NewLifecycleCollector(client, thresholds),
+		snapshots:         collector.NewSnapshotCollector(client, cfg.SnapshotWindows),
+		prevNodeData:      make(map[string]*display.PrevNodeMetrics),
+		prevIndexData:     make(map[string]*display.PrevIndexMetrics),
+		stopChan:          make(chan struct{}),
+	}
+	if cfg.HotThreadsEvery > 0 {
+		m.hotThreads = collector.NewHotThreadsCollector(client, cfg.HotThreadsNodes, cfg.HotThreadsWindow)
 	}
+	return m
 }
 
 // Start 启动监控（生产环境安全）
 func (m *Monitor) Start(ctx context.Context) {
-	m.ticker = time.NewTicker(m.config.Interval)
-	defer m.ticker.Stop()
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
 
 	// 首次立即执行
 	m.collect(ctx)
@@ -60,7 +100,30 @@ func (m *Monitor) Start(ctx context.Context) {
 			return
 		case <-m.stopChan:
 			return
-		case <-m.ticker.C:
+		case <-ticker.C:
+			m.collect(ctx)
+		}
+	}
+}
+
+// StartStepping 以单步方式启动监控（回放单步模式）：不使用定时器，
+// 首次立即执行，之后每从 steps 收到一次信号，回放前进一个周期并采集一次
+func (m *Monitor) StartStepping(ctx context.Context, steps <-chan struct{}) {
+	m.collect(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stopChan:
+			return
+		case _, ok := <-steps:
+			if !ok {
+				return
+			}
+			if replayer := m.client.Replayer(); replayer != nil {
+				replayer.Step()
+			}
 			m.collect(ctx)
 		}
 	}
@@ -72,7 +135,44 @@ func (m *Monitor) Stop() {
 	m.wg.Wait()
 }
 
+// cycleResult 一个采集周期的结果（采集失败的项记录错误，未采集的项为空）
+type cycleResult struct {
+	sysMetrics *model.SystemMetrics
+	sysErr     error
+
+	health    *model.ClusterHealth
+	healthErr error
+	report    *model.HealthReport
+	reportErr error
+
+	nodeStats  *model.NodeStats
+	nodeErr    error
+	indexList  []model.IndexInfo
+	indexStats *model.IndexStats
+	indexErr   error
+
+	shards        *model.ShardOverview
+	shardErr      error
+	pending       *model.PendingTaskOverview
+	pendingErr    error
+	tasks         *model.TaskOverview
+	taskErr       error
+	lifecycle     *model.LifecycleOverview
+	lifecycleErr  error
+	snapshots     *model.SnapshotOverview
+	snapshotErr   error
+	hotThreadsErr error
+
+	input       collector.EnhancedInput
+	enhanced    *model.EnhancedMetrics
+	enhancedErr error
+}
+
+// systemSampleDelay 采集系统指标后等待的时间，避免 ES 请求影响本次网络流量统计
+var systemSampleDelay = 1500 * time.Millisecond
+
 // collect 采集并显示所有指标（只读操作）
+// 先完成全部采集再统一输出，健康问题面板依赖本周期的全部数据，需要显示在最上方
 func (m *Monitor) collect(ctx context.Context) {
 	m.wg.Add(1)
 	defer m.wg.Done()
@@ -80,72 +180,285 @@ func (m *Monitor) collect(ctx context.Context) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// 显示标题
-	m.terminal.DisplayHeader()
+	m.render(m.gather(ctx))
+}
+
+// gather 采集一个周期的全部指标（只读操作，不输出）
+func (m *Monitor) gather(ctx context.Context) *cycleResult {
+	r := &cycleResult{}
 
 	// ========================================
 	// 【改动1】第一步：先采集系统指标（此时无网络请求，流量统计准确）
+	// 回放模式：本机系统指标与录制内容无关，不再采集
 	// ========================================
-	sysMetrics, sysErr := m.systemCollector.Collect(ctx)
+	if m.client.Replayer() == nil {
+		r.sysMetrics, r.sysErr = m.systemCollector.Collect(ctx)
 
-	// ========================================
-	// 【改动2】延迟 1.5 秒，避免后续 ES 请求影响本次网络流量统计
-	// ========================================
-	time.Sleep(1500 * time.Millisecond)
+		// ========================================
+		// 【改动2】延迟 1.5 秒，避免后续 ES 请求影响本次网络流量统计
+		// ========================================
+		time.Sleep(systemSampleDelay)
+	}
 
 	// ========================================
 	// 【改动3】第二步：再采集 ES 指标（会产生大量 HTTP 流量）
 	// ========================================
 
 	// 1. 采集集群健康状态
-	health, err := m.clusterCollector.Collect(ctx)
-	if err != nil {
-		m.terminal.DisplayError("获取集群健康状态失败", err)
+	r.health, r.healthErr = m.clusterCollector.Collect(ctx)
+	if r.healthErr != nil && m.lastHealth == nil {
+		return r
+	}
+
+	// 健康报告（按版本能力决定是否支持）
+	r.report, r.reportErr = m.clusterCollector.CollectHealthReport(ctx)
+
+	// 2. 采集节点统计
+	r.nodeStats, r.nodeErr = m.nodeCollector.Collect(ctx)
+
+	// 3. 采集索引统计
+	r.indexList, r.indexErr = m.indexCollector.CollectList(ctx)
+	if r.indexErr == nil {
+		r.indexStats, r.indexErr = m.indexCollector.CollectStats(ctx)
+	}
+
+	// 4. 采集分片状态（只在存在非 STARTED 分片时请求，_cat/shards 在大集群上响应较大）
+	health := r.health
+	if r.healthErr == nil && health.UnassignedShards+health.InitializingShards+health.RelocatingShards > 0 {
+		r.shards, r.shardErr = m.shardCollector.Collect(ctx)
+	}
+
+	// 5. 采集主节点待处理任务（集群健康显示队列为空时不请求）
+	if r.healthErr == nil {
+		r.pending, r.pendingErr = m.pendingCollector.Collect(ctx, health)
+	}
+
+	// 6. 采集长时间运行的任务（节点统计成功时显示节点名称）
+	taskNodes := r.nodeStats
+	if r.nodeErr != nil {
+		taskNodes = nil
+	}
+	r.tasks, r.taskErr = m.taskCollector.Collect(ctx, taskNodes)
+
+	// 7. 采集索引生命周期（索引列表用于判断大小和文档数滚动条件）
+	// explain 响应与索引数成正比，刷新间隔内沿用上次成功采集的结果
+	if m.lastLifecycle != nil && m.client.Now().Sub(m.lastLifecycleAt) < m.config.LifecycleInterval {
+		r.lifecycle = m.lastLifecycle
+	} else {
+		lifecycleIndices := r.indexList
+		if r.indexErr != nil {
+			lifecycleIndices = nil
+		}
+		r.lifecycle, r.lifecycleErr = m.lifecycle.Collect(ctx, lifecycleIndices)
+		if r.lifecycleErr == nil {
+			m.lastLifecycle, m.lastLifecycleAt = r.lifecycle, m.client.Now()
+		}
+	}
+
+	// 8. 采集快照策略和正在执行的快照
+	r.snapshots, r.snapshotErr = m.snapshots.Collect(ctx)
+
+	// 9. 按周期采样热点线程（采样期间 ES 需要抓取线程栈，默认不开启）
+	if m.hotThreads != nil && m.cycles%m.config.HotThreadsEvery == 0 {
+		var profile *model.HotThreadsProfile
+		profile, r.hotThreadsErr = m.hotThreads.Sample(ctx)
+		if r.hotThreadsErr == nil {
+			m.lastHotThreads = profile
+			if m.config.HotThreadsFolded != "" {
+				r.hotThreadsErr = writeFoldedFile(m.config.HotThreadsFolded, profile)
+			}
+		}
+	}
+	m.cycles++
+
+	// 10. 增强分析：线程池、断路器和健康问题（只使用本周期成功采集的数据）
+	r.input = collector.EnhancedInput{NodeStats: r.nodeStats, Health: health, System: r.sysMetrics, Indices: r.indexList, Pending: r.pending, Tasks: r.tasks, Lifecycle: r.lifecycle, Snapshots: r.snapshots}
+	if r.nodeErr != nil {
+		r.input.NodeStats = nil
+	}
+	if r.indexErr == nil {
+		r.input.IndexStats = r.indexStats
+	}
+	if r.healthErr != nil {
+		r.input.Health = nil
+	}
+	r.enhanced, r.enhancedErr = m.enhancedCollector.Collect(ctx, r.input)
+	return r
+}
+
+// render 统一输出一个周期的结果，并保存成功采集的数据供下个周期使用
+func (m *Monitor) render(r *cycleResult) {
+	replayer := m.client.Replayer()
+	m.displayHeader(replayer)
+
+	if r.healthErr != nil && m.lastHealth == nil {
+		m.terminal.DisplayError("获取集群健康状态失败", r.healthErr)
 		m.terminal.DisplayFooter()
 		return
 	}
-	m.terminal.DisplayClusterHealth(health)
 
-	// 2. 显示系统指标（优先显示，最关心的指标）
-	if sysErr != nil {
-		m.terminal.DisplayError("获取系统指标失败", sysErr)
+	// 健康问题面板（按优先级排序，显示在最上方）
+	if r.enhancedErr != nil {
+		m.terminal.DisplayError("分析健康问题失败", r.enhancedErr)
 	} else {
-		// 显示完整的系统资源监控
-		m.terminal.DisplaySystemMetrics(sysMetrics)
-		m.terminal.DisplayDiskMetrics(&sysMetrics.Disk)
-		m.terminal.DisplayNetworkMetrics(&sysMetrics.Network)
+		m.terminal.DisplayHealthIssues(r.enhanced.HealthIssues)
 	}
 
-	// 3. 采集节点统计
-	nodeStats, err := m.nodeCollector.Collect(ctx)
-	if err != nil {
-		m.terminal.DisplayError("获取节点统计失败", err)
+	// 集群健康状态
+	if r.healthErr == nil {
+		m.lastHealth, m.lastHealthAt = r.health, m.client.Now()
+		m.terminal.DisplayClusterHealth(r.health)
 	} else {
-		m.terminal.DisplayNodeStats(nodeStats, m.prevNodeData)
-		m.updateNodePrevData(nodeStats)
+		m.terminal.DisplayStaleNotice("获取集群健康状态失败", r.healthErr, m.lastHealthAt)
+		m.terminal.DisplayClusterHealth(m.lastHealth)
+	}
+
+	var unsupported *client.UnsupportedError
+	switch {
+	case r.reportErr == nil:
+		m.terminal.DisplayHealthReport(r.report)
+	case errors.As(r.reportErr, &unsupported):
+		m.terminal.DisplayUnsupported("健康报告", r.reportErr)
+	default:
+		m.terminal.DisplayError("获取健康报告失败", r.reportErr)
+	}
+
+	// 分片状态（集群未全部分配时）
+	if r.shardErr != nil {
+		m.terminal.DisplayError("获取分片状态失败", r.shardErr)
+	} else if r.shards != nil {
+		m.terminal.DisplayShards(r.shards)
 	}
 
-	// 4. 采集索引统计
-	indexList, err := m.indexCollector.CollectList(ctx)
-	if err != nil {
-		m.terminal.DisplayError("获取索引列表失败", err)
+	// 待处理集群任务（队列非空或最近有积压时）
+	if r.pendingErr != nil {
+		m.terminal.DisplayError("获取待处理任务失败", r.pendingErr)
+	} else if r.pending != nil {
+		m.terminal.DisplayPendingTasks(r.pending)
+	}
+
+	// 长时间运行的任务（只显示任务 ID，不会取消）
+	if r.taskErr != nil {
+		m.terminal.DisplayError("获取任务列表失败", r.taskErr)
 	} else {
-		indexStats, err := m.indexCollector.CollectStats(ctx)
-		if err != nil {
-			m.terminal.DisplayError("获取索引统计失败", err)
-		} else {
-			m.terminal.DisplayIndexStats(indexList, indexStats, m.prevIndexData)
-			m.updateIndexPrevData(indexStats)
+		m.terminal.DisplayTasks(r.tasks)
+	}
+
+	// 系统指标（优先显示，最关心的指标）
+	if r.sysErr != nil {
+		m.terminal.DisplayError("获取系统指标失败", r.sysErr)
+	} else if r.sysMetrics != nil {
+		// 显示完整的系统资源监控
+		m.terminal.DisplaySystemMetrics(r.sysMetrics)
+		m.terminal.DisplayDiskMetrics(&r.sysMetrics.Disk)
+		m.terminal.DisplayNetworkMetrics(&r.sysMetrics.Network)
+	}
+
+	// 节点统计
+	if r.nodeErr == nil {
+		// Full GC 增量取自采集器，节点面板与健康问题保持一致
+		var oldGC map[string]int64
+		if r.enhancedErr == nil {
+			oldGC = r.enhanced.NodeOldGC
 		}
+		m.terminal.DisplayNodeStats(r.nodeStats, m.prevNodeData, oldGC)
+		m.updateNodePrevData(r.nodeStats)
+		m.lastNodeStats, m.lastNodeStatsAt = r.nodeStats, m.client.Now()
+	} else if m.lastNodeStats != nil {
+		m.terminal.DisplayStaleNotice("获取节点统计失败", r.nodeErr, m.lastNodeStatsAt)
+		m.terminal.DisplayNodeStats(m.lastNodeStats, nil, nil)
+	} else {
+		m.terminal.DisplayError("获取节点统计失败", r.nodeErr)
+	}
+
+	// 集群段、缓存和事务日志汇总（按本周期节点统计计算）
+	if r.enhancedErr == nil && r.input.NodeStats != nil {
+		m.terminal.DisplayClusterStats(r.enhanced.ClusterStats)
+	}
+
+	// 线程池和断路器（节点统计失败时为空，不显示）
+	if r.enhancedErr == nil {
+		m.terminal.DisplayThreadPools(r.enhanced.NodeThreadPools)
+		m.terminal.DisplayCircuitBreakers(r.enhanced.NodeCircuitBreakers)
+	}
+
+	// 热点线程（显示最近一次采样后的聚合结果）
+	if r.hotThreadsErr != nil {
+		m.terminal.DisplayError("采样热点线程失败", r.hotThreadsErr)
+	}
+	if m.lastHotThreads != nil {
+		m.terminal.DisplayHotThreads(m.lastHotThreads)
+	}
+
+	// 索引统计
+	if r.indexErr == nil {
+		m.terminal.DisplayIndexStats(r.indexList, r.indexStats, m.prevIndexData)
+		m.updateIndexPrevData(r.indexStats)
+		m.lastIndexList, m.lastIndexStats, m.lastIndexStatsAt = r.indexList, r.indexStats, m.client.Now()
+	} else if m.lastIndexStats != nil {
+		m.terminal.DisplayStaleNotice("获取索引统计失败", r.indexErr, m.lastIndexStatsAt)
+		m.terminal.DisplayIndexStats(m.lastIndexList, m.lastIndexStats, nil)
+	} else {
+		m.terminal.DisplayError("获取索引统计失败", r.indexErr)
+	}
+
+	// 索引生命周期（按版本能力决定是否支持）
+	switch {
+	case r.lifecycleErr == nil:
+		m.terminal.DisplayLifecycle(r.lifecycle)
+	case errors.As(r.lifecycleErr, &unsupported):
+		m.terminal.DisplayUnsupported("索引生命周期", r.lifecycleErr)
+	default:
+		m.terminal.DisplayError("获取索引生命周期失败", r.lifecycleErr)
+	}
+
+	// 快照生命周期（按版本能力决定是否支持）
+	switch {
+	case r.snapshotErr == nil:
+		m.terminal.DisplaySnapshots(r.snapshots)
+	case errors.As(r.snapshotErr, &unsupported):
+		m.terminal.DisplayUnsupported("快照生命周期", r.snapshotErr)
+	default:
+		m.terminal.DisplayError("获取快照状态失败", r.snapshotErr)
 	}
 
 	// 显示页脚
 	m.terminal.DisplayFooter()
 }
 
+// displayHeader 显示标题、端点状态和回放进度
+func (m *Monitor) displayHeader(replayer *client.Replayer) {
+	m.terminal.DisplayHeader(m.client.ActiveEndpoint())
+	m.terminal.DisplayEndpointErrors(m.client.EndpointStatuses())
+
+	// 被只读策略拒绝的请求（未配置审计日志时只在这里显示）
+	denials, dropped := m.client.TakeDenials()
+	for _, d := range denials {
+		m.terminal.DisplayError("只读策略拒绝请求", fmt.Errorf("%s %s (%s)", d.Method, d.Endpoint, d.Reason))
+	}
+	if dropped > 0 {
+		m.terminal.DisplayError("只读策略拒绝请求", fmt.Errorf("另有 %d 个请求被拒绝未显示", dropped))
+	}
+	if n, err := m.client.TakeAuditErrors(); n > 0 {
+		m.terminal.DisplayError("审计日志写入失败", fmt.Errorf("%d 次，最近一次: %w", n, err))
+	}
+	if n, err := m.client.TakeRecordErrors(); n > 0 {
+		m.terminal.DisplayError("录制失败", fmt.Errorf("%d 次，最近一次: %w", n, err))
+	}
+
+	// 回放模式：显示录制时间
+	if replayer != nil {
+		tick, total := replayer.Position()
+		if m.config.ReplaySpeed > 0 {
+			total = 0
+		}
+		m.terminal.DisplayReplayStatus(replayer.Now(), tick, total)
+	}
+}
+
 // updateNodePrevData 更新节点历史数据
 func (m *Monitor) updateNodePrevData(stats *model.NodeStats) {
-	now := time.Now()
+	now := m.client.Now()
 	for nodeID, node := range stats.Nodes {
 		m.prevNodeData[nodeID] = &display.PrevNodeMetrics{
 			IndexTotal: node.Indices.Indexing.IndexTotal,
@@ -157,7 +470,7 @@ func (m *Monitor) updateNodePrevData(stats *model.NodeStats) {
 
 // updateIndexPrevData 更新索引历史数据
 func (m *Monitor) updateIndexPrevData(stats *model.IndexStats) {
-	now := time.Now()
+	now := m.client.Now()
 	for indexName, indexStat := range stats.Indices {
 		m.prevIndexData[indexName] = &display.PrevIndexMetrics{
 			IndexTotal: indexStat.Total.Indexing.IndexTotal,
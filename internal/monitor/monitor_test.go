@@ -0,0 +1,708 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/client"
+	"github.com/Y-vQv-Y/es-monitor/internal/config"
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+	"github.com/Y-vQv-Y/es-monitor/internal/testing/fakees"
+)
+
+// harness 连接到模拟集群的监控器，每一步推进一个场景周期并采集一次
+type harness struct {
+	t   *testing.T
+	ctx context.Context
+	srv *fakees.Server
+	cl  *client.ElasticsearchClient
+	m   *Monitor
+}
+
+// newHarness 启动模拟集群并连接监控器，configure 在创建监控器之前修改配置（如开启热点线程采样）
+func newHarness(t *testing.T, cluster *fakees.Cluster, scenario fakees.Scenario, configure ...func(*config.Config)) *harness {
+	t.Helper()
+	delay := systemSampleDelay
+	systemSampleDelay = 0
+	t.Cleanup(func() { systemSampleDelay = delay })
+
+	srv := fakees.New(cluster, scenario)
+	t.Cleanup(srv.Close)
+
+	safety := config.DefaultSafetyConfig
+	safety.RequestsPerSecond = 0
+	safety.RetryBaseDelay = 10 * time.Millisecond
+	cfg := &config.Config{Hosts: []string{srv.URL()}, Safety: &safety, ReadOnly: true, Interval: time.Second}
+	for _, f := range configure {
+		f(cfg)
+	}
+	cl, err := client.NewElasticsearchClient(cfg)
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+	cl.SetClock(srv.Now)
+
+	ctx := context.Background()
+	if err := cl.Ping(ctx); err != nil {
+		t.Fatalf("连接模拟集群失败: %v", err)
+	}
+	return &harness{t: t, ctx: ctx, srv: srv, cl: cl, m: NewMonitor(cl, cfg)}
+}
+
+// step 推进一个周期并采集，任一采集失败都视为测试失败
+func (h *harness) step() *cycleResult {
+	h.t.Helper()
+	if err := h.srv.Advance(); err != nil {
+		h.t.Fatal(err)
+	}
+	r := h.m.gather(h.ctx)
+	for name, err := range map[string]error{
+		"集群健康": r.healthErr, "节点统计": r.nodeErr, "索引统计": r.indexErr, "分片": r.shardErr,
+		"待处理任务": r.pendingErr, "任务": r.taskErr, "生命周期": r.lifecycleErr, "快照": r.snapshotErr,
+		"热点线程": r.hotThreadsErr, "健康问题": r.enhancedErr,
+	} {
+		if err != nil {
+			h.t.Fatalf("周期 %d 采集%s失败: %v", h.srv.Tick(), name, err)
+		}
+	}
+	return r
+}
+
+// issue 按 Key 查找健康问题
+func issue(r *cycleResult, key string) (model.HealthIssue, bool) {
+	for _, i := range r.enhanced.HealthIssues {
+		if i.Key == key {
+			return i, true
+		}
+	}
+	return model.HealthIssue{}, false
+}
+
+// issueLevel 返回健康问题的级别，已恢复时为 resolved，没有该问题时为空
+func issueLevel(r *cycleResult, key string) string {
+	is, ok := issue(r, key)
+	switch {
+	case !ok:
+		return ""
+	case is.Resolved:
+		return "resolved"
+	}
+	return is.Level
+}
+
+func TestRejectionRateAcrossCounterReset(t *testing.T) {
+	h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+		fakees.At(1, "写入拒绝", fakees.Rejections("node-1", "write", 5)),
+		fakees.At(3, "节点重启", fakees.CounterReset("node-1")),
+	})
+
+	want := []struct {
+		delta int64
+		valid bool
+	}{
+		{0, false}, // 首次采集没有基准
+		{5, true},
+		{0, true}, // 重启后计数器归零，不能出现负增量
+		{5, true},
+	}
+	for i, w := range want {
+		r := h.step()
+		pool := r.enhanced.NodeThreadPools["node-1-id"]["write"]
+		if pool.RateValid != w.valid || pool.RejectedDelta != w.delta {
+			t.Errorf("周期 %d: 拒绝增量 = %d (有效 %v), 期望 %d (有效 %v)", i+1, pool.RejectedDelta, pool.RateValid, w.delta, w.valid)
+		}
+		// 模拟集群每个周期推进 2 秒
+		if w.valid && pool.RejectedPerSec != float64(w.delta)/2 {
+			t.Errorf("周期 %d: 拒绝速率 = %.2f/s, 期望 %.2f/s", i+1, pool.RejectedPerSec, float64(w.delta)/2)
+		}
+	}
+}
+
+func TestNodeIssuesRaiseAndClear(t *testing.T) {
+	h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+		fakees.At(2, "堆内存上升", fakees.HeapChange("node-2", 40)),
+		fakees.At(3, "堆内存继续上升", fakees.HeapChange("node-2", 10)),
+		fakees.At(3, "磁盘将满", fakees.DiskFree("node-3", 5)),
+		fakees.At(4, "堆内存回落", fakees.HeapChange("node-2", -50)),
+		fakees.At(5, "清理磁盘", fakees.DiskFree("node-3", 60)),
+	})
+
+	const heapKey, diskKey = "node/node-2/jvm.heap", "node/node-3/disk"
+	want := []struct {
+		heap, disk string // 问题级别，resolved 表示已恢复，空表示没有该问题
+	}{
+		{"", ""},
+		{"warning", ""},
+		{"critical", "critical"},
+		{"resolved", "critical"},
+		{"resolved", "resolved"},
+	}
+	for i, w := range want {
+		r := h.step()
+		for _, c := range []struct{ key, want string }{{heapKey, w.heap}, {diskKey, w.disk}} {
+			got := issueLevel(r, c.key)
+			if got != c.want {
+				t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, c.key, got, c.want)
+			}
+		}
+	}
+}
+
+func TestBreakerUsageUsesRoleThresholds(t *testing.T) {
+	// node-3 为冻结层节点，parent 断路器使用率约为堆内存使用率 / 95%
+	cluster := fakees.DefaultCluster()
+	cluster.Nodes[2].Roles = []string{"data_frozen"}
+	h := newHarness(t, cluster, fakees.Scenario{
+		fakees.At(2, "堆内存上升", fakees.HeapChange("node-2", 30)),
+		fakees.At(2, "冻结层堆内存上升", fakees.HeapChange("node-3", 30)),
+		fakees.At(3, "堆内存继续上升", fakees.HeapChange("node-2", 10)),
+		fakees.At(4, "堆内存回落", fakees.HeapChange("node-2", -40)),
+		fakees.At(4, "冻结层堆内存回落", fakees.HeapChange("node-3", -30)),
+	}, func(cfg *config.Config) {
+		cfg.Thresholds = &config.Thresholds{
+			ThresholdValues: config.DefaultThresholds.ThresholdValues,
+			Overrides: []config.ThresholdOverride{{
+				NodeRole:        "data_frozen",
+				ThresholdValues: config.ThresholdValues{BreakerWarning: 60, BreakerCritical: 70},
+			}},
+		}
+	})
+
+	const dataKey, frozenKey = "node/node-2/breaker.parent.usage", "node/node-3/breaker.parent.usage"
+	want := []struct {
+		data, frozen string // 问题级别，resolved 表示已恢复，空表示没有该问题
+	}{
+		{"", ""},
+		{"", "critical"}, // 73.7%：超过冻结层的 70%，未达到全局的 80%
+		{"warning", "critical"},
+		{"resolved", "resolved"},
+	}
+	for i, w := range want {
+		r := h.step()
+		for _, c := range []struct{ key, want string }{{dataKey, w.data}, {frozenKey, w.frozen}} {
+			if got := issueLevel(r, c.key); got != c.want {
+				t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, c.key, got, c.want)
+			}
+		}
+		if is, ok := issue(r, frozenKey); ok && !is.Resolved && is.Threshold != 70 {
+			t.Errorf("周期 %d: 冻结层断路器阈值 = %v, 期望 70", i+1, is.Threshold)
+		}
+		if breaker := r.enhanced.NodeCircuitBreakers["node-3-id"]["parent"]; !slices.Equal(breaker.NodeRoles, []string{"data_frozen"}) {
+			t.Errorf("周期 %d: 断路器节点角色 = %v, 期望 [data_frozen]", i+1, breaker.NodeRoles)
+		}
+	}
+}
+
+func TestThreadPoolsKeyedByNodeID(t *testing.T) {
+	// 节点替换后新旧节点同名（旧节点尚未离开集群）
+	h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+		fakees.At(1, "同名节点加入", fakees.NodeJoins(&fakees.Node{
+			ID: "node-1-new-id", Name: "node-1", IP: "10.0.0.4", Roles: []string{"data"},
+			HeapMaxBytes: 4 << 30, MemTotalBytes: 16 << 30, DiskTotalBytes: 500 << 30, DiskFreeBytes: 300 << 30,
+		})),
+	})
+
+	r := h.step()
+	for _, id := range []string{"node-1-id", "node-1-new-id"} {
+		if pool, ok := r.enhanced.NodeThreadPools[id]["write"]; !ok || pool.NodeName != "node-1" {
+			t.Errorf("节点 %s 的线程池 = %+v (存在 %v)", id, pool, ok)
+		}
+		if breaker, ok := r.enhanced.NodeCircuitBreakers[id]["parent"]; !ok || breaker.NodeName != "node-1" {
+			t.Errorf("节点 %s 的断路器 = %+v (存在 %v)", id, breaker, ok)
+		}
+	}
+}
+
+func TestUnassignedShardsSortedAndExplained(t *testing.T) {
+	for _, c := range []struct {
+		version string
+		want    []string // 分配诊断的分片（index/shard/p|r）
+	}{
+		// 8.14 之前只能诊断 ES 选择的第一个未分配分片
+		{"8.11.0", []string{"logs-2025.01.01/0/p"}},
+		{"8.14.0", []string{"logs-2025.01.01/0/p", "logs-2025.01.01/1/p", "logs-2025.01.01/0/r"}},
+	} {
+		t.Run(c.version, func(t *testing.T) {
+			cluster := fakees.DefaultCluster()
+			cluster.Version = c.version
+			h := newHarness(t, cluster, fakees.Scenario{
+				fakees.At(1, "节点离开后分片未分配", fakees.ShardsUnassigned(4, 2)),
+			})
+
+			r := h.step()
+			if r.shards == nil {
+				t.Fatal("存在未分配分片时应采集分片状态")
+			}
+			if r.shards.Unassigned != 4 || r.shards.Total != 18 {
+				t.Errorf("未分配 %d / 共 %d, 期望 4 / 18", r.shards.Unassigned, r.shards.Total)
+			}
+			// 未分配主分片排在副本之前，同类按索引名和分片号排序
+			var order []string
+			for _, s := range r.shards.Shards {
+				order = append(order, s.Index+"/"+s.Shard+"/"+s.PriRep)
+			}
+			wantOrder := []string{"logs-2025.01.01/0/p", "logs-2025.01.01/1/p", "logs-2025.01.01/0/r", "logs-2025.01.01/1/r"}
+			if strings.Join(order, " ") != strings.Join(wantOrder, " ") {
+				t.Errorf("分片顺序 = %v, 期望 %v", order, wantOrder)
+			}
+
+			if r.shards.ExplainErr != nil {
+				t.Fatalf("分配诊断失败: %v", r.shards.ExplainErr)
+			}
+			var explained []string
+			for _, a := range r.shards.Allocations {
+				kind := "r"
+				if a.Primary {
+					kind = "p"
+				}
+				explained = append(explained, fmt.Sprintf("%s/%d/%s", a.Index, a.Shard, kind))
+
+				switch {
+				case a.Primary && (a.CanAllocate != "no_valid_shard_copy" || len(a.Deciders) != 0):
+					t.Errorf("主分片 %s/%d 诊断 = %+v, 期望 no_valid_shard_copy 且没有决策器", a.Index, a.Shard, a)
+				case !a.Primary && (len(a.Deciders) != 1 || a.Deciders[0].Decider != "same_shard" || a.Deciders[0].Nodes != 3):
+					// 每个数据节点都已有该分片的主分片副本，汇总为同一个决策器
+					t.Errorf("副本 %s/%d 决策器 = %+v, 期望 same_shard 拒绝 3 个节点", a.Index, a.Shard, a.Deciders)
+				}
+			}
+			if strings.Join(explained, " ") != strings.Join(c.want, " ") {
+				t.Errorf("诊断的分片 = %v, 期望 %v", explained, c.want)
+			}
+		})
+	}
+}
+
+func TestPendingTaskThresholdAndHistory(t *testing.T) {
+	cluster := fakees.DefaultCluster()
+	cluster.Interval = 20 * time.Second // 默认阈值为 30 秒警告、120 秒严重
+	h := newHarness(t, cluster, fakees.Scenario{
+		fakees.At(2, "映射更新堆积", fakees.PendingTasks(4)),
+		fakees.At(9, "队列清空", fakees.PendingTasks(0)),
+	})
+
+	const key = "pending/oldest"
+	want := []struct {
+		depth int
+		level string // 问题级别，resolved 表示已恢复，空表示没有该问题
+	}{
+		{0, ""},
+		{4, ""},         // 刚开始排队
+		{4, ""},         // 20s
+		{4, "warning"},  // 40s
+		{4, "warning"},  // 60s
+		{4, "warning"},  // 80s
+		{4, "warning"},  // 100s
+		{4, "critical"}, // 120s
+		{0, "resolved"},
+	}
+	var depths []int
+	for i, w := range want {
+		r := h.step()
+		depths = append(depths, w.depth)
+		if len(r.pending.Tasks) != w.depth {
+			t.Errorf("周期 %d: 队列深度 = %d, 期望 %d", i+1, len(r.pending.Tasks), w.depth)
+		}
+		if fmt.Sprint(r.pending.History) != fmt.Sprint(depths) {
+			t.Errorf("周期 %d: 深度历史 = %v, 期望 %v", i+1, r.pending.History, depths)
+		}
+		if w.depth > 0 {
+			// 排队最久的任务排在最前面
+			if oldest := r.pending.Tasks[0]; oldest.TimeInQueueMillis != r.pending.OldestMillis || !oldest.Executing || r.pending.Executing != 1 {
+				t.Errorf("周期 %d: 最久任务 = %+v, 最久排队 %dms, 执行中 %d", i+1, oldest, r.pending.OldestMillis, r.pending.Executing)
+			}
+		}
+
+		got := issueLevel(r, key)
+		if got != w.level {
+			t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, key, got, w.level)
+		}
+	}
+}
+
+func TestHotThreadsProfileAndFoldedOutput(t *testing.T) {
+	folded := filepath.Join(t.TempDir(), "hot.folded")
+	h := newHarness(t, fakees.DefaultCluster(), nil, func(cfg *config.Config) {
+		cfg.HotThreadsEvery = 1
+		cfg.HotThreadsWindow = 2
+		cfg.HotThreadsNodes = "node-1"
+		cfg.HotThreadsFolded = folded
+	})
+	for i := 0; i < 3; i++ {
+		h.step()
+	}
+
+	// 节点 CPU 20%：write 线程 12%（9/10 快照为完整调用栈，1 次只少栈顶帧），search 线程 6%
+	// 每次采样 500ms，窗口内保留最近 2 次
+	profile := h.m.lastHotThreads
+	if profile == nil || profile.Samples != 2 || len(profile.Nodes) != 1 {
+		t.Fatalf("热点线程聚合 = %+v, 期望 node-1 的 2 次采样", profile)
+	}
+	node := profile.Nodes[0]
+	if node.Node != "node-1" || node.TotalMillis != 180 {
+		t.Errorf("节点 %s CPU 时间 = %.1fms, 期望 node-1 180ms", node.Node, node.TotalMillis)
+	}
+	wantTop := []struct {
+		frame       string
+		self, total float64
+	}{
+		{"org.apache.lucene.index.IndexingChain.processField", 108, 108},
+		{"org.apache.lucene.search.TermScorer.score", 60, 60},
+		{"org.apache.lucene.index.IndexingChain.processDocument", 12, 120},
+	}
+	for i, w := range wantTop {
+		if i >= len(node.Frames) {
+			t.Fatalf("热点栈帧只有 %d 个", len(node.Frames))
+		}
+		if f := node.Frames[i]; f.Frame != w.frame || f.SelfMillis != w.self || f.TotalMillis != w.total {
+			t.Errorf("第 %d 个热点栈帧 = %+v, 期望 %s 自身 %.0fms 合计 %.0fms", i+1, f, w.frame, w.self, w.total)
+		}
+	}
+
+	data, err := os.ReadFile(folded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeStack := "node-1;write;java.lang.Thread.run;" +
+		"org.elasticsearch.action.ActionListener$$Lambda/0x00000008018f3a8.onResponse;" +
+		"org.elasticsearch.action.bulk.TransportShardBulkAction.executeBulkItemRequest;" +
+		"org.elasticsearch.index.engine.InternalEngine.index;" +
+		"org.apache.lucene.index.DocumentsWriterPerThread.updateDocuments;" +
+		"org.apache.lucene.index.IndexingChain.processDocument"
+	want := []string{
+		writeStack + ";org.apache.lucene.index.IndexingChain.processField 108",
+		"node-1;search;java.lang.Thread.run;" +
+			"org.elasticsearch.search.SearchService.executeQueryPhase;" +
+			"org.elasticsearch.search.query.QueryPhase.executeInternal;" +
+			"org.apache.lucene.search.BooleanScorer.scoreDocument;" +
+			"org.apache.lucene.search.TermScorer.score 60",
+		writeStack + " 12",
+	}
+	if got := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("折叠调用栈 =\n%s\n期望\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLongRunningTasks(t *testing.T) {
+	cluster := fakees.DefaultCluster()
+	cluster.Interval = 5 * time.Minute // 默认阈值为 300 秒警告、1800 秒严重
+	const reindex, byQuery = "indices:data/write/reindex", "indices:data/write/update/byquery"
+	h := newHarness(t, cluster, fakees.Scenario{
+		fakees.At(1, "开始重建索引", fakees.TaskStarts(reindex, "node-2", 3)),
+		fakees.At(3, "开始按查询更新", fakees.TaskStarts(byQuery, "node-3", 0)),
+		fakees.At(8, "重建索引完成", fakees.TaskEnds(reindex)),
+	})
+
+	const reindexKey, byQueryKey = "task/node-2-id:1000/running_time", "task/node-3-id:1001/running_time"
+	want := []struct {
+		total   int
+		listed  []string // 按运行时间降序列出的任务
+		reindex string   // 问题级别，resolved 表示已恢复，空表示没有该问题
+		byQuery string
+	}{
+		{1, nil, "", ""}, // 刚启动的任务不列出
+		{1, []string{reindex}, "warning", ""},
+		{2, []string{reindex}, "warning", ""},
+		{2, []string{reindex, byQuery}, "warning", "warning"},
+		{2, []string{reindex, byQuery}, "warning", "warning"},
+		{2, []string{reindex, byQuery}, "warning", "warning"},
+		{2, []string{reindex, byQuery}, "critical", "warning"},
+		{1, []string{byQuery}, "resolved", "warning"},
+	}
+	for i, w := range want {
+		r := h.step()
+		// 列出任务的请求自身（cluster:monitor/tasks/lists）不计入
+		if r.tasks.Total != w.total {
+			t.Errorf("周期 %d: 任务数 = %d, 期望 %d", i+1, r.tasks.Total, w.total)
+		}
+		var listed []string
+		for _, task := range r.tasks.Tasks {
+			listed = append(listed, task.Action)
+			if task.Action == reindex && (task.TaskID != "node-2-id:1000" || task.NodeName != "node-2" || task.Children != 3) {
+				t.Errorf("周期 %d: 重建索引任务 = %+v", i+1, task)
+			}
+		}
+		if fmt.Sprint(listed) != fmt.Sprint(w.listed) {
+			t.Errorf("周期 %d: 列出的任务 = %v, 期望 %v", i+1, listed, w.listed)
+		}
+
+		for _, c := range []struct{ key, want string }{{reindexKey, w.reindex}, {byQueryKey, w.byQuery}} {
+			got := issueLevel(r, c.key)
+			if got != c.want {
+				t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, c.key, got, c.want)
+			}
+		}
+	}
+}
+
+func TestSegmentIssuesAndClusterRollup(t *testing.T) {
+	h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+		fakees.At(2, "频繁刷新产生小段", fakees.Segments("metrics", 250)),
+		fakees.At(3, "段继续增加", fakees.Segments("metrics", 1200)),
+		fakees.At(4, "强制合并", fakees.Segments("metrics", 10)),
+	})
+
+	const key = "segments/metrics"
+	want := []struct {
+		segments int    // 集群总段数（3 个索引各 3 主 1 副）
+		level    string // 问题级别，resolved 表示已恢复，空表示没有该问题
+	}{
+		{2*3*2*20 + 3*2*20, ""},
+		{2*3*2*20 + 3*2*250, "warning"},
+		{2*3*2*20 + 3*2*1200, "critical"},
+		{2*3*2*20 + 3*2*10, "resolved"},
+	}
+	for i, w := range want {
+		r := h.step()
+		stats := r.enhanced.ClusterStats
+		// 每 10 个段有一个合并在进行，各节点均分
+		if stats.TotalSegments != w.segments || stats.MergesCurrent != w.segments/3/10*3 {
+			t.Errorf("周期 %d: 总段数 %d、进行中的合并 %d, 期望 %d、%d", i+1, stats.TotalSegments, stats.MergesCurrent, w.segments, w.segments/3/10*3)
+		}
+		if got := issueLevel(r, key); got != w.level {
+			t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, key, got, w.level)
+		}
+	}
+
+	// 缓存命中率按节点累计值汇总，事务日志保留约 10 秒的写入（每个节点 1000 次/s）
+	stats := h.step().enhanced.ClusterStats
+	if stats.QueryCacheHitRatio != 75 || stats.RequestCacheHitRatio != 50 {
+		t.Errorf("缓存命中率 = 查询 %.1f%%、请求 %.1f%%, 期望 75%% 和 50%%", stats.QueryCacheHitRatio, stats.RequestCacheHitRatio)
+	}
+	if stats.QueryCacheMemoryMB != 3*256 || stats.TranslogOperations != 3*1000*10 {
+		t.Errorf("查询缓存 %.0fMB、事务日志操作 %d, 期望 768MB 和 30000", stats.QueryCacheMemoryMB, stats.TranslogOperations)
+	}
+}
+
+func TestLifecycleProblems(t *testing.T) {
+	cluster := fakees.DefaultCluster()
+	cluster.Interval = 20 * time.Minute
+	h := newHarness(t, cluster, fakees.Scenario{
+		// 日志索引创建于 2 小时前，max_age 改为 2h 后立即逾期，达到 4h 时为 2 倍
+		fakees.At(1, "缩小滚动周期", fakees.ILMRollover("logs-2025.01.01", "2h")),
+		fakees.At(1, "进入 shrink", fakees.ILMStep("metrics", "warm", "shrink", "shrink")),
+		fakees.At(2, "数据流生命周期失败", fakees.DSLError("users")),
+		fakees.At(7, "shrink 失败", fakees.ILMError("metrics", "shrink")),
+		fakees.At(8, "重试后完成", fakees.ILMStep("metrics", "warm", "complete", "complete")),
+	}, func(cfg *config.Config) {
+		// 面板的停留过久和健康问题使用同一个按索引覆盖的阈值
+		cfg.Thresholds = &config.Thresholds{
+			ThresholdValues: config.DefaultThresholds.ThresholdValues,
+			Overrides: []config.ThresholdOverride{{
+				IndexPattern:    "metrics",
+				ThresholdValues: config.ThresholdValues{ILMStepWarningMin: 40, ILMStepCriticalMin: 100},
+			}},
+		}
+	})
+
+	const logs, metrics, users = "logs-2025.01.01", "metrics", "users"
+	want := []struct {
+		panel  map[string]string // 面板中有问题的索引
+		issues map[string]string // 健康问题级别，空表示没有该问题（每周期 20 分钟，已恢复的问题超过保留时间，不再显示）
+	}{
+		{ // 在 shrink 步骤停留 0 分钟
+			map[string]string{logs: model.LifecycleRolloverOverdue},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "warning"},
+		},
+		{ // 20 分钟：超过 ILM 检查周期但未达到阈值，不算停留过久
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "warning", "ilm/users/error": "critical"},
+		},
+		{ // 40 分钟
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError, metrics: model.LifecycleStuck},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "warning", "ilm/users/error": "critical", "ilm/metrics/step": "warning"},
+		},
+		{
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError, metrics: model.LifecycleStuck},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "warning", "ilm/users/error": "critical", "ilm/metrics/step": "warning"},
+		},
+		{
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError, metrics: model.LifecycleStuck},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "warning", "ilm/users/error": "critical", "ilm/metrics/step": "warning"},
+		},
+		{ // 100 分钟；日志索引创建 4 小时，达到 max_age 的 2 倍
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError, metrics: model.LifecycleStuck},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "critical", "ilm/users/error": "critical", "ilm/metrics/step": "critical"},
+		},
+		{
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError, metrics: model.LifecycleError},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "critical", "ilm/users/error": "critical", "ilm/metrics/step": "", "ilm/metrics/error": "critical"},
+		},
+		{
+			map[string]string{logs: model.LifecycleRolloverOverdue, users: model.LifecycleError},
+			map[string]string{"ilm/logs-2025.01.01/rollover": "critical", "ilm/users/error": "critical", "ilm/metrics/step": "", "ilm/metrics/error": ""},
+		},
+	}
+	for i, w := range want {
+		r := h.step()
+		if r.lifecycle.ILMManaged != 2 {
+			t.Errorf("周期 %d: ILM 管理 %d 个索引, 期望 2", i+1, r.lifecycle.ILMManaged)
+		}
+
+		panel := make(map[string]string)
+		for _, s := range r.lifecycle.Indices {
+			panel[s.Index] = s.Problem
+		}
+		if fmt.Sprint(panel) != fmt.Sprint(w.panel) {
+			t.Errorf("周期 %d: 面板 = %v, 期望 %v", i+1, panel, w.panel)
+		}
+		for key, level := range w.issues {
+			if got := issueLevel(r, key); got != level {
+				t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, key, got, level)
+			}
+		}
+	}
+}
+
+func TestLifecycleExplainThrottled(t *testing.T) {
+	cluster := fakees.DefaultCluster()
+	cluster.Interval = 20 * time.Second
+	h := newHarness(t, cluster, fakees.Scenario{
+		fakees.At(2, "shrink 失败", fakees.ILMError("metrics", "shrink")),
+	}, func(cfg *config.Config) {
+		cfg.LifecycleInterval = time.Minute
+	})
+
+	want := []struct {
+		requests int    // 累计的 _ilm/explain 和 _lifecycle/explain 请求数
+		errors   int    // 面板中的出错索引数
+		level    string // 健康问题级别
+	}{
+		{2, 0, ""},
+		{2, 0, ""}, // 刷新间隔内沿用上次结果
+		{2, 0, ""},
+		{4, 1, "critical"}, // 距上次获取 1 分钟
+	}
+	for i, w := range want {
+		r := h.step()
+		requests := 0
+		for _, req := range h.srv.Requests() {
+			if strings.Contains(req, "/_ilm/explain") || strings.Contains(req, "/_lifecycle/explain") {
+				requests++
+			}
+		}
+		if requests != w.requests {
+			t.Errorf("周期 %d: explain 请求数 = %d, 期望 %d", i+1, requests, w.requests)
+		}
+		if r.lifecycle.Errors != w.errors {
+			t.Errorf("周期 %d: 出错索引数 = %d, 期望 %d", i+1, r.lifecycle.Errors, w.errors)
+		}
+		if got := issueLevel(r, "ilm/metrics/error"); got != w.level {
+			t.Errorf("周期 %d: ilm/metrics/error = %q, 期望 %q", i+1, got, w.level)
+		}
+	}
+}
+
+func TestSnapshotOverdue(t *testing.T) {
+	const policy, key = "nightly-snapshots", "snapshot/nightly-snapshots/overdue"
+	for _, c := range []struct {
+		name    string
+		windows map[string]time.Duration
+		window  time.Duration // 成功窗口
+		level   string        // 超过窗口时的问题级别
+	}{
+		// 每天 01:30 执行，上次成功于 2024-12-31 01:35 完成：
+		// 窗口推算到下一次计划执行（01-01 01:30）再加半个调度周期
+		{"按调度推算", nil, 35*time.Hour + 55*time.Minute, "warning"},
+		{"配置窗口", map[string]time.Duration{policy: 30 * time.Hour}, 30 * time.Hour, "critical"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+				fakees.At(2, "长时间没有成功的快照", fakees.SnapshotAge(policy, 40*time.Hour)),
+				fakees.At(3, "快照成功", fakees.SnapshotSucceeds(policy)),
+			}, func(cfg *config.Config) {
+				cfg.SnapshotWindows = c.windows
+			})
+
+			for i, level := range []string{"", c.level, "resolved"} {
+				r := h.step()
+				if len(r.snapshots.Policies) != 1 {
+					t.Fatalf("周期 %d: 快照策略 = %+v", i+1, r.snapshots.Policies)
+				}
+				p := r.snapshots.Policies[0]
+				if p.Overdue != (level == c.level) || p.Configured != (c.windows != nil) {
+					t.Errorf("周期 %d: 逾期 %v、配置窗口 %v", i+1, p.Overdue, p.Configured)
+				}
+				if i == 0 && p.WindowMillis != c.window.Milliseconds() {
+					t.Errorf("成功窗口 = %v, 期望 %v", time.Duration(p.WindowMillis)*time.Millisecond, c.window)
+				}
+				if got := issueLevel(r, key); got != level {
+					t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, key, got, level)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeLeavesResolvesItsIssues(t *testing.T) {
+	h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+		fakees.At(2, "堆内存上升", fakees.HeapChange("node-2", 40)),
+		fakees.At(3, "节点离开", fakees.NodeLeaves("node-2")),
+	})
+
+	const heapKey = "node/node-2/jvm.heap"
+	want := []struct {
+		heap    string
+		present bool // node-2 的线程池和断路器是否存在
+		nodes   int  // 有线程池统计的节点数
+	}{
+		{"", true, 3},
+		{"warning", true, 3},
+		{"resolved", false, 2},
+	}
+	for i, w := range want {
+		r := h.step()
+		if got := issueLevel(r, heapKey); got != w.heap {
+			t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, heapKey, got, w.heap)
+		}
+		_, pools := r.enhanced.NodeThreadPools["node-2-id"]
+		_, breakers := r.enhanced.NodeCircuitBreakers["node-2-id"]
+		if pools != w.present || breakers != w.present {
+			t.Errorf("周期 %d: node-2 线程池存在 %v、断路器存在 %v, 期望 %v", i+1, pools, breakers, w.present)
+		}
+		if n := len(r.enhanced.NodeThreadPools); n != w.nodes {
+			t.Errorf("周期 %d: 线程池节点数 = %d, 期望 %d", i+1, n, w.nodes)
+		}
+	}
+}
+
+func TestFailedNodeStatsKeepIssueState(t *testing.T) {
+	// 节点统计失败 3 次（首次请求 + 2 次重试）使第 3 个周期采集失败
+	h := newHarness(t, fakees.DefaultCluster(), fakees.Scenario{
+		fakees.At(2, "堆内存上升", fakees.HeapChange("node-2", 40)),
+		fakees.At(3, "节点统计不可用", fakees.FailRequests("/_nodes/stats", 503, 3)),
+		fakees.At(4, "堆内存回落", fakees.HeapChange("node-2", -40)),
+	})
+
+	const heapKey = "node/node-2/jvm.heap"
+	want := []struct {
+		heap    string
+		nodeErr bool
+	}{
+		{"", false},
+		{"warning", false},
+		{"warning", true}, // 本周期未检查节点，保留上次的状态而不是视为恢复
+		{"resolved", false},
+	}
+	for i, w := range want {
+		if err := h.srv.Advance(); err != nil {
+			t.Fatal(err)
+		}
+		r := h.m.gather(h.ctx)
+		if (r.nodeErr != nil) != w.nodeErr {
+			t.Errorf("周期 %d: 节点统计错误 = %v, 期望出错 %v", i+1, r.nodeErr, w.nodeErr)
+		}
+		if r.healthErr != nil || r.enhancedErr != nil {
+			t.Fatalf("周期 %d: 集群健康或健康问题采集失败: %v / %v", i+1, r.healthErr, r.enhancedErr)
+		}
+		if got := issueLevel(r, heapKey); got != w.heap {
+			t.Errorf("周期 %d: %s = %q, 期望 %q", i+1, heapKey, got, w.heap)
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package fakees
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// Cluster 模拟集群状态（由场景脚本逐周期修改）
+type Cluster struct {
+	Name    string
+	Version string
+
+	Nodes   []*Node
+	Indices []*Index
+
+	UnassignedShards    int       // 未分配分片数
+	UnassignedPrimaries int       // 其中未分配的主分片数（大于 0 时集群为 red）
+	PendingTasks        int       // 主节点待处理任务数
+	PendingSince        time.Time // 队列开始积压的时间（最早任务的入队时间）
+	Tasks               []*Task   // 运行中的顶层任务
+
+	SnapshotPolicies []*SnapshotPolicy // 快照生命周期策略
+
+	Now      time.Time     // 模拟时钟（响应中的 timestamp 字段）
+	Interval time.Duration // 每个周期推进的时间
+
+	failures []failure
+}
+
+// Node 模拟节点
+type Node struct {
+	ID    string
+	Name  string
+	IP    string
+	Roles []string
+
+	HeapMaxBytes    int64
+	HeapUsedPercent int
+	CPUPercent      int
+	MemTotalBytes   int64
+	MemUsedPercent  int
+	DiskTotalBytes  int64
+	DiskFreeBytes   int64
+
+	Docs       int
+	StoreBytes int64
+
+	// 累计计数器（节点重启后归零）和每个周期的增量
+	IndexTotal int
+	QueryTotal int
+	IndexRate  int
+	QueryRate  int
+
+	// 线程池累计拒绝数和每个周期的增量（键为线程池名称）
+	Rejected   map[string]int64
+	RejectRate map[string]int64
+
+	// 断路器累计触发次数（键为断路器名称）
+	Tripped map[string]int64
+
+	StartedAt time.Time
+}
+
+// Index 模拟索引
+type Index struct {
+	Name      string
+	UUID      string
+	Status    string // open / close
+	Primaries int
+	Replicas  int
+
+	Docs       int
+	StoreBytes int64
+
+	IndexTotal int
+	QueryTotal int
+	IndexRate  int
+	QueryRate  int
+
+	SegmentsPerShard int // 每个分片的段数
+
+	CreatedAt  time.Time
+	ILM        *ILMState // ILM 执行状态，为空表示不受 ILM 管理
+	DSLManaged bool      // 是否由数据流生命周期管理
+	DSLError   string    // 数据流生命周期最近一次执行失败的原因
+}
+
+// failure 注入的请求失败（按路径前缀匹配，剩余次数用完后恢复）
+type failure struct {
+	prefix    string
+	status    int
+	remaining int
+}
+
+// clusterEpoch 模拟时钟起点（固定值保证结果可重复）
+var clusterEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// DefaultCluster 创建默认集群：3 个节点、3 个索引、ES 8.11.0
+func DefaultCluster() *Cluster {
+	c := &Cluster{
+		Name:     "fake-cluster",
+		Version:  "8.11.0",
+		Now:      clusterEpoch,
+		Interval: 2 * time.Second,
+	}
+	for i := 1; i <= 3; i++ {
+		c.Nodes = append(c.Nodes, &Node{
+			ID:              fmt.Sprintf("node-%d-id", i),
+			Name:            fmt.Sprintf("node-%d", i),
+			IP:              fmt.Sprintf("10.0.0.%d", i),
+			Roles:           []string{"data", "ingest", "master"},
+			HeapMaxBytes:    4 << 30,
+			HeapUsedPercent: 40,
+			CPUPercent:      20,
+			MemTotalBytes:   16 << 30,
+			MemUsedPercent:  60,
+			DiskTotalBytes:  500 << 30,
+			DiskFreeBytes:   300 << 30,
+			Docs:            1000000,
+			StoreBytes:      10 << 30,
+			IndexTotal:      5000000,
+			QueryTotal:      2000000,
+			IndexRate:       1000,
+			QueryRate:       200,
+			StartedAt:       clusterEpoch.Add(-24 * time.Hour),
+		})
+	}
+	for i, name := range []string{"logs-2025.01.01", "metrics", "users"} {
+		c.Indices = append(c.Indices, &Index{
+			Name:       name,
+			UUID:       fmt.Sprintf("uuid-%d", i),
+			Status:     "open",
+			Primaries:  3,
+			Replicas:   1,
+			Docs:       1000000,
+			StoreBytes: 5 << 30,
+			IndexTotal: 3000000,
+			QueryTotal: 1000000,
+			IndexRate:  500,
+			QueryRate:  100,
+
+			SegmentsPerShard: 20,
+			CreatedAt:        clusterEpoch.Add(-2 * time.Hour),
+		})
+	}
+	// 日志索引由 ILM 管理：处于热阶段，等待滚动条件
+	logs := c.Indices[0]
+	logs.ILM = &ILMState{
+		Policy:                      "logs",
+		Phase:                       "hot",
+		Action:                      "rollover",
+		Step:                        "check-rollover-ready",
+		PhaseSince:                  logs.CreatedAt,
+		StepSince:                   logs.CreatedAt,
+		RolloverMaxAge:              "1d",
+		RolloverMaxPrimaryShardSize: "50gb",
+	}
+	// 每天 01:30 执行的快照策略，上一次在前一天成功
+	lastRun := clusterEpoch.Add(-22*time.Hour - 30*time.Minute)
+	c.SnapshotPolicies = []*SnapshotPolicy{{
+		ID:           "nightly-snapshots",
+		Repository:   "backups",
+		Schedule:     "0 30 1 * * ?",
+		ModifiedAt:   clusterEpoch.Add(-30 * 24 * time.Hour),
+		NextRunAt:    lastRun.Add(24 * time.Hour),
+		LastSuccess:  &SnapshotRun{Name: "nightly-snapshots-2024.12.31", StartedAt: lastRun, EndedAt: lastRun.Add(5 * time.Minute)},
+		Taken:        30,
+		ShardsPerRun: 18,
+		BytesPerRun:  15 << 30,
+	}}
+	return c
+}
+
+// Node 按 ID 或名称查找节点
+func (c *Cluster) Node(name string) *Node {
+	for _, n := range c.Nodes {
+		if n.ID == name || n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// Index 按名称查找索引
+func (c *Cluster) Index(name string) *Index {
+	for _, idx := range c.Indices {
+		if idx.Name == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+// advance 推进一个周期：时钟前进，计数器按速率增长
+func (c *Cluster) advance() {
+	c.Now = c.Now.Add(c.Interval)
+	for _, n := range c.Nodes {
+		n.IndexTotal += n.IndexRate
+		n.QueryTotal += n.QueryRate
+		for pool, rate := range n.RejectRate {
+			if n.Rejected == nil {
+				n.Rejected = make(map[string]int64)
+			}
+			n.Rejected[pool] += rate
+		}
+	}
+	for _, idx := range c.Indices {
+		idx.IndexTotal += idx.IndexRate
+		idx.QueryTotal += idx.QueryRate
+	}
+	c.advanceSnapshots()
+}
+
+// status 集群状态：主分片未分配为 red，副本未分配为 yellow
+func (c *Cluster) status() string {
+	switch {
+	case c.UnassignedPrimaries > 0:
+		return "red"
+	case c.UnassignedShards > 0:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
+// info 根端点响应
+func (c *Cluster) info() model.ClusterInfo {
+	var info model.ClusterInfo
+	info.Name = c.Name + "-node"
+	info.ClusterName = c.Name
+	info.ClusterUUID = "fake-cluster-uuid"
+	info.Version.Number = c.Version
+	info.Version.BuildFlavor = "default"
+	info.Tagline = "You Know, for Search"
+	return info
+}
+
+// health /_cluster/health 响应
+func (c *Cluster) health() model.ClusterHealth {
+	h := model.ClusterHealth{
+		ClusterName:      c.Name,
+		Status:           c.status(),
+		NumberOfNodes:    len(c.Nodes),
+		UnassignedShards: c.UnassignedShards,
+		PendingTasks:     c.PendingTasks,
+	}
+	for _, n := range c.Nodes {
+		if hasDataRole(n.Roles) {
+			h.NumberOfDataNodes++
+		}
+	}
+
+	total := 0
+	for _, idx := range c.Indices {
+		if idx.Status != "open" {
+			continue
+		}
+		h.ActivePrimaryShards += idx.Primaries
+		total += idx.Primaries * (1 + idx.Replicas)
+	}
+	h.ActivePrimaryShards -= c.UnassignedPrimaries
+	h.ActiveShards = total - c.UnassignedShards
+	if total > 0 {
+		h.ActiveShardsPercent = float64(h.ActiveShards) * 100 / float64(total)
+	}
+	return h
+}
+
+// healthReport /_health_report 响应（仅包含分片可用性指标）
+func (c *Cluster) healthReport() model.HealthReport {
+	symptom := "This cluster has all shards available."
+	if c.UnassignedShards > 0 {
+		symptom = fmt.Sprintf("This cluster has %d unavailable shards.", c.UnassignedShards)
+	}
+	return model.HealthReport{
+		ClusterName: c.Name,
+		Status:      c.status(),
+		Indicators: map[string]model.HealthReportIndicator{
+			"shards_availability": {Status: c.status(), Symptom: symptom},
+		},
+	}
+}
+
+// nodeStats /_nodes/stats 响应
+func (c *Cluster) nodeStats() model.NodeStats {
+	ts := c.Now.UnixMilli()
+	stats := model.NodeStats{Nodes: make(map[string]model.NodeStat, len(c.Nodes))}
+	for _, n := range c.Nodes {
+		var s model.NodeStat
+		s.Name = n.Name
+		s.Host = n.IP
+		s.IP = n.IP
+		s.Roles = n.Roles
+		s.Timestamp = ts
+
+		s.JVM.Timestamp = ts
+		s.JVM.UptimeInMillis = c.Now.Sub(n.StartedAt).Milliseconds()
+		s.JVM.Mem.HeapMaxInBytes = n.HeapMaxBytes
+		s.JVM.Mem.HeapCommittedInBytes = n.HeapMaxBytes
+		s.JVM.Mem.HeapUsedPercent = n.HeapUsedPercent
+		s.JVM.Mem.HeapUsedInBytes = n.HeapMaxBytes * int64(n.HeapUsedPercent) / 100
+		s.JVM.Threads.Count = 120
+		s.JVM.Threads.PeakCount = 150
+
+		s.OS.Timestamp = ts
+		s.OS.CPU.Percent = n.CPUPercent
+		s.OS.CPU.LoadAverage.OneMinute = float64(n.CPUPercent) / 25
+		s.OS.Mem.TotalInBytes = n.MemTotalBytes
+		s.OS.Mem.UsedPercent = n.MemUsedPercent
+		s.OS.Mem.UsedInBytes = n.MemTotalBytes * int64(n.MemUsedPercent) / 100
+		s.OS.Mem.FreeInBytes = n.MemTotalBytes - s.OS.Mem.UsedInBytes
+		s.OS.Mem.FreePercent = 100 - n.MemUsedPercent
+
+		s.Process.Timestamp = ts
+		s.Process.OpenFileDescriptors = 1000
+		s.Process.MaxFileDescriptors = 65535
+		s.Process.CPU.Percent = n.CPUPercent
+
+		s.FS.Timestamp = ts
+		s.FS.Total.TotalInBytes = n.DiskTotalBytes
+		s.FS.Total.FreeInBytes = n.DiskFreeBytes
+		s.FS.Total.AvailableInBytes = n.DiskFreeBytes
+
+		s.HTTP.CurrentOpen = 5
+		s.HTTP.TotalOpened = 100
+		s.Transport.ServerOpen = 26
+
+		s.Indices.Docs.Count = n.Docs
+		s.Indices.Store.SizeInBytes = n.StoreBytes
+		s.Indices.Indexing.IndexTotal = n.IndexTotal
+		s.Indices.Search.QueryTotal = n.QueryTotal
+		fillSegmentStats(&s.Indices.Segments, &s.Indices.Merges, c.totalSegments()/len(c.Nodes))
+		fillCacheStats(&s.Indices.QueryCache, &s.Indices.RequestCache, &s.Indices.FieldData, int64(n.QueryTotal))
+		fillTranslogStats(&s.Indices.Translog, int64(n.IndexRate))
+		s.Indices.Refresh.Total = n.IndexTotal / 1000
+		s.Indices.Flush.Total = n.IndexTotal / 100000
+
+		s.ThreadPool = make(map[string]model.ThreadPool, len(threadPools))
+		for _, pool := range threadPools {
+			s.ThreadPool[pool] = model.ThreadPool{
+				Threads:   8,
+				Active:    1,
+				Largest:   8,
+				Rejected:  n.Rejected[pool],
+				Completed: int64(n.IndexTotal + n.QueryTotal),
+			}
+		}
+
+		// parent 断路器跟随堆内存使用，其他断路器保持较低用量
+		heapUsed := s.JVM.Mem.HeapUsedInBytes
+		s.Breakers = make(map[string]model.Breaker, len(breakerLimits))
+		for name, percent := range breakerLimits {
+			estimated := heapUsed / 10
+			if name == "parent" {
+				estimated = heapUsed
+			}
+			s.Breakers[name] = model.Breaker{
+				LimitSizeInBytes:     n.HeapMaxBytes * percent / 100,
+				EstimatedSizeInBytes: estimated,
+				Overhead:             1.0,
+				Tripped:              n.Tripped[name],
+			}
+		}
+
+		stats.Nodes[n.ID] = s
+	}
+	return stats
+}
+
+// threadPools 模拟节点返回的线程池
+var threadPools = []string{"search", "write", "get", "management"}
+
+// breakerLimits 各断路器限制占堆内存的百分比（与 ES 默认配置一致）
+var breakerLimits = map[string]int64{
+	"parent":             95,
+	"fielddata":          40,
+	"request":            60,
+	"in_flight_requests": 100,
+	"accounting":         100,
+}
+
+// indexStats /_stats 响应
+func (c *Cluster) indexStats() model.IndexStats {
+	stats := model.IndexStats{Indices: make(map[string]model.IndexStat, len(c.Indices))}
+	for _, idx := range c.Indices {
+		var s model.IndexStat
+		s.UUID = idx.UUID
+		s.Status = idx.Status
+		s.Health = c.status()
+
+		s.Primaries.Docs.Count = idx.Docs
+		s.Primaries.Store.SizeInBytes = idx.StoreBytes
+		s.Primaries.Indexing.IndexTotal = idx.IndexTotal
+		s.Primaries.Search.QueryTotal = idx.QueryTotal
+
+		copies := 1 + idx.Replicas
+		s.Total.Docs.Count = idx.Docs * copies
+		s.Total.Store.SizeInBytes = idx.StoreBytes * int64(copies)
+		s.Total.Indexing.IndexTotal = idx.IndexTotal * copies
+		s.Total.Search.QueryTotal = idx.QueryTotal
+
+		fillSegmentStats(&s.Primaries.Segments, &s.Primaries.Merges, idx.SegmentsPerShard*idx.Primaries)
+		fillSegmentStats(&s.Total.Segments, &s.Total.Merges, idx.SegmentsPerShard*idx.Primaries*copies)
+		fillCacheStats(&s.Total.QueryCache, &s.Total.RequestCache, &s.Total.FieldData, int64(idx.QueryTotal))
+		fillTranslogStats(&s.Total.Translog, int64(idx.IndexRate))
+
+		stats.Indices[idx.Name] = s
+	}
+	return stats
+}
+
+// indexStatsAll /_stats 响应中的 _all 汇总（只汇总文档数和存储大小）
+func (c *Cluster) indexStatsAll() model.IndexStat {
+	var all model.IndexStat
+	for _, idx := range c.Indices {
+		copies := 1 + idx.Replicas
+		all.Primaries.Docs.Count += idx.Docs
+		all.Primaries.Store.SizeInBytes += idx.StoreBytes
+		all.Total.Docs.Count += idx.Docs * copies
+		all.Total.Store.SizeInBytes += idx.StoreBytes * int64(copies)
+	}
+	return all
+}
+
+// totalShards 打开的索引的分片总数（含副本）
+func (c *Cluster) totalShards() int {
+	total := 0
+	for _, idx := range c.Indices {
+		if idx.Status == "open" {
+			total += idx.Primaries * (1 + idx.Replicas)
+		}
+	}
+	return total
+}
+
+// totalSegments 集群中打开的索引的段数合计（含副本）
+func (c *Cluster) totalSegments() int {
+	total := 0
+	for _, idx := range c.Indices {
+		if idx.Status == "open" {
+			total += idx.SegmentsPerShard * idx.Primaries * (1 + idx.Replicas)
+		}
+	}
+	return total
+}
+
+// fillSegmentStats 按段数生成段和合并统计：每 10 个段有一个合并在进行
+func fillSegmentStats(segments *model.SegmentStats, merges *model.MergeStats, count int) {
+	segments.Count = count
+	merges.Current = count / 10
+	merges.CurrentSizeInBytes = int64(merges.Current) * (64 << 20)
+}
+
+// fillCacheStats 按查询数生成缓存统计：查询缓存命中率 75%，请求缓存命中率 50%
+func fillCacheStats(query *model.QueryCacheStats, request *model.RequestCacheStats, fieldData *model.FieldDataStats, queries int64) {
+	query.MemorySizeInBytes = 256 << 20
+	query.HitCount = queries * 3 / 4
+	query.MissCount = queries - query.HitCount
+	request.MemorySizeInBytes = 32 << 20
+	request.HitCount = queries / 10
+	request.MissCount = queries / 10
+	fieldData.MemorySizeInBytes = 8 << 20
+}
+
+// fillTranslogStats 事务日志保留约 10 秒的写入
+func fillTranslogStats(translog *model.TranslogStats, indexRate int64) {
+	translog.Operations = indexRate * 10
+	translog.SizeInBytes = translog.Operations * 512
+	translog.UncommittedOperations = translog.Operations
+	translog.UncommittedSizeInBytes = translog.SizeInBytes
+}
+
+// catIndices /_cat/indices?format=json&bytes=b 响应
+func (c *Cluster) catIndices() []model.IndexInfo {
+	result := make([]model.IndexInfo, 0, len(c.Indices))
+	for _, idx := range c.Indices {
+		result = append(result, model.IndexInfo{
+			Health:       c.status(),
+			Status:       idx.Status,
+			Index:        idx.Name,
+			UUID:         idx.UUID,
+			Pri:          strconv.Itoa(idx.Primaries),
+			Rep:          strconv.Itoa(idx.Replicas),
+			DocsCount:    strconv.Itoa(idx.Docs),
+			DocsDeleted:  "0",
+			StoreSize:    strconv.FormatInt(idx.StoreBytes*int64(1+idx.Replicas), 10),
+			PriStoreSize: strconv.FormatInt(idx.StoreBytes, 10),
+		})
+	}
+	return result
+}
+
+// hasDataRole 是否为数据节点（data、data_hot 等）
+func hasDataRole(roles []string) bool {
+	for _, r := range roles {
+		if strings.HasPrefix(r, "data") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package fakees
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// catByteColumns _cat 响应中的字节数列，未指定 bytes=b 时 ES 返回带单位的值
+var catByteColumns = map[string]bool{
+	"store":          true,
+	"store.size":     true,
+	"pri.store.size": true,
+}
+
+// toGeneric 将响应转换为 JSON 通用结构（数字保留原始文本）
+func toGeneric(body interface{}) interface{} {
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(fmt.Sprintf("fakees: 编码响应失败: %v", err))
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		panic(fmt.Sprintf("fakees: 解码响应失败: %v", err))
+	}
+	return v
+}
+
+// withFields 在对象响应的顶层加入附加字段（如 _nodes、_shards、_all）
+func withFields(body interface{}, fields map[string]interface{}) interface{} {
+	obj, ok := toGeneric(body).(map[string]interface{})
+	if !ok {
+		return body
+	}
+	for k, v := range fields {
+		obj[k] = v
+	}
+	return obj
+}
+
+// filterResponse 按 filter_path 过滤响应，与 ES 相同：逗号分隔多个路径，路径按 . 分段，
+// 段中的 * 匹配任意字符，** 匹配任意层级；数组按元素过滤，没有匹配字段的对象和数组被移除
+func filterResponse(body interface{}, filter string) interface{} {
+	var paths [][]string
+	for _, p := range strings.Split(filter, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, strings.Split(p, "."))
+		}
+	}
+	if len(paths) == 0 {
+		return body
+	}
+	if v, ok := filterValue(toGeneric(body), paths); ok {
+		return v
+	}
+	return map[string]interface{}{}
+}
+
+// filterValue 保留匹配任一路径的字段，返回过滤后的值和是否有匹配
+func filterValue(v interface{}, paths [][]string) (interface{}, bool) {
+	for _, p := range paths {
+		if len(p) == 0 {
+			return v, true
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, child := range v {
+			var next [][]string
+			for _, p := range paths {
+				next = append(next, matchSegment(p, key)...)
+			}
+			if len(next) == 0 {
+				continue
+			}
+			if filtered, ok := filterValue(child, next); ok {
+				result[key] = filtered
+			}
+		}
+		return result, len(result) > 0
+	case []interface{}:
+		var result []interface{}
+		for _, elem := range v {
+			if filtered, ok := filterValue(elem, paths); ok {
+				result = append(result, filtered)
+			}
+		}
+		return result, len(result) > 0
+	default:
+		return nil, false
+	}
+}
+
+// matchSegment 返回路径匹配 key 后剩余的路径（不匹配时为空）
+func matchSegment(path []string, key string) [][]string {
+	if path[0] != "**" {
+		if wildcardMatch(path[0], key) {
+			return [][]string{path[1:]}
+		}
+		return nil
+	}
+	// ** 可以继续匹配更深层级，也可以匹配零层后由下一段匹配 key
+	rest := [][]string{path}
+	if len(path) == 1 {
+		return append(rest, nil)
+	}
+	return append(rest, matchSegment(path[1:], key)...)
+}
+
+// wildcardMatch 判断 key 是否匹配含 * 的模式
+func wildcardMatch(pattern, key string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == key
+	}
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+	key = key[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(key, part)
+		if idx == -1 {
+			return false
+		}
+		key = key[idx+len(part):]
+	}
+	return strings.HasSuffix(key, parts[len(parts)-1])
+}
+
+// catRows 按 h 和 bytes 参数生成 _cat 响应：h 指定时只返回这些列，
+// bytes 不为 b 时字节数列带单位（如 5gb），与 ES 一致
+func catRows(rows interface{}, query url.Values) interface{} {
+	var columns []string
+	if h := query.Get("h"); h != "" {
+		columns = strings.Split(h, ",")
+	}
+	raw := query.Get("bytes") == "b"
+
+	list, _ := toGeneric(rows).([]interface{})
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, elem := range list {
+		row, _ := elem.(map[string]interface{})
+		if !raw {
+			for col := range catByteColumns {
+				if s, ok := row[col].(string); ok {
+					row[col] = humanBytes(s)
+				}
+			}
+		}
+		if columns != nil {
+			selected := make(map[string]interface{}, len(columns))
+			for _, col := range columns {
+				selected[col] = row[col]
+			}
+			row = selected
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+// humanBytes 将字节数格式化为 ES _cat 的带单位形式（如 5gb、512mb）
+func humanBytes(s string) string {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return s
+	}
+	units := []string{"b", "kb", "mb", "gb", "tb"}
+	value, unit := float64(n), 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return strings.TrimSuffix(strconv.FormatFloat(value, 'f', 1, 64), ".0") + units[unit]
+}
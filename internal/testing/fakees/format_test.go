@@ -0,0 +1,52 @@
+package fakees
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestFilterResponse(t *testing.T) {
+	body := map[string]interface{}{
+		"_shards": map[string]int{"total": 6},
+		"indices": map[string]interface{}{
+			"logs-2025.01.01": map[string]interface{}{"uuid": "a", "primaries": map[string]int{"docs": 1, "store": 2}},
+			"metrics":         map[string]interface{}{"uuid": "b", "primaries": map[string]int{"docs": 3, "store": 4}},
+		},
+		"tasks": []interface{}{
+			map[string]string{"action": "search", "node": "n1"},
+			map[string]string{"node": "n2"},
+		},
+	}
+	tests := []struct {
+		filter, want string
+	}{
+		{"indices.*.uuid", `{"indices":{"logs-2025.01.01":{"uuid":"a"},"metrics":{"uuid":"b"}}}`},
+		{"indices.met*.primaries.docs,_shards", `{"_shards":{"total":6},"indices":{"metrics":{"primaries":{"docs":3}}}}`},
+		{"**.store", `{"indices":{"logs-2025.01.01":{"primaries":{"store":2}},"metrics":{"primaries":{"store":4}}}}`},
+		{"tasks.action", `{"tasks":[{"action":"search"}]}`},
+		{"missing", `{}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(filterResponse(body, tt.filter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("filter_path=%s: %s, 期望 %s", tt.filter, data, tt.want)
+		}
+	}
+}
+
+func TestCatRows(t *testing.T) {
+	rows := []map[string]string{{"index": "logs", "store.size": "5368709120", "docs.count": "10"}}
+
+	data, _ := json.Marshal(catRows(rows, url.Values{"h": {"index,store.size"}}))
+	if want := `[{"index":"logs","store.size":"5gb"}]`; string(data) != want {
+		t.Errorf("未指定 bytes: %s, 期望 %s", data, want)
+	}
+	data, _ = json.Marshal(catRows(rows, url.Values{"h": {"store.size"}, "bytes": {"b"}}))
+	if want := `[{"store.size":"5368709120"}]`; string(data) != want {
+		t.Errorf("bytes=b: %s, 期望 %s", data, want)
+	}
+}
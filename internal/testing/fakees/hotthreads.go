@@ -0,0 +1,83 @@
+package fakees
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hotStacks 模拟线程池的调用栈（栈顶在前，含 lambda 隐藏类栈帧），按 CPU 占比分配给各线程池
+var hotStacks = []struct {
+	pool   string
+	share  int // 占节点 CPU 的百分比
+	frames []string
+}{
+	{"write", 60, []string{
+		"app//org.apache.lucene.index.IndexingChain.processField(IndexingChain.java:702)",
+		"app//org.apache.lucene.index.IndexingChain.processDocument(IndexingChain.java:554)",
+		"app//org.apache.lucene.index.DocumentsWriterPerThread.updateDocuments(DocumentsWriterPerThread.java:241)",
+		"app//org.elasticsearch.index.engine.InternalEngine.index(InternalEngine.java:1046)",
+		"app//org.elasticsearch.action.bulk.TransportShardBulkAction.executeBulkItemRequest(TransportShardBulkAction.java:284)",
+		"app//org.elasticsearch.action.ActionListener$$Lambda/0x00000008018f3a8.onResponse(Unknown Source)",
+		"java.base@17.0.2/java.lang.Thread.run(Thread.java:833)",
+	}},
+	{"search", 30, []string{
+		"app//org.apache.lucene.search.TermScorer.score(TermScorer.java:65)",
+		"app//org.apache.lucene.search.BooleanScorer.scoreDocument(BooleanScorer.java:211)",
+		"app//org.elasticsearch.search.query.QueryPhase.executeInternal(QueryPhase.java:191)",
+		"app//org.elasticsearch.search.SearchService.executeQueryPhase(SearchService.java:620)",
+		"java.base@17.0.2/java.lang.Thread.run(Thread.java:833)",
+	}},
+}
+
+// hotThreads /_nodes[/{node_id}]/hot_threads 文本响应（ES 8 格式）
+// 每个节点按 CPUPercent 生成 write、search 线程；write 线程有一组只出现一次的调用栈
+func (c *Cluster) hotThreads(nodeIDs string) string {
+	var sb strings.Builder
+	for _, n := range c.Nodes {
+		if nodeIDs != "" && !containsNode(c, nodeIDs, n) {
+			continue
+		}
+		fmt.Fprintf(&sb, "::: {%s}{%s}{ephemeral-%s}{%s}{%s:9300}{%s}\n",
+			n.Name, n.ID, n.ID, n.IP, n.IP, strings.Join(n.Roles, ","))
+		fmt.Fprintf(&sb, "   Hot threads at %s, interval=500ms, busiestThreads=5, ignoreIdleThreads=true:\n\n",
+			c.Now.UTC().Format("2006-01-02T15:04:05.000Z"))
+
+		for _, s := range hotStacks {
+			percent := float64(n.CPUPercent*s.share) / 100
+			if percent == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "   %.1f%% [cpu=%.1f%%, other=0.0%%] (%.1fms out of 500ms) cpu usage by thread 'elasticsearch[%s][%s][T#1]'\n",
+				percent, percent, percent*5, n.Name, s.pool)
+			if s.pool == "write" {
+				fmt.Fprintf(&sb, "     9/10 snapshots sharing following %d elements\n", len(s.frames))
+				writeFrames(&sb, s.frames)
+				sb.WriteString("     unique snapshot\n")
+				writeFrames(&sb, s.frames[1:])
+				continue
+			}
+			fmt.Fprintf(&sb, "     10/10 snapshots sharing following %d elements\n", len(s.frames))
+			writeFrames(&sb, s.frames)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// writeFrames 写入调用栈并以空行结束
+func writeFrames(sb *strings.Builder, frames []string) {
+	for _, f := range frames {
+		sb.WriteString("       " + f + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// containsNode 节点是否在逗号分隔的节点 ID / 名称列表中
+func containsNode(c *Cluster, nodeIDs string, n *Node) bool {
+	for _, id := range strings.Split(nodeIDs, ",") {
+		if c.Node(id) == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package fakees
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// ILMState 索引的 ILM 执行状态
+type ILMState struct {
+	Policy     string
+	Phase      string
+	Action     string
+	Step       string // 出错时为 ERROR
+	PhaseSince time.Time
+	StepSince  time.Time
+
+	FailedStep string // 出错的步骤
+	Reason     string // 出错原因
+
+	// 热阶段 rollover 动作的滚动条件（为空表示未设置）
+	RolloverMaxAge              string
+	RolloverMaxPrimaryShardSize string
+}
+
+// ilmExplain /<index>/_ilm/explain 响应（包含全部索引，未受管理的索引只有 managed=false）
+func (c *Cluster) ilmExplain() model.ILMExplain {
+	result := model.ILMExplain{Indices: make(map[string]model.ILMIndex, len(c.Indices))}
+	for _, idx := range c.Indices {
+		state := idx.ILM
+		if state == nil {
+			result.Indices[idx.Name] = model.ILMIndex{Index: idx.Name}
+			continue
+		}
+
+		entry := model.ILMIndex{
+			Index:                   idx.Name,
+			Managed:                 true,
+			Policy:                  state.Policy,
+			IndexCreationDateMillis: idx.CreatedAt.UnixMilli(),
+			LifecycleDateMillis:     idx.CreatedAt.UnixMilli(),
+			Phase:                   state.Phase,
+			PhaseTimeMillis:         state.PhaseSince.UnixMilli(),
+			Action:                  state.Action,
+			ActionTimeMillis:        state.StepSince.UnixMilli(),
+			Step:                    state.Step,
+			StepTimeMillis:          state.StepSince.UnixMilli(),
+			FailedStep:              state.FailedStep,
+			PhaseExecution:          &model.ILMPhaseExecution{Policy: state.Policy, Version: 1},
+		}
+		if state.Step == "ERROR" {
+			entry.StepInfo = &model.ILMStepInfo{Type: "illegal_argument_exception", Reason: state.Reason}
+		}
+		if state.Phase == "hot" && (state.RolloverMaxAge != "" || state.RolloverMaxPrimaryShardSize != "") {
+			entry.PhaseExecution.PhaseDefinition.Actions.Rollover = &model.RolloverConditions{
+				MaxAge:              state.RolloverMaxAge,
+				MaxPrimaryShardSize: state.RolloverMaxPrimaryShardSize,
+			}
+		}
+		result.Indices[idx.Name] = entry
+	}
+	return result
+}
+
+// lifecycleExplain /<index>/_lifecycle/explain 响应（数据流生命周期，ES 8.11+）
+func (c *Cluster) lifecycleExplain() model.DataStreamLifecycleExplain {
+	result := model.DataStreamLifecycleExplain{Indices: make(map[string]model.DataStreamLifecycleIndex, len(c.Indices))}
+	for _, idx := range c.Indices {
+		entry := model.DataStreamLifecycleIndex{Index: idx.Name, ManagedByLifecycle: idx.DSLManaged}
+		if idx.DSLManaged {
+			entry.IndexCreationDateMillis = idx.CreatedAt.UnixMilli()
+			entry.Lifecycle.Enabled = true
+			entry.Lifecycle.DataRetention = "7d"
+			if idx.DSLError != "" {
+				entry.Error = fmt.Sprintf(`{"type":"validation_exception","reason":%q}`, idx.DSLError)
+			}
+		}
+		result.Indices[idx.Name] = entry
+	}
+	return result
+}
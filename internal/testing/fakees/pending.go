@@ -0,0 +1,40 @@
+package fakees
+
+import (
+	"fmt"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// pendingSources 模拟任务来源（轮流使用）
+var pendingSources = []string{
+	"put-mapping [%s]",
+	"shard-started StartedShardEntry{shardId [[%s][0]]}",
+	"update-settings [[%s]]",
+	"create-index [%s], cause [auto(bulk api)]",
+}
+
+// pendingTasks /_cluster/pending_tasks 响应
+// 最早的任务从 PendingSince 开始排队并处于执行中，其余任务的排队时间依次缩短
+func (c *Cluster) pendingTasks() model.PendingTasks {
+	result := model.PendingTasks{Tasks: make([]model.PendingTask, 0, c.PendingTasks)}
+	oldest := c.Now.Sub(c.PendingSince).Milliseconds()
+	for i := 0; i < c.PendingTasks; i++ {
+		index := "fake-index"
+		if len(c.Indices) > 0 {
+			index = c.Indices[i%len(c.Indices)].Name
+		}
+		priority := "NORMAL"
+		if i%len(pendingSources) == 0 {
+			priority = "URGENT"
+		}
+		result.Tasks = append(result.Tasks, model.PendingTask{
+			InsertOrder:       int64(i + 1),
+			Priority:          priority,
+			Source:            fmt.Sprintf(pendingSources[i%len(pendingSources)], index),
+			Executing:         i == 0,
+			TimeInQueueMillis: oldest * int64(c.PendingTasks-i) / int64(c.PendingTasks),
+		})
+	}
+	return result
+}
@@ -0,0 +1,562 @@
+package fakees
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/pkg/util"
+)
+
+// Action 对集群状态的一次修改
+type Action func(c *Cluster) error
+
+// Step 场景中的一步：在第 Tick 个周期（从 1 开始）执行 Action
+type Step struct {
+	Tick   int
+	Name   string
+	Action Action
+}
+
+// Scenario 场景脚本（按周期执行的步骤列表）
+type Scenario []Step
+
+// At 在指定周期执行动作
+func At(tick int, name string, action Action) Step {
+	return Step{Tick: tick, Name: name, Action: action}
+}
+
+// Every 在 [from, to] 范围内的每个周期执行动作
+func Every(from, to int, name string, action Action) Scenario {
+	steps := make(Scenario, 0, to-from+1)
+	for tick := from; tick <= to; tick++ {
+		steps = append(steps, At(tick, name, action))
+	}
+	return steps
+}
+
+// NodeLeaves 节点离开集群
+func NodeLeaves(name string) Action {
+	return func(c *Cluster) error {
+		for i, n := range c.Nodes {
+			if n.ID == name || n.Name == name {
+				c.Nodes = append(c.Nodes[:i], c.Nodes[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("节点不存在: %s", name)
+	}
+}
+
+// NodeJoins 节点加入集群
+func NodeJoins(node *Node) Action {
+	return func(c *Cluster) error {
+		if c.Node(node.ID) != nil {
+			return fmt.Errorf("节点已存在: %s", node.ID)
+		}
+		if node.StartedAt.IsZero() {
+			node.StartedAt = c.Now
+		}
+		c.Nodes = append(c.Nodes, node)
+		return nil
+	}
+}
+
+// HeapChange 调整节点堆内存使用率（限制在 0-100）
+func HeapChange(name string, delta int) Action {
+	return withNode(name, func(n *Node) {
+		n.HeapUsedPercent = util.Max(0, util.Min(100, n.HeapUsedPercent+delta))
+	})
+}
+
+// CPUChange 调整节点 CPU 使用率（限制在 0-100）
+func CPUChange(name string, delta int) Action {
+	return withNode(name, func(n *Node) {
+		n.CPUPercent = util.Max(0, util.Min(100, n.CPUPercent+delta))
+	})
+}
+
+// DiskFree 设置节点磁盘剩余空间百分比
+func DiskFree(name string, percent int) Action {
+	return withNode(name, func(n *Node) {
+		n.DiskFreeBytes = n.DiskTotalBytes * int64(percent) / 100
+	})
+}
+
+// CounterReset 节点重启：累计计数器归零，运行时间重新计算
+func CounterReset(name string) Action {
+	return func(c *Cluster) error {
+		return withNode(name, func(n *Node) {
+			n.IndexTotal = 0
+			n.QueryTotal = 0
+			n.Rejected = nil
+			n.Tripped = nil
+			n.StartedAt = c.Now
+		})(c)
+	}
+}
+
+// NodeTraffic 设置节点每个周期的写入和查询增量
+func NodeTraffic(name string, indexRate, queryRate int) Action {
+	return withNode(name, func(n *Node) {
+		n.IndexRate = indexRate
+		n.QueryRate = queryRate
+	})
+}
+
+// Rejections 设置节点线程池每个周期新增的拒绝数
+func Rejections(name, pool string, perTick int64) Action {
+	return withNode(name, func(n *Node) {
+		if n.RejectRate == nil {
+			n.RejectRate = make(map[string]int64)
+		}
+		n.RejectRate[pool] = perTick
+	})
+}
+
+// BreakerTrips 节点断路器触发指定次数
+func BreakerTrips(name, breaker string, count int64) Action {
+	return withNode(name, func(n *Node) {
+		if n.Tripped == nil {
+			n.Tripped = make(map[string]int64)
+		}
+		n.Tripped[breaker] += count
+	})
+}
+
+// IndexTraffic 设置索引每个周期的写入和查询增量
+func IndexTraffic(name string, indexRate, queryRate int) Action {
+	return func(c *Cluster) error {
+		idx := c.Index(name)
+		if idx == nil {
+			return fmt.Errorf("索引不存在: %s", name)
+		}
+		idx.IndexRate = indexRate
+		idx.QueryRate = queryRate
+		return nil
+	}
+}
+
+// Segments 设置索引每个分片的段数
+func Segments(name string, perShard int) Action {
+	return func(c *Cluster) error {
+		idx := c.Index(name)
+		if idx == nil {
+			return fmt.Errorf("索引不存在: %s", name)
+		}
+		idx.SegmentsPerShard = perShard
+		return nil
+	}
+}
+
+// ShardsUnassigned 设置未分配分片数（primaries 为其中的主分片数）
+func ShardsUnassigned(total, primaries int) Action {
+	return func(c *Cluster) error {
+		if primaries > total {
+			return fmt.Errorf("未分配主分片数 %d 大于未分配分片总数 %d", primaries, total)
+		}
+		c.UnassignedShards = total
+		c.UnassignedPrimaries = primaries
+		return nil
+	}
+}
+
+// PendingTasks 设置主节点待处理任务数（队列从空变为非空时开始计算排队时间）
+func PendingTasks(count int) Action {
+	return func(c *Cluster) error {
+		if count < 0 {
+			return fmt.Errorf("待处理任务数不能为负数: %d", count)
+		}
+		if c.PendingTasks == 0 && count > 0 {
+			c.PendingSince = c.Now
+		}
+		c.PendingTasks = count
+		return nil
+	}
+}
+
+// TaskStarts 在节点上启动一个长时间运行的可取消任务（如 reindex、update_by_query）
+func TaskStarts(action, node string, children int) Action {
+	return func(c *Cluster) error {
+		if c.Node(node) == nil {
+			return fmt.Errorf("节点不存在: %s", node)
+		}
+		id := int64(1000)
+		for _, t := range c.Tasks {
+			id = max(id, t.ID+1)
+		}
+		c.Tasks = append(c.Tasks, &Task{
+			ID:          id,
+			Node:        node,
+			Action:      action,
+			Description: fmt.Sprintf("%s [fake-source] to [fake-dest]", action),
+			Cancellable: true,
+			Children:    children,
+			StartedAt:   c.Now,
+		})
+		return nil
+	}
+}
+
+// TaskEnds 结束指定动作的全部任务
+func TaskEnds(action string) Action {
+	return func(c *Cluster) error {
+		remaining := c.Tasks[:0]
+		for _, t := range c.Tasks {
+			if t.Action != action {
+				remaining = append(remaining, t)
+			}
+		}
+		if len(remaining) == len(c.Tasks) {
+			return fmt.Errorf("没有运行中的任务: %s", action)
+		}
+		c.Tasks = remaining
+		return nil
+	}
+}
+
+// ILMStep 索引进入 ILM 的指定阶段、动作和步骤（清除出错状态，进入新阶段时重新计算阶段时间）
+func ILMStep(name, phase, action, step string) Action {
+	return withILM(name, func(c *Cluster, state *ILMState) {
+		if state.Phase != phase {
+			state.PhaseSince = c.Now
+		}
+		state.Phase, state.Action, state.Step = phase, action, step
+		state.StepSince = c.Now
+		state.FailedStep, state.Reason = "", ""
+	})
+}
+
+// ILMError 索引的 ILM 步骤执行失败，进入 ERROR 步骤
+func ILMError(name, step string) Action {
+	return withILM(name, func(c *Cluster, state *ILMState) {
+		state.FailedStep = step
+		state.Reason = fmt.Sprintf("fake failure in step [%s] for index [%s]", step, name)
+		state.Step = "ERROR"
+		state.StepSince = c.Now
+	})
+}
+
+// ILMRollover 设置索引热阶段的 max_age 滚动条件
+func ILMRollover(name, maxAge string) Action {
+	return withILM(name, func(c *Cluster, state *ILMState) {
+		state.RolloverMaxAge = maxAge
+	})
+}
+
+// DSLError 索引改由数据流生命周期管理，并记录一次执行失败
+func DSLError(name string) Action {
+	return func(c *Cluster) error {
+		idx := c.Index(name)
+		if idx == nil {
+			return fmt.Errorf("索引不存在: %s", name)
+		}
+		idx.ILM = nil
+		idx.DSLManaged = true
+		idx.DSLError = fmt.Sprintf("this action would add [2] shards, but this cluster currently has [%d]/[%d] maximum normal shards open", 1000, 1000)
+		return nil
+	}
+}
+
+// SnapshotStarts 快照策略开始执行一次快照
+func SnapshotStarts(id string) Action {
+	return withSnapshotPolicy(id, func(c *Cluster, p *SnapshotPolicy) error {
+		if p.Running != nil {
+			return fmt.Errorf("快照策略 %s 已有正在执行的快照", id)
+		}
+		p.Running = &SnapshotRun{Name: snapshotName(c, id), StartedAt: c.Now}
+		p.ShardsDone = 0
+		return nil
+	})
+}
+
+// SnapshotSucceeds 快照策略正在执行的快照成功（没有正在执行的快照时视为立即完成的一次快照）
+func SnapshotSucceeds(id string) Action {
+	return withSnapshotPolicy(id, func(c *Cluster, p *SnapshotPolicy) error {
+		p.LastSuccess = finishSnapshot(c, p)
+		p.Taken++
+		return nil
+	})
+}
+
+// SnapshotFails 快照策略正在执行的快照失败（没有正在执行的快照时视为立即失败的一次快照）
+func SnapshotFails(id string) Action {
+	return withSnapshotPolicy(id, func(c *Cluster, p *SnapshotPolicy) error {
+		p.LastFailure = finishSnapshot(c, p)
+		p.FailureReason = fmt.Sprintf("[%s:%s] failed to write snapshot metadata: repository is read-only", p.Repository, p.LastFailure.Name)
+		p.Failed++
+		return nil
+	})
+}
+
+// SnapshotAge 快照策略的上次成功改为 age 之前（用于模拟长时间没有成功的快照）
+func SnapshotAge(id string, age time.Duration) Action {
+	return withSnapshotPolicy(id, func(c *Cluster, p *SnapshotPolicy) error {
+		if p.LastSuccess == nil {
+			return fmt.Errorf("快照策略 %s 从未成功", id)
+		}
+		duration := p.LastSuccess.EndedAt.Sub(p.LastSuccess.StartedAt)
+		p.LastSuccess.EndedAt = c.Now.Add(-age)
+		p.LastSuccess.StartedAt = p.LastSuccess.EndedAt.Add(-duration)
+		return nil
+	})
+}
+
+// FailRequests 之后 times 次路径前缀匹配的请求返回指定状态码
+func FailRequests(prefix string, status, times int) Action {
+	return func(c *Cluster) error {
+		c.failures = append(c.failures, failure{prefix: prefix, status: status, remaining: times})
+		return nil
+	}
+}
+
+// withNode 对指定节点执行修改
+func withNode(name string, fn func(n *Node)) Action {
+	return func(c *Cluster) error {
+		n := c.Node(name)
+		if n == nil {
+			return fmt.Errorf("节点不存在: %s", name)
+		}
+		fn(n)
+		return nil
+	}
+}
+
+// withSnapshotPolicy 对指定快照策略执行修改
+func withSnapshotPolicy(id string, fn func(c *Cluster, p *SnapshotPolicy) error) Action {
+	return func(c *Cluster) error {
+		p := c.SnapshotPolicy(id)
+		if p == nil {
+			return fmt.Errorf("快照策略不存在: %s", id)
+		}
+		return fn(c, p)
+	}
+}
+
+// finishSnapshot 结束正在执行的快照并返回本次执行
+func finishSnapshot(c *Cluster, p *SnapshotPolicy) *SnapshotRun {
+	run := p.Running
+	if run == nil {
+		run = &SnapshotRun{Name: snapshotName(c, p.ID), StartedAt: c.Now}
+	}
+	run.EndedAt = c.Now
+	p.Running = nil
+	return run
+}
+
+// snapshotName 按当前时间生成快照名称，如 nightly-snapshots-2025.01.01-000020
+func snapshotName(c *Cluster, id string) string {
+	return id + "-" + c.Now.Format("2006.01.02-150405")
+}
+
+// withILM 对指定索引的 ILM 状态执行修改（索引不受 ILM 管理时开始管理）
+func withILM(name string, fn func(c *Cluster, state *ILMState)) Action {
+	return func(c *Cluster) error {
+		idx := c.Index(name)
+		if idx == nil {
+			return fmt.Errorf("索引不存在: %s", name)
+		}
+		if idx.ILM == nil {
+			idx.ILM = &ILMState{Policy: "default", PhaseSince: c.Now, StepSince: c.Now}
+			idx.DSLManaged, idx.DSLError = false, ""
+		}
+		fn(c, idx.ILM)
+		return nil
+	}
+}
+
+// ParseScenario 解析文本场景脚本
+// 每行格式为 "<周期> <动作> [参数...]"，周期可写作范围 "3-6"，# 开头为注释：
+//
+//	1-5 heap node-1 +10        # 堆内存每周期上升 10%
+//	3   cpu node-2 +30
+//	4   disk-free node-3 5     # 磁盘剩余 5%
+//	6   node-leaves node-2
+//	6   unassigned 6 0         # 6 个副本分片未分配
+//	7   pending-tasks 40       # 主节点积压 40 个任务
+//	7   task-start indices:data/write/reindex node-1 3 # 启动带 3 个子任务的 reindex
+//	12  task-end indices:data/write/reindex
+//	8   counter-reset node-1   # 节点重启，计数器归零
+//	9   node-traffic node-1 0 0
+//	9   index-traffic logs-2025.01.01 5000 0
+//	9   segments logs-2025.01.01 300 # 每个分片 300 个段
+//	9   ilm-step metrics warm/shrink/check-shrink-allocation
+//	10  ilm-error metrics shrink   # shrink 步骤失败，进入 ERROR
+//	10  ilm-rollover logs-2025.01.01 1h # 热阶段 max_age 改为 1h
+//	10  dsl-error users            # 数据流生命周期执行失败
+//	11  snapshot-start nightly-snapshots
+//	13  snapshot-fail nightly-snapshots
+//	13  snapshot-age nightly-snapshots 48h # 上次成功改为 48 小时前
+//	15  snapshot-success nightly-snapshots
+//	9   rejections node-1 write 50 # write 线程池每周期拒绝 50 次
+//	11  breaker-trip node-2 parent 3
+//	10  fail /_nodes/stats 503 2
+func ParseScenario(r io.Reader) (Scenario, error) {
+	var scenario Scenario
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("第 %d 行: 缺少动作", lineNo)
+		}
+
+		from, to, err := parseTickRange(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+		}
+		action, err := parseAction(fields[1], fields[2:])
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+		}
+		scenario = append(scenario, Every(from, to, strings.Join(fields[1:], " "), action)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+// parseTickRange 解析 "3" 或 "3-6"
+func parseTickRange(s string) (from, to int, err error) {
+	lo, hi, isRange := strings.Cut(s, "-")
+	from, err = strconv.Atoi(lo)
+	if err != nil || from < 1 {
+		return 0, 0, fmt.Errorf("无效的周期: %s", s)
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	to, err = strconv.Atoi(hi)
+	if err != nil || to < from {
+		return 0, 0, fmt.Errorf("无效的周期范围: %s", s)
+	}
+	return from, to, nil
+}
+
+// actionArgs 各动作的参数个数
+var actionArgs = map[string]int{
+	"node-leaves":      1,
+	"counter-reset":    1,
+	"pending-tasks":    1,
+	"task-end":         1,
+	"dsl-error":        1,
+	"snapshot-start":   1,
+	"snapshot-success": 1,
+	"snapshot-fail":    1,
+	"snapshot-age":     2,
+	"ilm-step":         2,
+	"ilm-error":        2,
+	"ilm-rollover":     2,
+	"task-start":       3,
+	"heap":             2,
+	"cpu":              2,
+	"disk-free":        2,
+	"segments":         2,
+	"unassigned":       2,
+	"node-traffic":     3,
+	"index-traffic":    3,
+	"rejections":       3,
+	"breaker-trip":     3,
+	"fail":             3,
+}
+
+// parseAction 解析动作名和参数
+func parseAction(name string, args []string) (Action, error) {
+	n, ok := actionArgs[name]
+	if !ok {
+		return nil, fmt.Errorf("未知动作: %s", name)
+	}
+	if len(args) != n {
+		return nil, fmt.Errorf("动作 %s 需要 %d 个参数", name, n)
+	}
+
+	// 除名称参数外均为整数（unassigned、pending-tasks 没有名称参数，rejections、breaker-trip、task-start 有两个，
+	// 生命周期和快照动作全部为字符串参数）
+	numStart := 1
+	switch name {
+	case "unassigned", "pending-tasks":
+		numStart = 0
+	case "rejections", "breaker-trip", "task-start":
+		numStart = 2
+	case "dsl-error", "ilm-step", "ilm-error", "ilm-rollover",
+		"snapshot-start", "snapshot-success", "snapshot-fail", "snapshot-age":
+		numStart = n
+	}
+	nums := make([]int, 0, n)
+	for _, s := range args[numStart:] {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("无效的数值: %s", s)
+		}
+		nums = append(nums, v)
+	}
+
+	switch name {
+	case "node-leaves":
+		return NodeLeaves(args[0]), nil
+	case "counter-reset":
+		return CounterReset(args[0]), nil
+	case "heap":
+		return HeapChange(args[0], nums[0]), nil
+	case "cpu":
+		return CPUChange(args[0], nums[0]), nil
+	case "disk-free":
+		return DiskFree(args[0], nums[0]), nil
+	case "segments":
+		return Segments(args[0], nums[0]), nil
+	case "unassigned":
+		return ShardsUnassigned(nums[0], nums[1]), nil
+	case "pending-tasks":
+		return PendingTasks(nums[0]), nil
+	case "task-start":
+		return TaskStarts(args[0], args[1], nums[0]), nil
+	case "task-end":
+		return TaskEnds(args[0]), nil
+	case "ilm-step":
+		parts := strings.Split(args[1], "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("ILM 步骤格式应为 阶段/动作/步骤: %s", args[1])
+		}
+		return ILMStep(args[0], parts[0], parts[1], parts[2]), nil
+	case "ilm-error":
+		return ILMError(args[0], args[1]), nil
+	case "ilm-rollover":
+		return ILMRollover(args[0], args[1]), nil
+	case "dsl-error":
+		return DSLError(args[0]), nil
+	case "snapshot-start":
+		return SnapshotStarts(args[0]), nil
+	case "snapshot-success":
+		return SnapshotSucceeds(args[0]), nil
+	case "snapshot-fail":
+		return SnapshotFails(args[0]), nil
+	case "snapshot-age":
+		age, err := time.ParseDuration(args[1])
+		if err != nil || age <= 0 {
+			return nil, fmt.Errorf("无效的时长: %s", args[1])
+		}
+		return SnapshotAge(args[0], age), nil
+	case "node-traffic":
+		return NodeTraffic(args[0], nums[0], nums[1]), nil
+	case "index-traffic":
+		return IndexTraffic(args[0], nums[0], nums[1]), nil
+	case "rejections":
+		return Rejections(args[0], args[1], int64(nums[0])), nil
+	case "breaker-trip":
+		return BreakerTrips(args[0], args[1], int64(nums[0])), nil
+	default: // fail
+		return FailRequests(args[0], nums[0], nums[1]), nil
+	}
+}
@@ -0,0 +1,325 @@
+package fakees
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runScript 解析脚本并按周期顺序直接作用于默认集群（不经过服务端）
+func runScript(t *testing.T, script string) *Cluster {
+	t.Helper()
+	scenario, err := ParseScenario(strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("解析场景失败: %v", err)
+	}
+	c := DefaultCluster()
+	for _, step := range scenario {
+		if err := step.Action(c); err != nil {
+			t.Fatalf("执行 %s 失败: %v", step.Name, err)
+		}
+	}
+	return c
+}
+
+func TestParseScenarioActions(t *testing.T) {
+	tests := []struct {
+		script string
+		check  func(c *Cluster) error
+	}{
+		{"1 node-leaves node-2", func(c *Cluster) error {
+			if c.Node("node-2") != nil || len(c.Nodes) != 2 {
+				return fmt.Errorf("节点数 = %d, node-2 仍在集群中", len(c.Nodes))
+			}
+			return nil
+		}},
+		{"1 counter-reset node-1", func(c *Cluster) error {
+			if n := c.Node("node-1"); n.IndexTotal != 0 || n.QueryTotal != 0 || !n.StartedAt.Equal(c.Now) {
+				return fmt.Errorf("计数器 = %d/%d, 启动时间 = %s", n.IndexTotal, n.QueryTotal, n.StartedAt)
+			}
+			return nil
+		}},
+		{"1 heap node-1 +10\n2 heap node-1 +70", func(c *Cluster) error {
+			if p := c.Node("node-1").HeapUsedPercent; p != 100 {
+				return fmt.Errorf("堆内存 = %d%%, 期望限制在 100%%", p)
+			}
+			return nil
+		}},
+		{"1 cpu node-2 -30", func(c *Cluster) error {
+			if p := c.Node("node-2").CPUPercent; p != 0 {
+				return fmt.Errorf("CPU = %d%%, 期望限制在 0%%", p)
+			}
+			return nil
+		}},
+		{"1 disk-free node-3 5", func(c *Cluster) error {
+			if n := c.Node("node-3"); n.DiskFreeBytes != n.DiskTotalBytes*5/100 {
+				return fmt.Errorf("磁盘剩余 = %d", n.DiskFreeBytes)
+			}
+			return nil
+		}},
+		{"1 segments metrics 300", func(c *Cluster) error {
+			if n := c.Index("metrics").SegmentsPerShard; n != 300 {
+				return fmt.Errorf("每分片段数 = %d", n)
+			}
+			return nil
+		}},
+		{"1 unassigned 6 2", func(c *Cluster) error {
+			if c.UnassignedShards != 6 || c.UnassignedPrimaries != 2 || c.status() != "red" {
+				return fmt.Errorf("未分配 = %d/%d, 状态 = %s", c.UnassignedShards, c.UnassignedPrimaries, c.status())
+			}
+			return nil
+		}},
+		{"1 pending-tasks 40", func(c *Cluster) error {
+			if c.PendingTasks != 40 || !c.PendingSince.Equal(c.Now) {
+				return fmt.Errorf("待处理任务 = %d, 开始于 %s", c.PendingTasks, c.PendingSince)
+			}
+			return nil
+		}},
+		{"1 task-start indices:data/write/reindex node-1 3", func(c *Cluster) error {
+			if len(c.Tasks) != 1 || c.Tasks[0].Node != "node-1" || c.Tasks[0].Children != 3 || !c.Tasks[0].Cancellable {
+				return fmt.Errorf("任务 = %+v", c.Tasks)
+			}
+			return nil
+		}},
+		{"1 task-start indices:data/write/reindex node-1 3\n2 task-end indices:data/write/reindex", func(c *Cluster) error {
+			if len(c.Tasks) != 0 {
+				return fmt.Errorf("剩余任务 = %d", len(c.Tasks))
+			}
+			return nil
+		}},
+		{"1 ilm-step metrics warm/shrink/check-shrink-allocation", func(c *Cluster) error {
+			if s := c.Index("metrics").ILM; s == nil || s.Phase != "warm" || s.Action != "shrink" || s.Step != "check-shrink-allocation" {
+				return fmt.Errorf("ILM 状态 = %+v", s)
+			}
+			return nil
+		}},
+		{"1 ilm-error logs-2025.01.01 rollover", func(c *Cluster) error {
+			if s := c.Index("logs-2025.01.01").ILM; s.Step != "ERROR" || s.FailedStep != "rollover" || s.Reason == "" {
+				return fmt.Errorf("ILM 状态 = %+v", s)
+			}
+			return nil
+		}},
+		{"1 ilm-rollover logs-2025.01.01 1h", func(c *Cluster) error {
+			if age := c.Index("logs-2025.01.01").ILM.RolloverMaxAge; age != "1h" {
+				return fmt.Errorf("max_age = %s", age)
+			}
+			return nil
+		}},
+		{"1 dsl-error users", func(c *Cluster) error {
+			if idx := c.Index("users"); !idx.DSLManaged || idx.DSLError == "" || idx.ILM != nil {
+				return fmt.Errorf("索引状态 = %+v", idx)
+			}
+			return nil
+		}},
+		{"1 snapshot-start nightly-snapshots", func(c *Cluster) error {
+			if p := c.SnapshotPolicy("nightly-snapshots"); p.Running == nil {
+				return fmt.Errorf("没有正在执行的快照")
+			}
+			return nil
+		}},
+		{"1 snapshot-start nightly-snapshots\n2 snapshot-success nightly-snapshots", func(c *Cluster) error {
+			if p := c.SnapshotPolicy("nightly-snapshots"); p.Running != nil || p.Taken != 31 || !p.LastSuccess.EndedAt.Equal(c.Now) {
+				return fmt.Errorf("快照策略 = %+v", p)
+			}
+			return nil
+		}},
+		{"1 snapshot-fail nightly-snapshots", func(c *Cluster) error {
+			if p := c.SnapshotPolicy("nightly-snapshots"); p.LastFailure == nil || p.Failed != 1 || p.FailureReason == "" {
+				return fmt.Errorf("快照策略 = %+v", p)
+			}
+			return nil
+		}},
+		{"1 snapshot-age nightly-snapshots 48h", func(c *Cluster) error {
+			if p := c.SnapshotPolicy("nightly-snapshots"); !p.LastSuccess.EndedAt.Equal(c.Now.Add(-48 * time.Hour)) {
+				return fmt.Errorf("上次成功 = %s", p.LastSuccess.EndedAt)
+			}
+			return nil
+		}},
+		{"1 node-traffic node-1 0 50", func(c *Cluster) error {
+			if n := c.Node("node-1"); n.IndexRate != 0 || n.QueryRate != 50 {
+				return fmt.Errorf("速率 = %d/%d", n.IndexRate, n.QueryRate)
+			}
+			return nil
+		}},
+		{"1 index-traffic users 5000 0", func(c *Cluster) error {
+			if idx := c.Index("users"); idx.IndexRate != 5000 || idx.QueryRate != 0 {
+				return fmt.Errorf("速率 = %d/%d", idx.IndexRate, idx.QueryRate)
+			}
+			return nil
+		}},
+		{"1 rejections node-1 write 50", func(c *Cluster) error {
+			if r := c.Node("node-1").RejectRate["write"]; r != 50 {
+				return fmt.Errorf("每周期拒绝 = %d", r)
+			}
+			return nil
+		}},
+		{"1 breaker-trip node-2 parent 3\n2 breaker-trip node-2 parent 2", func(c *Cluster) error {
+			if n := c.Node("node-2").Tripped["parent"]; n != 5 {
+				return fmt.Errorf("断路器触发 = %d, 期望累加为 5", n)
+			}
+			return nil
+		}},
+		{"1 fail /_nodes/stats 503 2", func(c *Cluster) error {
+			for i := 0; i < 2; i++ {
+				if status, ok := c.takeFailure("/_nodes/stats/jvm"); !ok || status != 503 {
+					return fmt.Errorf("第 %d 次请求未注入失败", i+1)
+				}
+			}
+			if _, ok := c.takeFailure("/_nodes/stats"); ok {
+				return fmt.Errorf("注入次数用完后仍失败")
+			}
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Fields(tt.script)[1], func(t *testing.T) {
+			if err := tt.check(runScript(t, tt.script)); err != nil {
+				t.Errorf("%q: %v", tt.script, err)
+			}
+		})
+	}
+}
+
+func TestParseScenarioTicks(t *testing.T) {
+	scenario, err := ParseScenario(strings.NewReader(`
+# 注释行和空行被忽略
+
+3     heap node-1 +5   # 行尾注释
+5-7   cpu node-2 +10
+2-2   pending-tasks 1
+`))
+	if err != nil {
+		t.Fatalf("解析场景失败: %v", err)
+	}
+
+	want := []struct {
+		tick int
+		name string
+	}{
+		{3, "heap node-1 +5"},
+		{5, "cpu node-2 +10"},
+		{6, "cpu node-2 +10"},
+		{7, "cpu node-2 +10"},
+		{2, "pending-tasks 1"},
+	}
+	if len(scenario) != len(want) {
+		t.Fatalf("步骤数 = %d, 期望 %d", len(scenario), len(want))
+	}
+	for i, w := range want {
+		if scenario[i].Tick != w.tick || scenario[i].Name != w.name {
+			t.Errorf("步骤 %d = (%d, %q), 期望 (%d, %q)", i, scenario[i].Tick, scenario[i].Name, w.tick, w.name)
+		}
+	}
+
+	// At / Every 与脚本等价
+	every := Every(5, 7, "cpu", CPUChange("node-2", 10))
+	if len(every) != 3 || every[0].Tick != 5 || every[2].Tick != 7 {
+		t.Errorf("Every(5, 7) = %+v", every)
+	}
+	if step := At(4, "heap", HeapChange("node-1", 1)); step.Tick != 4 || step.Name != "heap" {
+		t.Errorf("At(4) = %+v", step)
+	}
+}
+
+func TestParseScenarioErrors(t *testing.T) {
+	tests := []struct {
+		script string
+		want   string
+	}{
+		{"1", "第 1 行: 缺少动作"},
+		{"# 注释\n1 heap node-1 +5\nx heap node-1 +5", "第 3 行: 无效的周期: x"},
+		{"0 heap node-1 +5", "无效的周期: 0"},
+		{"5-3 heap node-1 +5", "无效的周期范围: 5-3"},
+		{"2-x heap node-1 +5", "无效的周期范围"},
+		{"1 explode node-1", "未知动作: explode"},
+		{"1 heap node-1", "动作 heap 需要 2 个参数"},
+		{"1 node-leaves", "动作 node-leaves 需要 1 个参数"},
+		{"1 heap node-1 ten", "无效的数值: ten"},
+		{"1 unassigned six 0", "无效的数值: six"},
+		{"1 rejections node-1 write many", "无效的数值: many"},
+		{"1 ilm-step metrics warm/shrink", "阶段/动作/步骤"},
+		{"1 snapshot-age nightly-snapshots yesterday", "无效的时长"},
+		{"1 snapshot-age nightly-snapshots -1h", "无效的时长"},
+	}
+	for _, tt := range tests {
+		_, err := ParseScenario(strings.NewReader(tt.script))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: 错误 = %v, 期望包含 %q", tt.script, err, tt.want)
+		}
+	}
+}
+
+func TestParsedScenarioThroughServer(t *testing.T) {
+	scenario, err := ParseScenario(strings.NewReader(`
+1-3 heap node-1 +10
+2   unassigned 2 0
+3   fail /_cluster/health 503 1
+4   node-leaves node-3
+5   task-end nothing-running
+`))
+	if err != nil {
+		t.Fatalf("解析场景失败: %v", err)
+	}
+	s := New(nil, scenario)
+	defer s.Close()
+
+	health := func() (int, map[string]interface{}) {
+		resp, err := http.Get(s.URL() + "/_cluster/health")
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	type want struct {
+		heap   int
+		status string
+		code   int
+		nodes  float64
+	}
+	expected := []want{
+		{50, "green", 200, 3},
+		{60, "yellow", 200, 3},
+		{70, "", 503, 0}, // 注入的失败只影响一次请求
+		{70, "yellow", 200, 2},
+	}
+	for i, w := range expected {
+		if err := s.Advance(); err != nil {
+			t.Fatalf("周期 %d: 推进失败: %v", i+1, err)
+		}
+		if s.Tick() != i+1 || !s.Now().Equal(clusterEpoch.Add(time.Duration(i+1)*2*time.Second)) {
+			t.Errorf("周期 %d: Tick = %d, Now = %s", i+1, s.Tick(), s.Now())
+		}
+		var heap int
+		s.Update(func(c *Cluster) { heap = c.Node("node-1").HeapUsedPercent })
+		if heap != w.heap {
+			t.Errorf("周期 %d: node-1 堆内存 = %d%%, 期望 %d%%", i+1, heap, w.heap)
+		}
+		code, body := health()
+		if code != w.code {
+			t.Errorf("周期 %d: 状态码 = %d, 期望 %d", i+1, code, w.code)
+			continue
+		}
+		if code == 503 {
+			if code, _ = health(); code != 200 {
+				t.Errorf("周期 %d: 注入次数用完后状态码 = %d, 期望 200", i+1, code)
+			}
+			continue
+		}
+		if body["status"] != w.status || body["number_of_nodes"] != w.nodes {
+			t.Errorf("周期 %d: 健康状态 = %v/%v 个节点, 期望 %s/%v", i+1, body["status"], body["number_of_nodes"], w.status, w.nodes)
+		}
+	}
+
+	// 动作执行失败时 Advance 返回带周期和动作名的错误
+	err = s.Advance()
+	if err == nil || !strings.Contains(err.Error(), "周期 5 执行 task-end nothing-running 失败") {
+		t.Errorf("错误 = %v, 期望指出周期 5 的 task-end 失败", err)
+	}
+}
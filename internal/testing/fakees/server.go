@@ -0,0 +1,252 @@
+// Package fakees 提供基于 httptest 的模拟 Elasticsearch 服务端，用于端到端测试
+//
+// 服务端根据 Cluster 状态生成 /、/_cluster/health、/_health_report、/_nodes/stats、
+// /_nodes/http、/_stats、/_cat/indices、/_cat/shards、/_cluster/allocation/explain
+// /_cluster/pending_tasks、/_tasks、/<index>/_ilm/explain、/<index>/_lifecycle/explain、
+// /_slm/policy、/_slm/stats 和 /_snapshot/_status 响应，
+// 以及 /_nodes/hot_threads 文本响应，结构与 model 包一致。
+// 与真实集群一样按 filter_path 过滤响应，_cat 响应按 h 选择列、未指定 bytes=b 时字节数带单位，
+// _nodes/stats 和 _stats 带有 _nodes、_shards 和 _all 等模型之外的字段。
+// 场景脚本按周期修改集群状态（节点离开、堆内存上升、分片未分配、计数器归零等），
+// 每调用一次 Advance 前进一个周期，结果完全可重复。
+package fakees
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server 模拟 Elasticsearch 服务端
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	cluster  *Cluster
+	scenario Scenario
+	tick     int
+	requests []string
+}
+
+// New 启动模拟服务端，cluster 为空时使用 DefaultCluster
+func New(cluster *Cluster, scenario Scenario) *Server {
+	if cluster == nil {
+		cluster = DefaultCluster()
+	}
+	s := &Server{cluster: cluster, scenario: scenario}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL 返回服务端地址（如 http://127.0.0.1:12345）
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Close 关闭服务端
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Advance 前进一个周期：推进时钟和计数器，然后执行该周期的场景步骤
+func (s *Server) Advance() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tick++
+	s.cluster.advance()
+	for _, step := range s.scenario {
+		if step.Tick != s.tick {
+			continue
+		}
+		if err := step.Action(s.cluster); err != nil {
+			return fmt.Errorf("周期 %d 执行 %s 失败: %w", s.tick, step.Name, err)
+		}
+	}
+	return nil
+}
+
+// Tick 返回当前周期（初始为 0）
+func (s *Server) Tick() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tick
+}
+
+// Now 返回模拟时钟
+func (s *Server) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cluster.Now
+}
+
+// Update 直接修改集群状态
+func (s *Server) Update(fn func(c *Cluster)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.cluster)
+}
+
+// Requests 返回收到的全部请求（方法 + URI）
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// handle 按路径生成响应
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" 不受支持")
+		return
+	}
+	if status, ok := s.cluster.takeFailure(r.URL.Path); ok {
+		writeError(w, status, "injected_failure", "场景注入的失败")
+		return
+	}
+
+	c := s.cluster
+	path := r.URL.Path
+	var body interface{}
+	switch {
+	case path == "/":
+		body = c.info()
+	case path == "/_cluster/health":
+		body = c.health()
+	case path == "/_health_report":
+		body = c.healthReport()
+	case path == "/_nodes/http":
+		body = s.nodesHTTP()
+	case path == "/_nodes/hot_threads" || strings.HasPrefix(path, "/_nodes/") && strings.HasSuffix(path, "/hot_threads"):
+		nodeIDs := strings.TrimSuffix(strings.TrimPrefix(path, "/_nodes/"), "hot_threads")
+		writeText(w, r, c.hotThreads(strings.TrimSuffix(nodeIDs, "/")))
+		return
+	case path == "/_nodes/stats" || strings.HasPrefix(path, "/_nodes/stats/"):
+		body = withFields(c.nodeStats(), map[string]interface{}{
+			"_nodes":       map[string]int{"total": len(c.Nodes), "successful": len(c.Nodes), "failed": 0},
+			"cluster_name": c.Name,
+		})
+	case path == "/_stats" || strings.HasPrefix(path, "/_stats/"):
+		shards := c.totalShards()
+		body = withFields(c.indexStats(), map[string]interface{}{
+			"_shards": map[string]int{"total": shards, "successful": shards - c.UnassignedShards, "failed": 0},
+			"_all":    c.indexStatsAll(),
+		})
+	case path == "/_cat/indices":
+		body = catRows(c.catIndices(), r.URL.Query())
+	case path == "/_cat/shards" || strings.HasPrefix(path, "/_cat/shards/"):
+		body = catRows(c.catShards(strings.TrimPrefix(strings.TrimPrefix(path, "/_cat/shards"), "/")), r.URL.Query())
+	case path == "/_tasks":
+		body = c.tasks()
+	case path == "/_cluster/pending_tasks":
+		body = c.pendingTasks()
+	case strings.HasSuffix(path, "/_ilm/explain"):
+		body = c.ilmExplain()
+	case strings.HasSuffix(path, "/_lifecycle/explain"):
+		body = c.lifecycleExplain()
+	case path == "/_slm/policy":
+		body = c.slmPolicies()
+	case path == "/_slm/stats":
+		body = c.slmStats()
+	case path == "/_snapshot/_status":
+		body = c.snapshotStatus()
+	case path == "/_cluster/allocation/explain":
+		var status int
+		body, status = c.allocationExplain(r.URL.Query())
+		writeJSON(w, r, status, body)
+		return
+	default:
+		writeError(w, http.StatusNotFound, "resource_not_found_exception", "fakees 未实现: "+path)
+		return
+	}
+	if filter := r.URL.Query().Get("filter_path"); filter != "" {
+		body = filterResponse(body, filter)
+	}
+	writeJSON(w, r, http.StatusOK, body)
+}
+
+// nodesHTTP /_nodes/http 响应：第一个节点发布服务端自身地址，保证节点发现后仍可访问
+func (s *Server) nodesHTTP() interface{} {
+	u, _ := url.Parse(s.srv.URL)
+	type httpInfo struct {
+		PublishAddress string `json:"publish_address"`
+	}
+	nodes := make(map[string]map[string]httpInfo, len(s.cluster.Nodes))
+	for i, n := range s.cluster.Nodes {
+		if i == 0 {
+			nodes[n.ID] = map[string]httpInfo{"http": {PublishAddress: n.Name + "/" + u.Host}}
+			continue
+		}
+		nodes[n.ID] = map[string]httpInfo{}
+	}
+	return map[string]interface{}{"nodes": nodes}
+}
+
+// takeFailure 检查是否有匹配的注入失败
+func (c *Cluster) takeFailure(path string) (int, bool) {
+	for i := range c.failures {
+		f := &c.failures[i]
+		if f.remaining > 0 && strings.HasPrefix(path, f.prefix) {
+			f.remaining--
+			return f.status, true
+		}
+	}
+	return 0, false
+}
+
+// writeJSON 写入 JSON 响应（客户端接受 gzip 时压缩）
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	gz := gzip.NewWriter(w)
+	defer gz.Close()
+	json.NewEncoder(gz).Encode(body)
+}
+
+// writeText 写入纯文本响应（客户端接受 gzip 时压缩）
+func writeText(w http.ResponseWriter, r *http.Request, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		io.WriteString(w, body)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzip.NewWriter(w)
+	defer gz.Close()
+	io.WriteString(gz, body)
+}
+
+// writeError 写入 ES 风格的错误响应
+func writeError(w http.ResponseWriter, status int, errType, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorBody(status, errType, reason))
+}
+
+// errorBody ES 风格的错误响应体
+func errorBody(status int, errType, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"error":  map[string]string{"type": errType, "reason": reason},
+		"status": status,
+	}
+}
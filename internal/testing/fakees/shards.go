@@ -0,0 +1,150 @@
+package fakees
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// shards 按索引和数据节点生成分片分布：各分片副本轮流分配到数据节点，
+// 前 UnassignedPrimaries 个主分片和前 UnassignedShards-UnassignedPrimaries 个副本为未分配
+func (c *Cluster) shards() []model.ShardInfo {
+	var dataNodes []*Node
+	for _, n := range c.Nodes {
+		if hasDataRole(n.Roles) {
+			dataNodes = append(dataNodes, n)
+		}
+	}
+
+	unassignedPrimaries := c.UnassignedPrimaries
+	unassignedReplicas := c.UnassignedShards - c.UnassignedPrimaries
+	slot := 0
+
+	var result []model.ShardInfo
+	for _, idx := range c.Indices {
+		if idx.Status != "open" {
+			continue
+		}
+		for shard := 0; shard < idx.Primaries; shard++ {
+			for replica := 0; replica <= idx.Replicas; replica++ {
+				s := model.ShardInfo{
+					Index:  idx.Name,
+					Shard:  strconv.Itoa(shard),
+					PriRep: "r",
+					State:  "STARTED",
+					Docs:   strconv.Itoa(idx.Docs / idx.Primaries),
+					Store:  strconv.FormatInt(idx.StoreBytes/int64(idx.Primaries), 10),
+				}
+				if replica == 0 {
+					s.PriRep = "p"
+				}
+
+				switch {
+				case replica == 0 && unassignedPrimaries > 0:
+					unassignedPrimaries--
+					s.State = "UNASSIGNED"
+					s.UnassignedReason = "NODE_LEFT"
+				case replica > 0 && unassignedReplicas > 0:
+					unassignedReplicas--
+					s.State = "UNASSIGNED"
+					s.UnassignedReason = "NODE_LEFT"
+				case len(dataNodes) > 0:
+					s.Node = dataNodes[slot%len(dataNodes)].Name
+					slot++
+				}
+				if s.State == "UNASSIGNED" {
+					s.Docs, s.Store = "", ""
+					s.UnassignedAt = c.Now.Format("2006-01-02T15:04:05.000Z")
+					s.UnassignedDetails = "node_left [fake-node]"
+				}
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// catShards /_cat/shards[/{index}] 响应（索引名支持逗号分隔的通配符）
+func (c *Cluster) catShards(pattern string) []model.ShardInfo {
+	all := c.shards()
+	if pattern == "" {
+		return all
+	}
+	result := make([]model.ShardInfo, 0, len(all))
+	for _, s := range all {
+		for _, p := range strings.Split(pattern, ",") {
+			if ok, _ := path.Match(p, s.Index); ok {
+				result = append(result, s)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// allocationExplain /_cluster/allocation/explain 响应
+// 未指定分片时解释第一个未分配分片；没有未分配分片时返回 400（与 ES 一致）
+func (c *Cluster) allocationExplain(query url.Values) (interface{}, int) {
+	var target *model.ShardInfo
+	for _, s := range c.shards() {
+		if query.Get("index") != "" {
+			if s.Index == query.Get("index") && s.Shard == query.Get("shard") &&
+				s.Primary() == (query.Get("primary") == "true") {
+				target = &s
+				break
+			}
+			continue
+		}
+		if s.State == "UNASSIGNED" {
+			target = &s
+			break
+		}
+	}
+	if target == nil {
+		return errorBody(http.StatusBadRequest, "illegal_argument_exception",
+			"there are no unassigned shards in this cluster"), http.StatusBadRequest
+	}
+
+	shard, _ := strconv.Atoi(target.Shard)
+	explain := model.AllocationExplain{
+		Index:        target.Index,
+		Shard:        shard,
+		Primary:      target.Primary(),
+		CurrentState: strings.ToLower(target.State),
+	}
+	if target.State != "UNASSIGNED" {
+		return explain, http.StatusOK
+	}
+
+	explain.UnassignedInfo.Reason = target.UnassignedReason
+	explain.UnassignedInfo.At = target.UnassignedAt
+	explain.UnassignedInfo.Details = target.UnassignedDetails
+	if target.Primary() {
+		explain.UnassignedInfo.LastAllocationStatus = "no_valid_shard_copy"
+		explain.CanAllocate = "no_valid_shard_copy"
+		explain.AllocateExplanation = "Elasticsearch can't allocate this shard because there are no copies of its data in the cluster."
+		return explain, http.StatusOK
+	}
+
+	explain.UnassignedInfo.LastAllocationStatus = "no_attempt"
+	explain.CanAllocate = "no"
+	explain.AllocateExplanation = "Elasticsearch isn't allowed to allocate this shard to any of the nodes in the cluster."
+	for _, n := range c.Nodes {
+		if !hasDataRole(n.Roles) {
+			continue
+		}
+		decision := model.NodeAllocationDecision{NodeName: n.Name, NodeDecision: "no"}
+		decision.Deciders = append(decision.Deciders, model.AllocationDecider{
+			Decider:     "same_shard",
+			Decision:    "NO",
+			Explanation: fmt.Sprintf("a copy of this shard is already allocated to this node [[%s][%d]]", target.Index, shard),
+		})
+		explain.NodeAllocationDecisions = append(explain.NodeAllocationDecisions, decision)
+	}
+	return explain, http.StatusOK
+}
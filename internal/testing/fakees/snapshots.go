@@ -0,0 +1,133 @@
+package fakees
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// SnapshotPolicy 模拟快照生命周期策略
+type SnapshotPolicy struct {
+	ID         string
+	Repository string
+	Schedule   string    // cron 表达式（UTC）
+	ModifiedAt time.Time // 策略修改时间
+	NextRunAt  time.Time // 下一次计划执行时间
+
+	LastSuccess   *SnapshotRun // 为空表示从未成功
+	LastFailure   *SnapshotRun // 为空表示从未失败
+	FailureReason string
+	Taken         int64
+	Failed        int64
+
+	Running      *SnapshotRun // 正在执行的快照
+	ShardsPerRun int          // 每次快照的分片数
+	BytesPerRun  int64        // 每次快照需要复制的数据量
+	ShardsDone   int          // 正在执行的快照已完成的分片数（每个周期完成一个）
+}
+
+// SnapshotRun 一次快照执行
+type SnapshotRun struct {
+	Name      string
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// advanceSnapshots 正在执行的快照每个周期完成一个分片
+func (c *Cluster) advanceSnapshots() {
+	for _, p := range c.SnapshotPolicies {
+		if p.Running != nil && p.ShardsDone < p.ShardsPerRun {
+			p.ShardsDone++
+		}
+	}
+}
+
+// SnapshotPolicy 按 ID 查找快照策略
+func (c *Cluster) SnapshotPolicy(id string) *SnapshotPolicy {
+	for _, p := range c.SnapshotPolicies {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// slmPolicies /_slm/policy 响应
+func (c *Cluster) slmPolicies() model.SLMPolicies {
+	result := make(model.SLMPolicies, len(c.SnapshotPolicies))
+	for _, p := range c.SnapshotPolicies {
+		entry := model.SLMPolicy{
+			Version:             1,
+			ModifiedDateMillis:  p.ModifiedAt.UnixMilli(),
+			NextExecutionMillis: p.NextRunAt.UnixMilli(),
+		}
+		entry.Policy.Name = "<" + p.ID + "-{now/d}>"
+		entry.Policy.Schedule = p.Schedule
+		entry.Policy.Repository = p.Repository
+		if p.LastSuccess != nil {
+			entry.LastSuccess = &model.SLMInvocation{
+				SnapshotName: p.LastSuccess.Name,
+				StartTime:    p.LastSuccess.StartedAt.UnixMilli(),
+				Time:         p.LastSuccess.EndedAt.UnixMilli(),
+			}
+		}
+		if p.LastFailure != nil {
+			entry.LastFailure = &model.SLMInvocation{
+				SnapshotName: p.LastFailure.Name,
+				StartTime:    p.LastFailure.StartedAt.UnixMilli(),
+				Time:         p.LastFailure.EndedAt.UnixMilli(),
+				Details:      fmt.Sprintf(`{"type":"snapshot_exception","reason":%q}`, p.FailureReason),
+			}
+		}
+		if p.Running != nil {
+			entry.InProgress = &model.SLMInProgress{
+				Name:            p.Running.Name,
+				UUID:            "uuid-" + p.Running.Name,
+				State:           "STARTED",
+				StartTimeMillis: p.Running.StartedAt.UnixMilli(),
+			}
+		}
+		entry.Stats.SnapshotsTaken = p.Taken
+		entry.Stats.SnapshotsFailed = p.Failed
+		result[p.ID] = entry
+	}
+	return result
+}
+
+// slmStats /_slm/stats 响应（全部策略的累计值）
+func (c *Cluster) slmStats() model.SLMStats {
+	stats := model.SLMStats{RetentionRuns: int64(len(c.SnapshotPolicies))}
+	for _, p := range c.SnapshotPolicies {
+		stats.TotalSnapshotsTaken += p.Taken
+		stats.TotalSnapshotsFailed += p.Failed
+	}
+	return stats
+}
+
+// snapshotStatus /_snapshot/_status 响应（只包含正在执行的快照）
+func (c *Cluster) snapshotStatus() model.SnapshotStatus {
+	result := model.SnapshotStatus{Snapshots: []model.SnapshotProgress{}}
+	for _, p := range c.SnapshotPolicies {
+		if p.Running == nil {
+			continue
+		}
+		s := model.SnapshotProgress{
+			Snapshot:   p.Running.Name,
+			Repository: p.Repository,
+			UUID:       "uuid-" + p.Running.Name,
+			State:      "STARTED",
+		}
+		s.ShardsStats.Done = p.ShardsDone
+		s.ShardsStats.Started = p.ShardsPerRun - p.ShardsDone
+		s.ShardsStats.Total = p.ShardsPerRun
+		if p.ShardsPerRun > 0 {
+			s.Stats.Processed.SizeInBytes = p.BytesPerRun * int64(p.ShardsDone) / int64(p.ShardsPerRun)
+		}
+		s.Stats.Total.SizeInBytes = p.BytesPerRun
+		s.Stats.StartTimeInMillis = p.Running.StartedAt.UnixMilli()
+		s.Stats.TimeInMillis = c.Now.Sub(p.Running.StartedAt).Milliseconds()
+		result.Snapshots = append(result.Snapshots, s)
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package fakees
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Y-vQv-Y/es-monitor/internal/model"
+)
+
+// Task 模拟运行中的顶层任务
+type Task struct {
+	ID          int64
+	Node        string // 节点名称
+	Action      string
+	Description string
+	Cancellable bool
+	Children    int // 子任务数（分布在各数据节点上）
+	StartedAt   time.Time
+}
+
+// tasks /_tasks?detailed&group_by=parents 响应
+// 除场景中的任务外，总是包含列出任务的请求自身（与 ES 一致）
+func (c *Cluster) tasks() model.Tasks {
+	result := model.Tasks{Tasks: make(map[string]model.TaskGroup, len(c.Tasks)+1)}
+	if len(c.Nodes) > 0 {
+		self := model.TaskGroup{Task: model.Task{
+			Node:              c.Nodes[0].ID,
+			ID:                1,
+			Type:              "transport",
+			Action:            "cluster:monitor/tasks/lists",
+			StartTimeInMillis: c.Now.UnixMilli(),
+		}}
+		result.Tasks[self.Node+":1"] = self
+	}
+
+	for _, t := range c.Tasks {
+		node := c.Node(t.Node)
+		if node == nil {
+			continue
+		}
+		group := model.TaskGroup{Task: model.Task{
+			Node:               node.ID,
+			ID:                 t.ID,
+			Type:               "transport",
+			Action:             t.Action,
+			Description:        t.Description,
+			StartTimeInMillis:  t.StartedAt.UnixMilli(),
+			RunningTimeInNanos: c.Now.Sub(t.StartedAt).Nanoseconds(),
+			Cancellable:        t.Cancellable,
+		}}
+		for i := 0; i < t.Children; i++ {
+			group.Children = append(group.Children, model.TaskChild{
+				Node:   c.Nodes[i%len(c.Nodes)].ID,
+				ID:     t.ID*100 + int64(i),
+				Action: t.Action + "[s]",
+			})
+		}
+		result.Tasks[fmt.Sprintf("%s:%d", node.ID, t.ID)] = group
+	}
+	return result
+}